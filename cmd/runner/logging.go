@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// newLogger builds the service logger. format selects the handler: "json"
+// (structured, for Loki/observability pipelines) or "text" (logfmt-style
+// key=value lines, easier to read locally).
+func newLogger(w io.Writer, format, logLevel string) (*slog.Logger, error) {
+	var level slog.Level
+	switch logLevel {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: level,
+		// Add source location for debugging
+		AddSource: level == slog.LevelDebug,
+	}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unsupported log format %q (want text or json)", format)
+	}
+
+	return slog.New(handler).With("service", "guidellm-runner"), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "json", "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("hello", "target", "llama-7b")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "hello" || entry["target"] != "llama-7b" || entry["service"] != "guidellm-runner" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
+
+func TestNewLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "text", "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("hello", "target", "llama-7b")
+
+	// Each attribute is a key=value pair
+	fields := map[string]string{}
+	for _, field := range strings.Fields(strings.TrimSpace(buf.String())) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			t.Fatalf("expected key=value pairs, got %q", buf.String())
+		}
+		fields[key] = value
+	}
+	if fields["msg"] != "hello" || fields["target"] != "llama-7b" || fields["service"] != "guidellm-runner" {
+		t.Errorf("unexpected log fields: %v", fields)
+	}
+}
+
+func TestNewLoggerLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "json", "warn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("expected info to be filtered at warn level, got %q", buf.String())
+	}
+
+	if _, err := newLogger(&buf, "xml", "info"); err == nil {
+		t.Error("expected error for unsupported log format")
+	}
+}
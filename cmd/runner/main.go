@@ -4,45 +4,51 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/yourorg/guidellm-runner/internal/api"
 	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+	"github.com/yourorg/guidellm-runner/internal/results"
 	"github.com/yourorg/guidellm-runner/internal/runner"
+	"github.com/yourorg/guidellm-runner/internal/webhook"
 )
 
 func main() {
+	// Developer subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "parse":
+			os.Exit(runParse(os.Args[2:], os.Stdout, os.Stderr))
+		case "validate":
+			os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+		case "openapi":
+			os.Exit(runOpenAPI(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	// Parse flags
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	logFormat := flag.String("log-format", "json", "Log format (json, text)")
 	apiPort := flag.Int("api-port", 8080, "Port for the runtime control API")
+	metricsPort := flag.Int("metrics-port", 0, "Port for Prometheus metrics (overrides prometheus.port in config)")
 	autoStart := flag.Bool("auto-start", true, "Automatically start configured targets on startup")
+	dryRun := flag.Bool("dry-run", false, "Log the guidellm command for each target and exit without running anything")
+	guidellmBin := flag.String("guidellm-bin", "", "Path to the guidellm binary (overrides guidellm_binary in config)")
+	keepRaw := flag.Bool("keep-raw", false, "Keep each run's raw guidellm output in raw_output_dir (same as keep_raw_output in config)")
+	once := flag.Bool("once", false, "Run one benchmark for each target, then exit (non-zero if any run failed) instead of serving")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 0, "How long shutdown may take to drain runs and deliver results (overrides shutdown.timeout in config, default 30s)")
 	flag.Parse()
 
-	// Setup logger with JSON format for Loki/observability compatibility
-	var level slog.Level
-	switch *logLevel {
-	case "debug":
-		level = slog.LevelDebug
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
-	// Use JSON handler for structured logging (compatible with Loki)
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
-		// Add source location for debugging
-		AddSource: level == slog.LevelDebug,
-	})).With("service", "guidellm-runner")
+	// Setup logger. JSON is the default for Loki/observability compatibility.
+	logger, err := newLogger(os.Stdout, *logFormat, *logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	// Load configuration
 	cfg, err := config.Load(*configPath)
@@ -50,6 +56,34 @@ func main() {
 		logger.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
+	// Mistakes would otherwise only surface when guidellm runs
+	if !checkConfig(cfg, logger) {
+		os.Exit(1)
+	}
+
+	if *keepRaw {
+		cfg.KeepRawOutput = true
+	}
+	if *metricsPort != 0 {
+		cfg.Prometheus.Port = *metricsPort
+	}
+
+	// Resolve the guidellm binary once so a bad path fails fast at startup
+	if *guidellmBin != "" {
+		cfg.GuideLLMBinary = *guidellmBin
+	}
+	binaryPath, err := runner.ResolveBinary(cfg.GuideLLMBinary)
+	if err != nil {
+		if !*dryRun {
+			logger.Error("invalid guidellm binary", "error", err)
+			os.Exit(1)
+		}
+		// A dry run never executes guidellm, so just warn
+		logger.Warn("invalid guidellm binary", "error", err)
+	} else {
+		cfg.GuideLLMBinary = binaryPath
+		logger.Info("using guidellm binary", "path", binaryPath)
+	}
 
 	// Count targets
 	totalTargets := 0
@@ -57,6 +91,7 @@ func main() {
 		logger.Info("loaded environment", "name", envName, "targets", len(env.Targets))
 		totalTargets += len(env.Targets)
 	}
+	logger.Info("starting guidellm-runner", "version", version, "commit", commit, "build_date", buildDate)
 	logger.Info("configuration loaded",
 		"environments", len(cfg.Environments),
 		"total_targets", totalTargets,
@@ -73,38 +108,107 @@ func main() {
 
 	// Create target manager
 	manager := runner.NewTargetManager(cfg, logger)
+	manager.SetBuildInfo(version, commit, buildDate)
 
 	// Create runner with manager reference
 	r := runner.New(cfg, logger)
 	manager.SetRunner(r)
 
 	// Load targets from config
-	manager.LoadFromConfig()
+	if err := manager.LoadFromConfig(); err != nil {
+		logger.Error("invalid target configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Load targets from discovery if enabled. In background mode discovery
+	// is deferred until the configured targets have been started, except for
+	// dry runs which need the full target list up front.
+	backgroundDiscovery := cfg.Discovery.Background && !*dryRun && !*once
+	if !backgroundDiscovery {
+		if err := manager.LoadFromDiscovery(ctx); err != nil {
+			logger.Error("failed to load targets from discovery", "error", err)
+			// Continue with static targets on discovery failure
+		}
+	}
+
+	// In dry-run mode just show what would be executed
+	if *dryRun {
+		manager.DryRun()
+		return
+	}
+
+	// Check guidellm is a version the results parser is tested against, so
+	// an upgrade underneath us is reported up front rather than as parse
+	// failures
+	guidellmVersion, err := r.DetectVersion(ctx)
+	if err != nil {
+		logger.Warn("could not detect guidellm version", "error", err)
+	} else if err := runner.CheckGuideLLMVersion(guidellmVersion); err != nil {
+		if cfg.StrictGuideLLMVersion {
+			logger.Error("unsupported guidellm version", "version", guidellmVersion, "error", err)
+			os.Exit(1)
+		}
+		logger.Warn("untested guidellm version, results may fail to parse", "version", guidellmVersion, "error", err)
+	} else {
+		logger.Info("detected guidellm version", "version", guidellmVersion)
+	}
+
+	// Notify the configured webhook of completed runs
+	var notifier *webhook.Notifier
+	if cfg.Webhooks.URL != "" {
+		notifier = webhook.New(cfg.Webhooks, logger)
+		manager.SetNotifier(notifier)
+	}
+
+	// Store completed runs to the configured result sinks
+	var sinks []results.ResultSink
+	if cfg.ResultsDir != "" {
+		sinks = append(sinks, results.NewDirWriter(cfg.ResultsDir))
+	}
+	if cfg.ResultsS3.Bucket != "" {
+		s3, err := results.NewS3Sink(cfg.ResultsS3)
+		if err != nil {
+			logger.Error("invalid results_s3 configuration", "error", err)
+			os.Exit(1)
+		}
+		sinks = append(sinks, s3)
+	}
+	manager.SetResultSinks(sinks...)
+
+	// Shutdown, including that of a run interrupted in --once mode, must
+	// finish within the timeout
+	timeout := cfg.Shutdown.GetTimeout()
+	if *shutdownTimeout > 0 {
+		timeout = *shutdownTimeout
+	}
+
+	// Start Prometheus metrics server, with the configured histogram buckets
+	metrics.SetLatencyBuckets(cfg.Metrics.Buckets.TTFT, cfg.Metrics.Buckets.ITL, cfg.Metrics.Buckets.E2E)
+	metrics.SetTagLabels(cfg.Prometheus.TagLabels)
+
+	// Push metrics after each run too, for runs that exit before a scrape
+	if cfg.Prometheus.Pushgateway.URL != "" {
+		manager.SetPusher(metrics.NewPusher(cfg.Prometheus.Pushgateway.URL, cfg.Prometheus.Pushgateway.Job))
+		logger.Info("pushing metrics to pushgateway", "url", cfg.Prometheus.Pushgateway.URL, "job", cfg.Prometheus.Pushgateway.Job)
+	}
 
-	// Load targets from discovery if enabled
-	if err := manager.LoadFromDiscovery(ctx); err != nil {
-		logger.Error("failed to load targets from discovery", "error", err)
-		// Continue with static targets on discovery failure
+	// A one-off run exits once every target has run, so has nothing to serve
+	if *once {
+		os.Exit(runOnce(ctx, manager, sigChan, cfg.Shutdown, timeout, notifier, logger))
 	}
 
-	// Start Prometheus metrics server
+	metricsServer := metrics.NewServer(cfg.Prometheus.Port, cfg.Prometheus.Exemplars, logger)
 	go func() {
-		addr := fmt.Sprintf(":%d", cfg.Prometheus.Port)
-		logger.Info("starting prometheus metrics server", "addr", addr)
-		http.Handle("/metrics", promhttp.Handler())
-		http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("ok"))
-		})
-		if err := http.ListenAndServe(addr, nil); err != nil && err != http.ErrServerClosed {
+		if err := metricsServer.Start(); err != nil {
 			logger.Error("metrics server failed", "error", err)
 		}
 	}()
 
 	// Start API server
 	apiServer := api.NewServer(api.ServerConfig{
-		Port:   *apiPort,
-		Logger: logger,
+		Port:               *apiPort,
+		Logger:             logger,
+		CORSAllowedOrigins: cfg.API.CORSAllowedOrigins,
 	}, manager)
 
 	go func() {
@@ -119,26 +223,51 @@ func main() {
 		manager.StartAllConfigured(ctx)
 	}
 
+	// Discover remaining targets while the configured ones are already running
+	if backgroundDiscovery {
+		manager.StartDiscovery(ctx, *autoStart)
+	}
+
 	// Wait for shutdown signal
 	sig := <-sigChan
 	logger.Info("received shutdown signal", "signal", sig)
 	cancel()
 
 	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
 	defer shutdownCancel()
 
+	// Stop scheduling runs and give those in flight the drain grace to
+	// finish, then cancel the rest. This runs alongside the API shutdown,
+	// which waits for requests such as synchronous triggers that only
+	// return once their run does.
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		manager.Drain(shutdownCtx, cfg.Shutdown.GetDrainGrace(timeout))
+	}()
+
 	// Stop API server
 	if err := apiServer.Shutdown(shutdownCtx); err != nil {
 		logger.Error("API server shutdown failed", "error", err)
 	}
+	<-drained
 
-	// Stop all targets
-	manager.StopAll()
-
-	// Wait for all benchmark runs to complete
+	// Wait for all benchmark runs to wind down
 	logger.Info("waiting for benchmark runs to complete")
 	manager.Wait()
 
+	// Deliver the webhook events of the final runs
+	if notifier != nil {
+		if err := notifier.Close(shutdownCtx); err != nil {
+			logger.Error("webhook delivery did not finish", "error", err)
+		}
+	}
+
+	// Stop the metrics server last so scrapes still see the final runs
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics server shutdown failed", "error", err)
+	}
+
 	logger.Info("shutdown complete")
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/runner"
+	"github.com/yourorg/guidellm-runner/internal/webhook"
+)
+
+// runOnce implements --once: it runs every target once, delivers the
+// webhook events of the runs and returns the exit code, non-zero if any run
+// failed. A signal interrupts the runs as on a normal shutdown, draining
+// those in flight, and also fails the run.
+func runOnce(ctx context.Context, manager *runner.DefaultTargetManager, sigChan <-chan os.Signal, shutdown config.ShutdownConfig, timeout time.Duration, notifier *webhook.Notifier, logger *slog.Logger) int {
+	logger.Info("running each target once")
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.RunOnce(ctx)
+	}()
+
+	code := 0
+	var shutdownCtx context.Context
+	var shutdownCancel context.CancelFunc
+	select {
+	case err := <-done:
+		shutdownCtx, shutdownCancel = context.WithTimeout(context.Background(), timeout)
+		if err != nil {
+			logger.Error("run failed", "error", err)
+			code = 1
+		}
+	case sig := <-sigChan:
+		logger.Info("received shutdown signal", "signal", sig)
+		shutdownCtx, shutdownCancel = context.WithTimeout(context.Background(), timeout)
+		manager.Drain(shutdownCtx, shutdown.GetDrainGrace(timeout))
+		<-done
+		code = 1
+	}
+	defer shutdownCancel()
+
+	// Deliver the webhook events of the runs before exiting
+	if notifier != nil {
+		if err := notifier.Close(shutdownCtx); err != nil {
+			logger.Error("webhook delivery did not finish", "error", err)
+		}
+	}
+	return code
+}
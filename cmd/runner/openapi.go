@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+)
+
+// runOpenAPI implements `runner openapi`: it prints the OpenAPI description
+// of the control API, the same document served at /api/openapi.json, for
+// generating client SDKs without a running instance. Returns the process
+// exit code.
+func runOpenAPI(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(stderr, "Usage: runner openapi")
+		return 2
+	}
+
+	spec, err := api.OpenAPISpec()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to generate OpenAPI spec: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, string(spec))
+	return 0
+}
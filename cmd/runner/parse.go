@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// runParse implements `runner parse <file>`: it parses a guidellm JSON
+// output file and prints the ParsedResults the runner would record, so the
+// parser can be checked against a guidellm version before deploying.
+// Returns the process exit code.
+func runParse(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: runner parse <guidellm-output.json>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	results, err := parser.ParseFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to parse %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		fmt.Fprintf(stderr, "failed to encode results: %v\n", err)
+		return 1
+	}
+	return 0
+}
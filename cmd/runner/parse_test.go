@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+func TestRunParse(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runParse([]string{"testdata/benchmarks.json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	var results parser.ParsedResults
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("expected ParsedResults JSON, got %q: %v", stdout.String(), err)
+	}
+	if results.TotalRequests != 100 || results.SuccessfulRequests != 95 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestRunParseFailures(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"missing file argument", nil, 2},
+		{"nonexistent file", []string{"testdata/missing.json"}, 1},
+		{"invalid JSON", []string{invalid}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runParse(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("expected exit code %d, got %d", tt.code, code)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("expected no output on failure, got %q", stdout.String())
+			}
+		})
+	}
+}
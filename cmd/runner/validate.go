@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+// runValidate implements `runner validate -config <file>`: it loads the
+// config with defaults applied, prints every problem found and returns a
+// non-zero exit code if there were any. Nothing is started.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", *configPath, err)
+		return 1
+	}
+
+	errs := cfg.Validate()
+	if len(errs) > 0 {
+		fmt.Fprintf(stderr, "%s: %d problem(s) found\n", *configPath, len(errs))
+		for _, err := range errs {
+			fmt.Fprintf(stderr, "  - %v\n", err)
+		}
+		return 1
+	}
+
+	targets := 0
+	for _, env := range cfg.Environments {
+		targets += len(env.Targets)
+	}
+	fmt.Fprintf(stdout, "%s: ok (%d environments, %d targets)\n", *configPath, len(cfg.Environments), targets)
+	return 0
+}
+
+// checkConfig logs every problem Validate finds in the config the daemon
+// was started with, returning false if there were any
+func checkConfig(cfg *config.Config, logger *slog.Logger) bool {
+	errs := cfg.Validate()
+	for _, err := range errs {
+		logger.Error("invalid config", "error", err)
+	}
+	return len(errs) == 0
+}
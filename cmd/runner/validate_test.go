@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+func TestRunValidate(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runValidate([]string{"-config", "../../configs/config.example.yaml"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected example config to validate, got exit code %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ok") {
+		t.Errorf("expected ok report, got %q", stdout.String())
+	}
+}
+
+func TestRunValidateReportsProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := `
+defaults:
+  interval: -5
+environments:
+  develop:
+    targets:
+      - name: llama
+        url: http://dev:8000/v1
+        model: llama
+  staging:
+    targets:
+      - name: llama
+        url: http://staging:8000/v1
+        model: llama
+        profile: bursty
+      - name: empty
+        rate: 0
+`
+	if err := os.WriteFile(path, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runValidate([]string{"-config", path}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	report := stderr.String()
+	for _, want := range []string{
+		"defaults.interval must be positive",
+		"environments.staging.targets[0] (llama): duplicate target name, already used in environment develop",
+		`profile "bursty" is not one of`,
+		"environments.staging.targets[1] (empty): url is required",
+		"environments.staging.targets[1] (empty): model is required",
+		"environments.staging.targets[1] (empty): rate must be positive",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+// TestCheckConfig verifies that the daemon rejects at startup the same
+// mistakes `runner validate` reports
+func TestCheckConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := `
+environments:
+  staging:
+    targets:
+      - name: llama
+        url: http://staging:8000/v1
+        model: llama
+        request_type: text_completions
+        chat_formatter: text
+`
+	if err := os.WriteFile(path, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	if checkConfig(loaded, logger) {
+		t.Fatal("expected chat_formatter with text_completions to be rejected")
+	}
+	if !strings.Contains(logs.String(), "chat_formatter") {
+		t.Errorf("expected the problem to be logged, got:\n%s", logs.String())
+	}
+
+	example, err := config.Load("../../configs/config.example.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !checkConfig(example, logger) {
+		t.Errorf("expected example config to pass, got:\n%s", logs.String())
+	}
+}
+
+// TestCheckConfigTargets verifies that the per-target checks run at startup
+// for targets in the config file, not just for those added through the API
+func TestCheckConfigTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"reserved extra arg", "extra_args: [\"--rate\", \"10\"]", "--rate"},
+		{"sweep step without rate or profile", "sweep: [{}]", "sweep step 1"},
+		{"invalid tag key", "tags: {\"bad-key\": x}", "tag key"},
+		{"rate for synchronous profile", "profile: synchronous\n        rate: 5", "rate is not used"},
+		{"zero max_runs", "max_runs: 0", "max_runs must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			cfg := `
+environments:
+  staging:
+    targets:
+      - name: llama
+        url: http://staging:8000/v1
+        model: llama
+        ` + tt.target + "\n"
+			if err := os.WriteFile(path, []byte(cfg), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := config.Load(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var logs bytes.Buffer
+			if checkConfig(loaded, slog.New(slog.NewTextHandler(&logs, nil))) {
+				t.Fatal("expected the config to be rejected")
+			}
+			if !strings.Contains(logs.String(), tt.want) {
+				t.Errorf("expected %q to be logged, got:\n%s", tt.want, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is wrapped by TargetManager implementations when the named
+// target does not exist, so handlers can map the failure to a 404 from the
+// error itself instead of a separate (racy) existence check
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalid is wrapped by TargetManager implementations when a request's
+// parameters are invalid
+var ErrInvalid = errors.New("invalid")
+
+// ErrConflict is wrapped by TargetManager implementations when a request
+// clashes with existing state, e.g. a run ID that is already taken
+var ErrConflict = errors.New("conflict")
+
+// ValidationError is returned by TargetManager implementations for a request
+// with invalid fields, giving the problem with each by its JSON field name,
+// so clients can fix them all at once. It wraps ErrInvalid.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// Error lists the problems in field order. A single problem reads as it did
+// before errors were collected by field.
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = e.Fields[name]
+	}
+	return strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
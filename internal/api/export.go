@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// exportPercentiles are the latency percentiles included in exports
+var exportPercentiles = []float64{50, 90, 95, 99}
+
+// exportField is one flattened value of a run's results
+type exportField struct {
+	name  string // CSV column and Prometheus metric suffix
+	help  string
+	value float64
+}
+
+// flattenResults flattens a run's results into counts, throughput and
+// latency percentiles. Percentiles of metrics without samples are omitted.
+func flattenResults(results *parser.ParsedResults) []exportField {
+	fields := []exportField{
+		{"requests_total", "Requests made in the run", float64(results.TotalRequests)},
+		{"requests_successful", "Successful requests in the run", float64(results.SuccessfulRequests)},
+		{"requests_failed", "Failed requests in the run", float64(results.FailedRequests)},
+		{"prompt_tokens", "Prompt tokens sent in the run", float64(results.PromptTokens)},
+		{"output_tokens", "Output tokens received in the run", float64(results.OutputTokens)},
+		{"output_tokens_per_second", "Output tokens generated per second", results.OutputTokensPerSec},
+		{"requests_per_second", "Requests completed per second", results.RequestsPerSec},
+	}
+
+	for _, latency := range []struct {
+		name   string
+		help   string
+		values []float64
+	}{
+		{"ttft_seconds", "Time to first token in seconds", results.TTFTValues},
+		{"itl_seconds", "Inter-token latency in seconds", results.ITLValues},
+		{"e2e_latency_seconds", "End-to-end request latency in seconds", results.E2EValues},
+	} {
+		sorted := append([]float64(nil), latency.values...)
+		sort.Float64s(sorted)
+		for _, p := range exportPercentiles {
+			value := 0.0
+			if len(sorted) > 0 {
+				value = parser.Percentile(sorted, p)
+			}
+			fields = append(fields, exportField{
+				name:  fmt.Sprintf("%s_p%g", latency.name, p),
+				help:  fmt.Sprintf("%s, p%g", latency.help, p),
+				value: value,
+			})
+		}
+	}
+	return fields
+}
+
+// writeResultsCSV writes one CSV row per run, with a header row naming the
+// flattened fields. The header is written even when there are no runs.
+func writeResultsCSV(w io.Writer, name string, runs []RunResults) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"target", "timestamp"}
+	for _, field := range flattenResults(&parser.ParsedResults{}) {
+		header = append(header, field.name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		row := []string{name, run.At.UTC().Format(time.RFC3339)}
+		for _, field := range flattenResults(run.Results) {
+			row = append(row, strconv.FormatFloat(field.value, 'f', -1, 64))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeResultsProm writes the most recent run as Prometheus text
+// exposition, one gauge per flattened field timestamped with the run time.
+// Metrics are prefixed guidellm_result_ to keep them apart from the live
+// series the runner exports.
+func writeResultsProm(w io.Writer, name string, runs []RunResults) error {
+	if len(runs) == 0 {
+		return nil
+	}
+	run := runs[len(runs)-1]
+
+	for _, field := range flattenResults(run.Results) {
+		metric := "guidellm_result_" + field.name
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s{target=%q} %s %d\n",
+			metric, field.help, metric, metric, name,
+			strconv.FormatFloat(field.value, 'g', -1, 64), run.At.UnixMilli()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
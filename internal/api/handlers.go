@@ -3,8 +3,18 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/yourorg/guidellm-runner/internal/parser"
 )
@@ -12,15 +22,39 @@ import (
 // TargetManager interface for the handlers to use
 // This matches the interface in runner/manager.go
 type TargetManager interface {
-	AddTarget(ctx context.Context, req AddTargetRequest) error
+	AddTarget(ctx context.Context, req AddTargetRequest) (*TargetResponse, error)
 	RemoveTarget(name string) error
 	StartTarget(ctx context.Context, name string) error
 	StopTarget(name string) error
-	TriggerRun(ctx context.Context, name string, runID string) (*parser.ParsedResults, error)
-	ListTargets() []TargetResponse
+	TriggerRun(ctx context.Context, name string, runID string, overrides *RunOverrides) (*parser.ParsedResults, error)
+	StartRun(ctx context.Context, name string, runID string, overrides *RunOverrides) (*RunResponse, error)
+	GetRun(runID string) (*RunResponse, error)
+	ListRuns(target string, status RunStatus) []RunResponse
+	CompareRuns(name string, req CompareRequest) (*CompareResponse, error)
+	SetBaseline(name string, runID string) (*BaselineResponse, error)
+	GetBaseline(name string) (*BaselineResponse, error)
+	ClearBaseline(name string) error
+	ListTargets(filter TargetFilter) ([]TargetResponse, int)
 	GetTarget(name string) (*TargetResponse, bool)
 	GetStatus() StatusResponse
-	GetLatestResults(name string) (*parser.ParsedResults, bool)
+	GetVersion() VersionResponse
+	GetSummary() SummaryResponse
+	GetMetricsSnapshot() MetricsSnapshotResponse
+	GetConfig() (map[string]any, error)
+	GetTargetConfig(name string) (*TargetConfigResponse, error)
+	GetLatestResults(name string) (*ResultsResponse, error)
+	GetRawResults(name string) ([]byte, error)
+	SubscribeResults(name string) (<-chan *parser.ParsedResults, func(), error)
+	GetHistoryPercentiles(name string, window time.Duration) (*HistoryPercentilesResponse, error)
+	GetFailures(window time.Duration) (*FailuresResponse, error)
+	GetResultsHistory(name string, window time.Duration) ([]RunResults, error)
+	BulkAction(ctx context.Context, action string, names []string) (*BulkActionResponse, error)
+	GroupAction(ctx context.Context, group string, action string) (*BulkActionResponse, error)
+	GetGroupSummary(group string) (*GroupSummaryResponse, error)
+	SetOverride(name string, req OverrideRequest) (*TargetResponse, error)
+	ClearOverride(name string) (*TargetResponse, error)
+	PauseTarget(name string) (*TargetResponse, error)
+	ResumeTarget(name string) (*TargetResponse, error)
 	PauseScheduler() error
 	ResumeScheduler() error
 	GetSchedulerStatus() SchedulerStatusResponse
@@ -30,20 +64,78 @@ type TargetManager interface {
 type Handlers struct {
 	manager TargetManager
 	logger  *slog.Logger
+
+	// openAPI describes the routes the handlers are served on
+	openAPI *openAPISpec
+
+	// streamsDone is closed when the server shuts down, ending open result
+	// streams, which would otherwise hold up the shutdown indefinitely
+	streamsDone chan struct{}
+	closeOnce   sync.Once
 }
 
 // NewHandlers creates a new Handlers instance
 func NewHandlers(manager TargetManager, logger *slog.Logger) *Handlers {
 	return &Handlers{
-		manager: manager,
-		logger:  logger,
+		manager:     manager,
+		logger:      logger,
+		streamsDone: make(chan struct{}),
 	}
 }
 
-// ListTargets handles GET /api/targets
+// closeStreams ends every open result stream
+func (h *Handlers) closeStreams() {
+	h.closeOnce.Do(func() { close(h.streamsDone) })
+}
+
+// ListTargets handles GET /api/targets. Targets are sorted by name and can
+// be filtered by environment, status and a name substring (q), and paged
+// with limit and offset.
 func (h *Handlers) ListTargets(w http.ResponseWriter, r *http.Request) {
-	targets := h.manager.ListTargets()
-	h.respondJSON(w, http.StatusOK, ListTargetsResponse{Targets: targets})
+	query := r.URL.Query()
+	filter := TargetFilter{
+		Environment: query.Get("environment"),
+		Status:      TargetStatus(query.Get("status")),
+		Query:       query.Get("q"),
+	}
+	if filter.Status != "" && !slices.Contains(validTargetStatuses, filter.Status) {
+		h.respondError(w, http.StatusBadRequest, "invalid status", fmt.Sprintf("expected one of %v", validTargetStatuses))
+		return
+	}
+	for _, tag := range query["tag"] {
+		key, value, _ := strings.Cut(tag, "=")
+		if key == "" {
+			h.respondError(w, http.StatusBadRequest, "invalid tag", "expected key=value or key")
+			return
+		}
+		if filter.Tags == nil {
+			filter.Tags = make(map[string]string)
+		}
+		filter.Tags[key] = value
+	}
+	for _, param := range []struct {
+		name string
+		dest *int
+	}{{"limit", &filter.Limit}, {"offset", &filter.Offset}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			h.respondError(w, http.StatusBadRequest, "invalid "+param.name, "expected a non-negative integer")
+			return
+		}
+		*param.dest = n
+	}
+
+	targets, total := h.manager.ListTargets(filter)
+	h.respondJSON(w, http.StatusOK, ListTargetsResponse{
+		Targets: targets,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	})
 }
 
 // AddTarget handles POST /api/targets
@@ -54,17 +146,20 @@ func (h *Handlers) AddTarget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.manager.AddTarget(r.Context(), req); err != nil {
+	target, err := h.manager.AddTarget(r.Context(), req)
+	if err != nil {
+		h.log(r).Warn("add target failed", "name", req.Name, "error", err)
+		// Several invalid fields are reported together, by field; a single
+		// one keeps the plain 400 response
+		var verr *ValidationError
+		if errors.As(err, &verr) && len(verr.Fields) > 1 {
+			h.respondValidationError(w, verr)
+			return
+		}
 		h.respondError(w, http.StatusBadRequest, err.Error(), "")
 		return
 	}
 
-	target, ok := h.manager.GetTarget(req.Name)
-	if !ok {
-		h.respondError(w, http.StatusInternalServerError, "target added but not found", "")
-		return
-	}
-
 	h.respondJSON(w, http.StatusCreated, target)
 }
 
@@ -94,7 +189,7 @@ func (h *Handlers) RemoveTarget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.manager.RemoveTarget(name); err != nil {
-		h.respondError(w, http.StatusNotFound, err.Error(), "")
+		h.respondManagerError(w, err)
 		return
 	}
 
@@ -113,12 +208,7 @@ func (h *Handlers) StartTarget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.manager.StartTarget(r.Context(), name); err != nil {
-		// Check if it's a not found error
-		if _, ok := h.manager.GetTarget(name); !ok {
-			h.respondError(w, http.StatusNotFound, err.Error(), "")
-			return
-		}
-		h.respondError(w, http.StatusBadRequest, err.Error(), "")
+		h.respondManagerError(w, err)
 		return
 	}
 
@@ -138,12 +228,7 @@ func (h *Handlers) StopTarget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.manager.StopTarget(name); err != nil {
-		// Check if it's a not found error
-		if _, ok := h.manager.GetTarget(name); !ok {
-			h.respondError(w, http.StatusNotFound, err.Error(), "")
-			return
-		}
-		h.respondError(w, http.StatusBadRequest, err.Error(), "")
+		h.respondManagerError(w, err)
 		return
 	}
 
@@ -154,7 +239,141 @@ func (h *Handlers) StopTarget(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// StartAllTargets handles POST /api/targets/start-all
+func (h *Handlers) StartAllTargets(w http.ResponseWriter, r *http.Request) {
+	h.bulkAction(w, r, BulkActionStart, nil)
+}
+
+// StopAllTargets handles POST /api/targets/stop-all
+func (h *Handlers) StopAllTargets(w http.ResponseWriter, r *http.Request) {
+	h.bulkAction(w, r, BulkActionStop, nil)
+}
+
+// TargetActions handles POST /api/targets/actions, applying an action to
+// the listed targets
+func (h *Handlers) TargetActions(w http.ResponseWriter, r *http.Request) {
+	var req BulkActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	if len(req.Names) == 0 {
+		h.respondError(w, http.StatusBadRequest, "names is required", "use start-all or stop-all to act on every target")
+		return
+	}
+
+	h.bulkAction(w, r, req.Action, req.Names)
+}
+
+// bulkAction applies action to the named targets (all when names is empty)
+// and responds with the per-target outcomes
+func (h *Handlers) bulkAction(w http.ResponseWriter, r *http.Request, action string, names []string) {
+	// Started targets must outlive this request, which the manager ensures
+	resp, err := h.manager.BulkAction(r.Context(), action, names)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// StartGroup handles POST /api/groups/{name}/start, starting every target
+// currently in the group
+func (h *Handlers) StartGroup(w http.ResponseWriter, r *http.Request) {
+	h.groupAction(w, r, BulkActionStart)
+}
+
+// StopGroup handles POST /api/groups/{name}/stop
+func (h *Handlers) StopGroup(w http.ResponseWriter, r *http.Request) {
+	h.groupAction(w, r, BulkActionStop)
+}
+
+// groupAction applies action to a group's targets and responds with the
+// per-target outcomes
+func (h *Handlers) groupAction(w http.ResponseWriter, r *http.Request, action string) {
+	resp, err := h.manager.GroupAction(r.Context(), r.PathValue("name"), action)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// GetGroupSummary handles GET /api/groups/{name}/summary
+func (h *Handlers) GetGroupSummary(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.manager.GetGroupSummary(r.PathValue("name"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// SetOverride handles POST /api/targets/{name}/override
+func (h *Handlers) SetOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "target name is required", "")
+		return
+	}
+
+	var req OverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	target, err := h.manager.SetOverride(name, req)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, target)
+}
+
+// ClearOverride handles DELETE /api/targets/{name}/override
+func (h *Handlers) ClearOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "target name is required", "")
+		return
+	}
+
+	target, err := h.manager.ClearOverride(name)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, target)
+}
+
+// PauseTarget handles POST /api/targets/{name}/pause, pausing just that
+// target's scheduled runs
+func (h *Handlers) PauseTarget(w http.ResponseWriter, r *http.Request) {
+	target, err := h.manager.PauseTarget(r.PathValue("name"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, target)
+}
+
+// ResumeTarget handles POST /api/targets/{name}/resume
+func (h *Handlers) ResumeTarget(w http.ResponseWriter, r *http.Request) {
+	target, err := h.manager.ResumeTarget(r.PathValue("name"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, target)
+}
+
 // GetTargetResults handles GET /api/targets/{name}/results
+// With ?format=guidellm the archived raw guidellm JSON is returned instead
+// of the runner's parsed results
 func (h *Handlers) GetTargetResults(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 	if name == "" {
@@ -162,54 +381,360 @@ func (h *Handlers) GetTargetResults(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if target exists
-	if _, ok := h.manager.GetTarget(name); !ok {
-		h.respondError(w, http.StatusNotFound, "target not found", "")
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+	case "guidellm":
+		h.getRawResults(w, name)
+		return
+	case "csv", "prom":
+		h.exportResults(w, r, name, format)
+		return
+	default:
+		h.respondError(w, http.StatusBadRequest, "unsupported format", format)
+		return
+	}
+
+	resp, err := h.manager.GetLatestResults(name)
+	if err != nil {
+		h.respondManagerError(w, err)
 		return
 	}
+	if resp.Results == nil {
+		resp.Message = "no results available yet"
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// ExportResultsCSV handles GET /api/targets/{name}/results.csv
+func (h *Handlers) ExportResultsCSV(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "target name is required", "")
+		return
+	}
+
+	h.exportResults(w, r, name, "csv")
+}
+
+// exportResults writes a target's results as CSV or Prometheus text: the
+// latest run by default, or every run within ?window= from its history
+func (h *Handlers) exportResults(w http.ResponseWriter, r *http.Request, name, format string) {
+	var runs []RunResults
+	if r.URL.Query().Has("window") {
+		window, ok := h.parseWindow(w, r)
+		if !ok {
+			return
+		}
+		history, err := h.manager.GetResultsHistory(name, window)
+		if err != nil {
+			h.respondManagerError(w, err)
+			return
+		}
+		runs = history
+	} else {
+		latest, err := h.manager.GetLatestResults(name)
+		if err != nil {
+			h.respondManagerError(w, err)
+			return
+		}
+		if latest.Results != nil && latest.LastRunAt != nil {
+			runs = []RunResults{{At: *latest.LastRunAt, Results: latest.Results}}
+		}
+	}
+
+	var err error
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"-results.csv"))
+		w.WriteHeader(http.StatusOK)
+		err = writeResultsCSV(w, name, runs)
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		err = writeResultsProm(w, name, runs)
+	}
+	if err != nil {
+		h.log(r).Error("failed to write results export", "target", name, "format", format, "error", err)
+	}
+}
 
-	results, ok := h.manager.GetLatestResults(name)
+// defaultHistoryWindow is the window used when ?window= is not given
+const defaultHistoryWindow = time.Hour
+
+// GetHistoryPercentiles handles GET /api/targets/{name}/history/percentiles,
+// merging latency percentiles across runs within ?window= (default 1h)
+func (h *Handlers) GetHistoryPercentiles(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "target name is required", "")
+		return
+	}
+
+	window, ok := h.parseWindow(w, r)
 	if !ok {
-		h.respondJSON(w, http.StatusOK, map[string]interface{}{
-			"name":    name,
-			"results": nil,
-			"message": "no results available yet",
-		})
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+	resp, err := h.manager.GetHistoryPercentiles(name, window)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// CompareRuns handles GET /api/targets/{name}/compare, comparing the run
+// ?current= (default the latest) against ?baseline=, optionally with
+// ?max_throughput_drop= and ?max_latency_increase= percentage thresholds
+func (h *Handlers) CompareRuns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := CompareRequest{
+		Baseline: query.Get("baseline"),
+		Current:  query.Get("current"),
+	}
+	if req.Baseline == "" {
+		h.respondError(w, http.StatusBadRequest, "baseline is required", "expected the run ID of the baseline run")
+		return
+	}
+	for _, param := range []struct {
+		name string
+		dest **float64
+	}{{"max_throughput_drop", &req.MaxThroughputDrop}, {"max_latency_increase", &req.MaxLatencyIncrease}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 || math.IsNaN(v) {
+			h.respondError(w, http.StatusBadRequest, "invalid "+param.name, "expected a non-negative percentage")
+			return
+		}
+		*param.dest = &v
+	}
+
+	resp, err := h.manager.CompareRuns(r.PathValue("name"), req)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// SetBaseline handles POST /api/targets/{name}/baseline, pinning the run in
+// the optional body's run_id, or the latest run, as the target's baseline
+func (h *Handlers) SetBaseline(w http.ResponseWriter, r *http.Request) {
+	var req BaselineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	baseline, err := h.manager.SetBaseline(r.PathValue("name"), req.RunID)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, baseline)
+}
+
+// GetBaseline handles GET /api/targets/{name}/baseline
+func (h *Handlers) GetBaseline(w http.ResponseWriter, r *http.Request) {
+	baseline, err := h.manager.GetBaseline(r.PathValue("name"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, baseline)
+}
+
+// ClearBaseline handles DELETE /api/targets/{name}/baseline
+func (h *Handlers) ClearBaseline(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := h.manager.ClearBaseline(name); err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, map[string]string{
+		"message": "baseline cleared",
 		"name":    name,
-		"results": results,
 	})
 }
 
-// TriggerRun handles POST /api/targets/{name}/trigger
-func (h *Handlers) TriggerRun(w http.ResponseWriter, r *http.Request) {
+// GetFailures handles GET /api/failures, breaking down recent run failures
+// across all targets. The optional window query parameter (a duration such
+// as 30m or 24h, default 1h) bounds how far back failures are counted.
+func (h *Handlers) GetFailures(w http.ResponseWriter, r *http.Request) {
+	window, ok := h.parseWindow(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.manager.GetFailures(window)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// parseWindow reads the window query parameter, defaulting to
+// defaultHistoryWindow. On an invalid window it responds with 400 and
+// returns false.
+func (h *Handlers) parseWindow(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	raw := r.URL.Query().Get("window")
+	if raw == "" {
+		return defaultHistoryWindow, true
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil || window <= 0 {
+		h.respondError(w, http.StatusBadRequest, "invalid window", "expected a positive duration such as 30m or 24h")
+		return 0, false
+	}
+	return window, true
+}
+
+// streamKeepaliveInterval is how often an idle results stream sends a
+// comment to keep proxies from closing the connection
+const streamKeepaliveInterval = 30 * time.Second
+
+// StreamTargetResults handles GET /api/targets/{name}/stream, pushing a
+// Server-Sent Event with the full results each time a run completes. The
+// stream ends when the target is removed or the server shuts down.
+func (h *Handlers) StreamTargetResults(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 	if name == "" {
 		h.respondError(w, http.StatusBadRequest, "target name is required", "")
 		return
 	}
 
-	// Check if target exists
-	if _, ok := h.manager.GetTarget(name); !ok {
-		h.respondError(w, http.StatusNotFound, "target not found", "")
+	results, unsubscribe, err := h.manager.SubscribeResults(name)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log(r).Warn("failed to clear write deadline for stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.log(r).Error("streaming not supported", "error", err)
+		return
+	}
+
+	keepalive := time.NewTicker(streamKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.streamsDone:
+			return
+		case res, ok := <-results:
+			if !ok {
+				// Target removed
+				return
+			}
+			data, err := json.Marshal(res)
+			if err != nil {
+				h.log(r).Error("failed to encode results event", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: results\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// getRawResults writes the archived raw guidellm JSON for a target
+func (h *Handlers) getRawResults(w http.ResponseWriter, name string) {
+	raw, err := h.manager.GetRawResults(name)
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	if raw == nil {
+		h.respondError(w, http.StatusNotFound, "no archived guidellm output", "enable archive_raw_output to retain it")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(raw); err != nil {
+		h.logger.Error("failed to write raw results", "error", err)
+	}
+}
+
+// TriggerRun handles POST /api/targets/{name}/trigger and
+// POST /api/targets/{name}/run. The body is optional. With ?async=true the
+// run is started in the background and 202 is returned with its run ID, to
+// poll with GET /api/runs/{run_id}; otherwise the request waits for it.
+func (h *Handlers) TriggerRun(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.respondError(w, http.StatusBadRequest, "target name is required", "")
 		return
 	}
 
 	var req TriggerRunRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
 		h.respondError(w, http.StatusBadRequest, "invalid request body", err.Error())
 		return
 	}
 
-	h.logger.Info("trigger run requested", "target", name, "run_id", req.RunID)
+	async := r.URL.Query().Get("async")
+	if async != "" && async != "true" && async != "false" {
+		h.respondError(w, http.StatusBadRequest, "invalid async", "expected true or false")
+		return
+	}
+
+	// Generate the run ID here so it is in the response even if the run fails
+	if req.RunID == "" {
+		req.RunID = NewRunID()
+	}
+
+	h.log(r).Info("trigger run requested", "target", name, "run_id", req.RunID, "async", async == "true")
 
-	// Run the benchmark synchronously (this may take a while)
-	results, err := h.manager.TriggerRun(r.Context(), name, req.RunID)
+	if async == "true" {
+		run, err := h.manager.StartRun(r.Context(), name, req.RunID, req.ConfigOverrides)
+		if err != nil {
+			h.respondManagerError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/api/runs/"+url.PathEscape(run.RunID))
+		h.respondJSON(w, http.StatusAccepted, run)
+		return
+	}
+
+	// Run the benchmark synchronously (this may take a while). The run is
+	// detached from the request so a client disconnecting, e.g. on a proxy
+	// timeout, doesn't kill it: it still completes and its results are
+	// recorded, bounded by the target's run timeout.
+	results, err := h.manager.TriggerRun(context.WithoutCancel(r.Context()), name, req.RunID, req.ConfigOverrides)
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrInvalid) {
+		h.respondManagerError(w, err)
+		return
+	}
 	if err != nil {
-		h.logger.Error("trigger run failed", "target", name, "error", err)
+		h.log(r).Error("trigger run failed", "target", name, "error", err)
 		h.respondJSON(w, http.StatusOK, TriggerRunResponse{
 			Name:   name,
 			RunID:  req.RunID,
@@ -227,12 +752,79 @@ func (h *Handlers) TriggerRun(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ListRuns handles GET /api/runs, newest first, optionally filtered by
+// ?target= and ?status=
+func (h *Handlers) ListRuns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	status := RunStatus(query.Get("status"))
+	switch status {
+	case "", RunStatusPending, RunStatusRunning, RunStatusDone, RunStatusFailed:
+	default:
+		h.respondError(w, http.StatusBadRequest, "invalid status", "expected pending, running, done or failed")
+		return
+	}
+
+	runs := h.manager.ListRuns(query.Get("target"), status)
+	h.respondJSON(w, http.StatusOK, ListRunsResponse{Runs: runs, Total: len(runs)})
+}
+
+// GetRun handles GET /api/runs/{run_id}
+func (h *Handlers) GetRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.manager.GetRun(r.PathValue("run_id"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, run)
+}
+
 // GetStatus handles GET /api/status
 func (h *Handlers) GetStatus(w http.ResponseWriter, r *http.Request) {
 	status := h.manager.GetStatus()
 	h.respondJSON(w, http.StatusOK, status)
 }
 
+// GetVersion handles GET /api/version
+func (h *Handlers) GetVersion(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.manager.GetVersion())
+}
+
+// GetOpenAPI handles GET /api/openapi.json
+func (h *Handlers) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.openAPI)
+}
+
+// GetSummary handles GET /api/summary
+func (h *Handlers) GetSummary(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.manager.GetSummary())
+}
+
+// GetMetricsSnapshot handles GET /api/metrics/snapshot
+func (h *Handlers) GetMetricsSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.manager.GetMetricsSnapshot())
+}
+
+// GetConfig handles GET /api/config
+func (h *Handlers) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.manager.GetConfig()
+	if err != nil {
+		h.log(r).Error("failed to get config", "error", err)
+		h.respondError(w, http.StatusInternalServerError, "failed to get config", err.Error())
+		return
+	}
+	h.respondJSON(w, http.StatusOK, cfg)
+}
+
+// GetTargetConfig handles GET /api/targets/{name}/config
+func (h *Handlers) GetTargetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.manager.GetTargetConfig(r.PathValue("name"))
+	if err != nil {
+		h.respondManagerError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, cfg)
+}
+
 // HealthCheck handles GET /api/health
 func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
@@ -273,11 +865,7 @@ func (h *Handlers) GetBenchmarkStatus(w http.ResponseWriter, r *http.Request) {
 // TriggerManualRun handles POST /api/v1/benchmark/run
 // Triggers immediate manual runs for all active targets
 func (h *Handlers) TriggerManualRun(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		RunID  string `json:"run_id"`
-		Target string `json:"target,omitempty"` // Optional: run specific target only
-	}
-
+	var req ManualRunRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respondError(w, http.StatusBadRequest, "invalid request body", err.Error())
 		return
@@ -285,12 +873,12 @@ func (h *Handlers) TriggerManualRun(w http.ResponseWriter, r *http.Request) {
 
 	// If target is specified, run that target only
 	if req.Target != "" {
-		results, err := h.manager.TriggerRun(r.Context(), req.Target, req.RunID)
+		results, err := h.manager.TriggerRun(context.WithoutCancel(r.Context()), req.Target, req.RunID, nil)
+		if errors.Is(err, ErrNotFound) {
+			h.respondManagerError(w, err)
+			return
+		}
 		if err != nil {
-			if _, ok := h.manager.GetTarget(req.Target); !ok {
-				h.respondError(w, http.StatusNotFound, "target not found", "")
-				return
-			}
 			h.respondJSON(w, http.StatusOK, TriggerRunResponse{
 				Name:   req.Target,
 				RunID:  req.RunID,
@@ -310,10 +898,10 @@ func (h *Handlers) TriggerManualRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Otherwise, trigger all running targets
-	targets := h.manager.ListTargets()
+	targets, _ := h.manager.ListTargets(TargetFilter{})
 	runningTargets := 0
 	for _, t := range targets {
-		if t.Status == TargetStatusRunning {
+		if t.Status.IsActive() {
 			runningTargets++
 		}
 	}
@@ -331,6 +919,11 @@ func (h *Handlers) TriggerManualRun(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// log returns the handler logger tagged with the request's ID
+func (h *Handlers) log(r *http.Request) *slog.Logger {
+	return WithRequestID(r.Context(), h.logger)
+}
+
 // respondJSON writes a JSON response
 func (h *Handlers) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
@@ -339,6 +932,30 @@ func (h *Handlers) respondJSON(w http.ResponseWriter, status int, data interface
 	}
 }
 
+// respondManagerError maps an error returned by the TargetManager to a
+// response: 404 for unknown targets, 400 for everything else
+func (h *Handlers) respondManagerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, err.Error(), "")
+		return
+	}
+	if errors.Is(err, ErrConflict) {
+		h.respondError(w, http.StatusConflict, err.Error(), "")
+		return
+	}
+	h.respondError(w, http.StatusBadRequest, err.Error(), "")
+}
+
+// respondValidationError writes a 422 response giving the problem with
+// each invalid field of a request
+func (h *Handlers) respondValidationError(w http.ResponseWriter, verr *ValidationError) {
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	resp := ErrorResponse{Error: "validation failed", Fields: verr.Fields}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode error response", "error", err)
+	}
+}
+
 // respondError writes an error response
 func (h *Handlers) respondError(w http.ResponseWriter, status int, error string, message string) {
 	w.WriteHeader(status)
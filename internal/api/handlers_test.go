@@ -0,0 +1,738 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+func TestTriggerRunAsync(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/targets/llama/run?async=true", nil)
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var run RunResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &run))
+	assert.True(t, strings.HasPrefix(run.RunID, "run-"), "expected a generated run ID, got %q", run.RunID)
+	assert.Equal(t, "llama", run.Target)
+	assert.Equal(t, RunStatusPending, run.Status)
+	assert.Equal(t, "/api/runs/"+run.RunID, rec.Header().Get("Location"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/runs/"+run.RunID, nil)
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/runs?target=llama&status=pending", nil)
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list ListRunsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/runs?status=finished", nil)
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/runs/missing", nil)
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/targets/llama/run?async=maybe", nil)
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTriggerRunOverrides(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+
+	body := `{"config_overrides":{"rate":50,"data_spec":"prompt_tokens=64,output_tokens=32"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/targets/llama/run?async=true", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.NotNil(t, manager.overrides)
+	assert.Equal(t, 50.0, *manager.overrides.Rate)
+	assert.Equal(t, "prompt_tokens=64,output_tokens=32", manager.overrides.DataSpec)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/targets/llama/run", strings.NewReader(`{"config_overrides":{"rps":50}}`))
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "rps")
+}
+
+func TestCompareRunsQuery(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/targets/llama/compare?baseline=run-1&current=run-2&max_throughput_drop=5", nil)
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "run-1", manager.compare.Baseline)
+	assert.Equal(t, "run-2", manager.compare.Current)
+	require.NotNil(t, manager.compare.MaxThroughputDrop)
+	assert.Equal(t, 5.0, *manager.compare.MaxThroughputDrop)
+	assert.Nil(t, manager.compare.MaxLatencyIncrease)
+
+	for _, query := range []string{"current=run-2", "baseline=run-1&max_latency_increase=-1", "baseline=run-1&max_throughput_drop=NaN"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/llama/compare?"+query, nil)
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+// fakeManager implements TargetManager for handler tests. Methods a test
+// doesn't stub fall through to the nil embedded interface and panic.
+type fakeManager struct {
+	TargetManager
+
+	raw     map[string][]byte
+	results map[string]*ResultsResponse
+	configs map[string]*TargetConfigResponse
+
+	// stream is handed to stream subscribers; unsubscribed is closed when
+	// the stream unsubscribes
+	stream       chan *parser.ParsedResults
+	unsubscribed chan struct{}
+
+	// bulk records the action and names of each bulk action call
+	bulk []BulkActionRequest
+
+	// filter records the last ListTargets filter
+	filter TargetFilter
+
+	// requestIDs records the request ID in each AddTarget call's context
+	requestIDs []string
+
+	// addErr, if set, is returned by AddTarget
+	addErr error
+
+	// runCtx is the context of the last TriggerRun call
+	runCtx context.Context
+
+	// runs are the runs started with StartRun, by ID
+	runs map[string]*RunResponse
+
+	// overrides are the config overrides of the last StartRun call
+	overrides *RunOverrides
+
+	// compare is the last CompareRuns request
+	compare CompareRequest
+}
+
+func (f *fakeManager) CompareRuns(name string, req CompareRequest) (*CompareResponse, error) {
+	f.compare = req
+	return &CompareResponse{Name: name, Pass: true}, nil
+}
+
+func (f *fakeManager) StartRun(ctx context.Context, name string, runID string, overrides *RunOverrides) (*RunResponse, error) {
+	f.overrides = overrides
+	run := &RunResponse{RunID: runID, Target: name, Status: RunStatusPending}
+	if f.runs == nil {
+		f.runs = make(map[string]*RunResponse)
+	}
+	f.runs[runID] = run
+	return run, nil
+}
+
+func (f *fakeManager) ListRuns(target string, status RunStatus) []RunResponse {
+	var runs []RunResponse
+	for _, run := range f.runs {
+		if (target == "" || run.Target == target) && (status == "" || run.Status == status) {
+			runs = append(runs, *run)
+		}
+	}
+	return runs
+}
+
+func (f *fakeManager) GetRun(runID string) (*RunResponse, error) {
+	run, ok := f.runs[runID]
+	if !ok {
+		return nil, fmt.Errorf("run %q %w", runID, ErrNotFound)
+	}
+	return run, nil
+}
+
+func (f *fakeManager) TriggerRun(ctx context.Context, name string, runID string, overrides *RunOverrides) (*parser.ParsedResults, error) {
+	f.runCtx = ctx
+	return &parser.ParsedResults{}, nil
+}
+
+func (f *fakeManager) AddTarget(ctx context.Context, req AddTargetRequest) (*TargetResponse, error) {
+	f.requestIDs = append(f.requestIDs, RequestIDFromContext(ctx))
+	if f.addErr != nil {
+		return nil, f.addErr
+	}
+	return &TargetResponse{Name: req.Name}, nil
+}
+
+func (f *fakeManager) ListTargets(filter TargetFilter) ([]TargetResponse, int) {
+	f.filter = filter
+	return []TargetResponse{{Name: "llama-prod"}}, 7
+}
+
+func (f *fakeManager) BulkAction(ctx context.Context, action string, names []string) (*BulkActionResponse, error) {
+	f.bulk = append(f.bulk, BulkActionRequest{Action: action, Names: names})
+	if action != BulkActionStart && action != BulkActionStop {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	return &BulkActionResponse{Action: action}, nil
+}
+
+func (f *fakeManager) SubscribeResults(name string) (<-chan *parser.ParsedResults, func(), error) {
+	if f.stream == nil {
+		return nil, nil, fmt.Errorf("target %q %w", name, ErrNotFound)
+	}
+	return f.stream, func() { close(f.unsubscribed) }, nil
+}
+
+func (f *fakeManager) GetRawResults(name string) ([]byte, error) {
+	raw, ok := f.raw[name]
+	if !ok {
+		return nil, fmt.Errorf("target %q %w", name, ErrNotFound)
+	}
+	return raw, nil
+}
+
+func (f *fakeManager) GetLatestResults(name string) (*ResultsResponse, error) {
+	resp, ok := f.results[name]
+	if !ok {
+		return nil, fmt.Errorf("target %q %w", name, ErrNotFound)
+	}
+	return resp, nil
+}
+
+func (f *fakeManager) GetTargetConfig(name string) (*TargetConfigResponse, error) {
+	cfg, ok := f.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("target %q %w", name, ErrNotFound)
+	}
+	return cfg, nil
+}
+
+// newTestServer creates a server around the given manager for use with
+// httptest recorders
+func newTestServer(manager TargetManager) *Server {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError, // Quiet during tests
+	}))
+	return NewServer(ServerConfig{Logger: logger}, manager)
+}
+
+func TestGetTargetResults_GuidellmFormat(t *testing.T) {
+	rawJSON := `{"metadata":{"version":1,"guidellm_version":"0.5.0"},"benchmarks":[{"type_":"benchmark"}]}`
+	server := newTestServer(&fakeManager{raw: map[string][]byte{
+		"archived":     []byte(rawJSON),
+		"not-archived": nil,
+	}})
+
+	t.Run("returns raw archived content", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/archived/results?format=guidellm", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, rawJSON, rec.Body.String())
+	})
+
+	t.Run("404 when nothing archived", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/not-archived/results?format=guidellm", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "no archived guidellm output", resp.Error)
+	})
+
+	t.Run("404 for unknown target", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/missing/results?format=guidellm", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("400 for unsupported format", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/archived/results?format=xml", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestStreamTargetResults(t *testing.T) {
+	manager := &fakeManager{
+		stream:       make(chan *parser.ParsedResults, 1),
+		unsubscribed: make(chan struct{}),
+	}
+	ts := httptest.NewServer(newTestServer(manager).server.Handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/targets/streamed/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	manager.stream <- &parser.ParsedResults{TotalRequests: 42, RequestsPerSec: 1.5}
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: results\n", event)
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(data, "data: "), "unexpected line %q", data)
+
+	var results parser.ParsedResults
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &results))
+	assert.Equal(t, 42, results.TotalRequests)
+	assert.Equal(t, 1.5, results.RequestsPerSec)
+
+	// Disconnecting the client unsubscribes
+	cancel()
+	select {
+	case <-manager.unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not unsubscribe after client disconnect")
+	}
+}
+
+// TestShutdownEndsStreams verifies that an open results stream doesn't hold
+// up the server's shutdown until its context expires
+func TestShutdownEndsStreams(t *testing.T) {
+	manager := &fakeManager{
+		stream:       make(chan *parser.ParsedResults),
+		unsubscribed: make(chan struct{}),
+	}
+	server := newTestServer(manager)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.server.Serve(ln)
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/api/targets/streamed/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx), "shutdown waited for the open stream")
+
+	select {
+	case <-manager.unsubscribed:
+	default:
+		t.Fatal("stream did not unsubscribe on shutdown")
+	}
+	_, err = io.ReadAll(resp.Body)
+	assert.NoError(t, err, "stream should end cleanly")
+}
+
+func TestStreamTargetResults_UnknownTarget(t *testing.T) {
+	server := newTestServer(&fakeManager{})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/targets/missing/stream", nil)
+	server.server.Handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetTargetResults_Freshness(t *testing.T) {
+	lastRunAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := newTestServer(&fakeManager{results: map[string]*ResultsResponse{
+		"running": {
+			Name:      "running",
+			Results:   &parser.ParsedResults{TotalRequests: 10},
+			LastRunAt: &lastRunAt,
+			IsRunning: true,
+		},
+		"never-run": {Name: "never-run"},
+	}})
+
+	t.Run("stale results while running", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/running/results", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.Equal(t, true, body["is_running"])
+		assert.Equal(t, "2025-01-02T03:04:05Z", body["last_run_at"])
+		assert.NotNil(t, body["results"])
+	})
+
+	t.Run("no results yet", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/never-run/results", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.Equal(t, false, body["is_running"])
+		assert.Nil(t, body["results"])
+		assert.NotContains(t, body, "last_run_at")
+		assert.Equal(t, "no results available yet", body["message"])
+	})
+}
+
+func TestGetTargetConfig(t *testing.T) {
+	rate := 2.5
+	server := newTestServer(&fakeManager{configs: map[string]*TargetConfigResponse{
+		"llama-prod": {Name: "llama-prod", Profile: "constant", Rate: &rate, Interval: 300},
+	}})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/targets/llama-prod/config", nil)
+	server.server.Handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "constant", body["profile"])
+	assert.Equal(t, 2.5, body["rate"])
+	assert.Equal(t, float64(300), body["interval"])
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/targets/missing/config", nil)
+	server.server.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCORS(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	server := NewServer(ServerConfig{
+		Logger:             logger,
+		CORSAllowedOrigins: []string{"https://dashboard.example.com"},
+	}, &fakeManager{})
+
+	t.Run("preflight lists registered methods", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/api/targets/llama/override", nil)
+		req.Header.Set("Origin", "https://dashboard.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://dashboard.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "POST, DELETE, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("disallowed origin gets no CORS headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/api/targets", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("simple request from allowed origin", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.Header.Set("Origin", "https://dashboard.example.com")
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://dashboard.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("same-origin only by default", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.Header.Set("Origin", "https://dashboard.example.com")
+		newTestServer(&fakeManager{}).server.Handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestBulkActionRoutes(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		server.server.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, post("/api/targets/start-all", "").Code)
+	assert.Equal(t, http.StatusOK, post("/api/targets/stop-all", "").Code)
+	assert.Equal(t, http.StatusOK, post("/api/targets/actions", `{"action":"stop","names":["a","b"]}`).Code)
+	require.Len(t, manager.bulk, 3)
+	assert.Equal(t, BulkActionRequest{Action: BulkActionStart}, manager.bulk[0])
+	assert.Equal(t, BulkActionRequest{Action: BulkActionStop}, manager.bulk[1])
+	assert.Equal(t, BulkActionRequest{Action: BulkActionStop, Names: []string{"a", "b"}}, manager.bulk[2])
+
+	// Acting on every target has its own endpoints, so names are required
+	assert.Equal(t, http.StatusBadRequest, post("/api/targets/actions", `{"action":"stop"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/api/targets/actions", `{"action":"restart","names":["a"]}`).Code)
+}
+
+func TestListTargetsQuery(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/api/targets?environment=prod&status=degraded&q=llama&limit=1&offset=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, TargetFilter{Environment: "prod", Status: TargetStatusDegraded, Query: "llama", Limit: 1, Offset: 2}, manager.filter)
+
+	var resp ListTargetsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 7, resp.Total)
+	assert.Len(t, resp.Targets, 1)
+
+	rec = get("/api/targets?tag=team=search&tag=gpu")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]string{"team": "search", "gpu": ""}, manager.filter.Tags)
+
+	for _, bad := range []string{"?status=sleeping", "?limit=-1", "?offset=x", "?tag==prod"} {
+		assert.Equal(t, http.StatusBadRequest, get("/api/targets"+bad).Code, bad)
+	}
+}
+
+func TestExportResults(t *testing.T) {
+	lastRunAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := newTestServer(&fakeManager{results: map[string]*ResultsResponse{
+		"llama": {
+			Name: "llama",
+			Results: &parser.ParsedResults{
+				TotalRequests:      10,
+				SuccessfulRequests: 9,
+				FailedRequests:     1,
+				RequestsPerSec:     2.5,
+				TTFTValues:         []float64{0.3, 0.1, 0.2},
+			},
+			LastRunAt: &lastRunAt,
+		},
+		"never-run": {Name: "never-run"},
+	}})
+
+	t.Run("csv", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/llama/results.csv", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "target,timestamp,requests_total,requests_successful,requests_failed,"))
+		assert.Contains(t, lines[0], "ttft_seconds_p50")
+		assert.True(t, strings.HasPrefix(lines[1], "llama,2025-01-02T03:04:05Z,10,9,1,"))
+	})
+
+	t.Run("csv without results has only a header", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/never-run/results?format=csv", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, strings.Split(strings.TrimSpace(rec.Body.String()), "\n"), 1)
+	})
+
+	t.Run("prom", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/llama/results?format=prom", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE guidellm_result_requests_total gauge\n")
+		assert.Contains(t, body, fmt.Sprintf("guidellm_result_requests_total{target=\"llama\"} 10 %d\n", lastRunAt.UnixMilli()))
+		assert.Contains(t, body, fmt.Sprintf("guidellm_result_ttft_seconds_p50{target=\"llama\"} 0.2 %d\n", lastRunAt.UnixMilli()))
+	})
+
+	t.Run("404 for unknown target", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/targets/missing/results.csv", nil)
+		server.server.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+	addTarget := func(requestID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(`{"name":"t"}`))
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		server.server.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A client-supplied ID is echoed and reaches the manager
+	rec := addTarget("client-id-123")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "client-id-123", rec.Header().Get(RequestIDHeader))
+
+	// Otherwise, or if the supplied ID is unsafe to log, one is generated
+	generated := addTarget("").Header().Get(RequestIDHeader)
+	assert.Len(t, generated, 32)
+	replaced := addTarget("bad id\n").Header().Get(RequestIDHeader)
+	assert.Len(t, replaced, 32)
+	assert.NotEqual(t, generated, replaced)
+
+	assert.Equal(t, []string{"client-id-123", generated, replaced}, manager.requestIDs)
+}
+
+// TestAddTargetValidationErrors verifies that several invalid fields are
+// reported together by field with a 422, while a single one keeps the plain
+// 400 response
+func TestAddTargetValidationErrors(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+	addTarget := func() (*httptest.ResponseRecorder, ErrorResponse) {
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(`{"name":"t"}`)))
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec, resp
+	}
+
+	fields := map[string]string{
+		"model": "model is required",
+		"url":   `invalid url "ftp://host": scheme must be http or https`,
+	}
+	manager.addErr = fmt.Errorf("adding target: %w", &ValidationError{Fields: fields})
+	rec, resp := addTarget()
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "validation failed", resp.Error)
+	assert.Equal(t, fields, resp.Fields)
+	assert.ErrorIs(t, manager.addErr, ErrInvalid)
+
+	manager.addErr = &ValidationError{Fields: map[string]string{"model": "model is required"}}
+	rec, resp = addTarget()
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ErrorResponse{Error: "model is required"}, resp)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	server := newTestServer(&fakeManager{})
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{method: http.MethodDelete, path: "/api/status", allow: "GET, HEAD, OPTIONS"},
+		{method: http.MethodPut, path: "/api/targets/llama", allow: "GET, HEAD, DELETE, OPTIONS"},
+		{method: http.MethodGet, path: "/api/v1/benchmark/pause", allow: "POST, OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			server.server.Handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+			assert.Equal(t, tt.allow, rec.Header().Get("Allow"))
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "method not allowed", resp.Error)
+		})
+	}
+
+	// Unknown paths are still not found
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestOpenAPI(t *testing.T) {
+	// Every route is documented
+	for _, rt := range apiRoutes(&Handlers{}) {
+		assert.Contains(t, operations, rt.method+" "+rt.pattern, "route has no OpenAPI operation")
+	}
+
+	rec := httptest.NewRecorder()
+	newTestServer(&fakeManager{}).server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec openAPISpec
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	target := spec.Paths["/api/targets/{name}"]
+	require.Contains(t, target, "get")
+	require.Contains(t, target, "delete")
+	assert.Equal(t, "getTarget", target["get"].OperationID)
+	require.Len(t, target["get"].Parameters, 1)
+	assert.Equal(t, "path", target["get"].Parameters[0].In)
+	assert.Equal(t, "#/components/schemas/TargetResponse", target["get"].Responses["200"].Content["application/json"].Schema.Ref)
+
+	// Schemas are derived from the JSON tags; omitempty fields are optional
+	add := spec.Components.Schemas["AddTargetRequest"]
+	require.NotNil(t, add)
+	assert.Contains(t, add.Properties, "max_seconds")
+	assert.Equal(t, "integer", add.Properties["max_seconds"].Type)
+	assert.True(t, add.Properties["max_seconds"].Nullable)
+	assert.Equal(t, []string{"model", "name", "url"}, add.Required)
+	assert.Equal(t, "#/components/schemas/ParsedResults", spec.Components.Schemas["ResultsResponse"].Properties["results"].Ref)
+	assert.Contains(t, spec.Components.Schemas, "ParsedResults")
+}
+
+func TestTriggerRunOutlivesRequest(t *testing.T) {
+	manager := &fakeManager{}
+	server := newTestServer(manager)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/api/targets/llama/trigger", strings.NewReader(`{"run_id":"run-1"}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// The client going away doesn't cancel the run
+	cancel()
+	require.NotNil(t, manager.runCtx)
+	assert.NoError(t, manager.runCtx.Err())
+	assert.NotEmpty(t, RequestIDFromContext(manager.runCtx), "detached context should keep request values")
+}
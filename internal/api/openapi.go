@@ -0,0 +1,517 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIVersion is the version of the control API described by the spec
+const OpenAPIVersion = "1.0.0"
+
+// OpenAPISpec returns the OpenAPI 3 description of the control API as
+// indented JSON, e.g. for generating client SDKs
+func OpenAPISpec() ([]byte, error) {
+	return json.MarshalIndent(buildOpenAPISpec(apiRoutes(&Handlers{})), "", "  ")
+}
+
+// operationDoc describes a route for the OpenAPI spec. Request and response
+// bodies are given as zero values of their types, whose schemas are derived
+// from the types' JSON tags.
+type operationDoc struct {
+	id        string
+	summary   string
+	query     []queryParam
+	request   any         // request body, nil if none
+	responses map[int]any // body by status code
+}
+
+// queryParam is an optional query parameter of an operation
+type queryParam struct {
+	name        string
+	description string
+	integer     bool // string if neither integer nor number
+	number      bool
+}
+
+// rawBody is a non-JSON response body
+type rawBody struct {
+	contentType string
+}
+
+// Query parameters shared by several operations
+var (
+	windowParam = queryParam{name: "window", description: "Time window to cover, e.g. 30m or 24h (default 1h)"}
+	asyncParam  = queryParam{name: "async", description: "true to start the run in the background and return its run ID"}
+	errorBody   = ErrorResponse{}
+)
+
+// operations documents every route, keyed by "METHOD pattern"
+var operations = map[string]operationDoc{
+	"GET /api/targets": {
+		id:      "listTargets",
+		summary: "List targets sorted by name, filtered and paged",
+		query: []queryParam{
+			{name: "environment", description: "Only targets in this environment"},
+			{name: "status", description: "Only targets with this status"},
+			{name: "q", description: "Only targets whose name contains this, case-insensitively"},
+			{name: "tag", description: "Only targets with this tag, as key=value, or key for any value; repeat to require several"},
+			{name: "limit", description: "Maximum number of targets to return", integer: true},
+			{name: "offset", description: "Number of matching targets to skip", integer: true},
+		},
+		responses: map[int]any{200: ListTargetsResponse{}, 400: errorBody},
+	},
+	"POST /api/targets": {
+		id:        "addTarget",
+		summary:   "Add a target at runtime",
+		request:   AddTargetRequest{},
+		responses: map[int]any{201: TargetResponse{}, 400: errorBody, 422: errorBody},
+	},
+	"POST /api/targets/start-all": {
+		id:        "startAllTargets",
+		summary:   "Start every target",
+		responses: map[int]any{200: BulkActionResponse{}, 400: errorBody},
+	},
+	"POST /api/targets/stop-all": {
+		id:        "stopAllTargets",
+		summary:   "Stop every target",
+		responses: map[int]any{200: BulkActionResponse{}, 400: errorBody},
+	},
+	"POST /api/targets/actions": {
+		id:        "targetActions",
+		summary:   "Start or stop the named targets",
+		request:   BulkActionRequest{},
+		responses: map[int]any{200: BulkActionResponse{}, 400: errorBody},
+	},
+	"GET /api/targets/{name}": {
+		id:        "getTarget",
+		summary:   "Get a target",
+		responses: map[int]any{200: TargetResponse{}, 404: errorBody},
+	},
+	"DELETE /api/targets/{name}": {
+		id:        "removeTarget",
+		summary:   "Remove a target, stopping it first",
+		responses: map[int]any{200: map[string]string{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/start": {
+		id:        "startTarget",
+		summary:   "Start benchmarking a target on its schedule",
+		responses: map[int]any{200: TargetActionResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/stop": {
+		id:        "stopTarget",
+		summary:   "Stop benchmarking a target",
+		responses: map[int]any{200: TargetActionResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/trigger": {
+		id:        "triggerRun",
+		summary:   "Run a benchmark of a target now and wait for its results, or start it in the background with async=true",
+		query:     []queryParam{asyncParam},
+		request:   TriggerRunRequest{},
+		responses: map[int]any{200: TriggerRunResponse{}, 202: RunResponse{}, 400: errorBody, 404: errorBody, 409: errorBody},
+	},
+	"POST /api/targets/{name}/run": {
+		id:        "runTarget",
+		summary:   "Run a benchmark of a target now and wait for its results, or start it in the background with async=true",
+		query:     []queryParam{asyncParam},
+		request:   TriggerRunRequest{},
+		responses: map[int]any{200: TriggerRunResponse{}, 202: RunResponse{}, 400: errorBody, 404: errorBody, 409: errorBody},
+	},
+	"GET /api/targets/{name}/compare": {
+		id:      "compareRuns",
+		summary: "Compare the throughput and latency percentiles of two runs in a target's history",
+		query: []queryParam{
+			{name: "baseline", description: "Run ID of the baseline run (required)"},
+			{name: "current", description: "Run ID of the run compared to the baseline (default: the latest run)"},
+			{name: "max_throughput_drop", description: "Percentage drop in throughput that fails the comparison", number: true},
+			{name: "max_latency_increase", description: "Percentage increase in a latency percentile that fails the comparison", number: true},
+		},
+		responses: map[int]any{200: CompareResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/baseline": {
+		id:        "setBaseline",
+		summary:   "Pin a run in a target's history, by default the latest with output throughput, as its baseline",
+		request:   BaselineRequest{},
+		responses: map[int]any{200: BaselineResponse{}, 400: errorBody, 404: errorBody, 409: errorBody},
+	},
+	"GET /api/targets/{name}/baseline": {
+		id:        "getBaseline",
+		summary:   "Get a target's pinned baseline",
+		responses: map[int]any{200: BaselineResponse{}, 404: errorBody},
+	},
+	"DELETE /api/targets/{name}/baseline": {
+		id:        "clearBaseline",
+		summary:   "Unpin a target's baseline, returning regression alerts to the rolling window",
+		responses: map[int]any{200: map[string]string{}, 404: errorBody},
+	},
+	"POST /api/groups/{name}/start": {
+		id:        "startGroup",
+		summary:   "Start every target currently in a configured group",
+		responses: map[int]any{200: BulkActionResponse{}, 404: errorBody},
+	},
+	"POST /api/groups/{name}/stop": {
+		id:        "stopGroup",
+		summary:   "Stop every target currently in a configured group",
+		responses: map[int]any{200: BulkActionResponse{}, 404: errorBody},
+	},
+	"GET /api/groups/{name}/summary": {
+		id:        "getGroupSummary",
+		summary:   "Get the latest results of every target in a configured group",
+		responses: map[int]any{200: GroupSummaryResponse{}, 404: errorBody},
+	},
+	"GET /api/runs": {
+		id:      "listRuns",
+		summary: "List recent scheduled and manual runs, newest first",
+		query: []queryParam{
+			{name: "target", description: "only runs of this target"},
+			{name: "status", description: "only runs in this status: pending, running, done or failed"},
+		},
+		responses: map[int]any{200: ListRunsResponse{}, 400: errorBody},
+	},
+	"GET /api/runs/{run_id}": {
+		id:        "getRun",
+		summary:   "Get the status and, once finished, the results of a recent run",
+		responses: map[int]any{200: RunResponse{}, 404: errorBody},
+	},
+	"GET /api/targets/{name}/config": {
+		id:        "getTargetConfig",
+		summary:   "Get the resolved settings of a target's runs",
+		responses: map[int]any{200: TargetConfigResponse{}, 404: errorBody},
+	},
+	"GET /api/targets/{name}/results": {
+		id:      "getTargetResults",
+		summary: "Get a target's latest results",
+		query: []queryParam{
+			{name: "format", description: "guidellm for the raw guidellm output, csv or prom to export runs in the window"},
+			windowParam,
+		},
+		responses: map[int]any{200: ResultsResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"GET /api/targets/{name}/results.csv": {
+		id:        "exportResultsCSV",
+		summary:   "Export a target's runs in a time window as CSV",
+		query:     []queryParam{windowParam},
+		responses: map[int]any{200: rawBody{"text/csv"}, 400: errorBody, 404: errorBody},
+	},
+	"GET /api/targets/{name}/stream": {
+		id:        "streamTargetResults",
+		summary:   "Stream a target's results as server-sent events",
+		responses: map[int]any{200: rawBody{"text/event-stream"}, 404: errorBody},
+	},
+	"GET /api/targets/{name}/history/percentiles": {
+		id:        "getHistoryPercentiles",
+		summary:   "Get latency percentiles merged across a target's runs in a time window",
+		query:     []queryParam{windowParam},
+		responses: map[int]any{200: HistoryPercentilesResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/override": {
+		id:        "setOverride",
+		summary:   "Temporarily override a target's benchmark settings",
+		request:   OverrideRequest{},
+		responses: map[int]any{200: TargetResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"DELETE /api/targets/{name}/override": {
+		id:        "clearOverride",
+		summary:   "Clear a target's override",
+		responses: map[int]any{200: TargetResponse{}, 400: errorBody, 404: errorBody},
+	},
+	"POST /api/targets/{name}/pause": {
+		id:        "pauseTarget",
+		summary:   "Pause a target's scheduled runs until resumed, leaving other targets running",
+		responses: map[int]any{200: TargetResponse{}, 404: errorBody},
+	},
+	"POST /api/targets/{name}/resume": {
+		id:        "resumeTarget",
+		summary:   "Resume a target's scheduled runs",
+		responses: map[int]any{200: TargetResponse{}, 404: errorBody},
+	},
+	"GET /api/failures": {
+		id:        "getFailures",
+		summary:   "Break down recent run failures across all targets",
+		query:     []queryParam{windowParam},
+		responses: map[int]any{200: FailuresResponse{}, 400: errorBody},
+	},
+	"GET /api/status": {
+		id:        "getStatus",
+		summary:   "Get the runner status",
+		responses: map[int]any{200: StatusResponse{}},
+	},
+	"GET /api/version": {
+		id:        "getVersion",
+		summary:   "Get the runner build and guidellm versions",
+		responses: map[int]any{200: VersionResponse{}},
+	},
+	"GET /api/openapi.json": {
+		id:        "getOpenAPI",
+		summary:   "Get this OpenAPI description of the API",
+		responses: map[int]any{200: map[string]any{}},
+	},
+	"GET /api/summary": {
+		id:        "getSummary",
+		summary:   "Get every target's latest results",
+		responses: map[int]any{200: SummaryResponse{}},
+	},
+	"GET /api/metrics/snapshot": {
+		id:        "getMetricsSnapshot",
+		summary:   "Get the current values of the runner's main gauges",
+		responses: map[int]any{200: MetricsSnapshotResponse{}},
+	},
+	"GET /api/config": {
+		id:        "getConfig",
+		summary:   "Get the effective configuration, with defaults applied and secrets redacted",
+		responses: map[int]any{200: map[string]any{}, 500: errorBody},
+	},
+	"GET /api/health": {
+		id:        "healthCheck",
+		summary:   "Check the API is serving",
+		responses: map[int]any{200: HealthResponse{}},
+	},
+	"POST /api/v1/benchmark/pause": {
+		id:        "pauseBenchmark",
+		summary:   "Pause scheduled runs",
+		responses: map[int]any{200: SchedulerActionResponse{}, 400: errorBody},
+	},
+	"POST /api/v1/benchmark/resume": {
+		id:        "resumeBenchmark",
+		summary:   "Resume scheduled runs",
+		responses: map[int]any{200: SchedulerActionResponse{}, 400: errorBody},
+	},
+	"POST /api/v1/benchmark/run": {
+		id:        "triggerManualRun",
+		summary:   "Run one target now, or trigger runs of every running target",
+		request:   ManualRunRequest{},
+		responses: map[int]any{200: TriggerRunResponse{}, 202: map[string]any{}, 400: errorBody, 404: errorBody},
+	},
+	"GET /api/v1/benchmark/status": {
+		id:        "getBenchmarkStatus",
+		summary:   "Get the scheduler state",
+		responses: map[int]any{200: SchedulerStatusResponse{}},
+	},
+}
+
+// openAPISpec is an OpenAPI 3 document
+type openAPISpec struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+}
+
+type openAPIMedia struct {
+	Schema *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// buildOpenAPISpec describes routes using their entries in operations.
+// Routes without an entry are listed with just their path and method.
+func buildOpenAPISpec(routes []route) *openAPISpec {
+	spec := &openAPISpec{
+		OpenAPI:    "3.0.3",
+		Info:       openAPIInfo{Title: "guidellm-runner control API", Version: OpenAPIVersion},
+		Paths:      make(map[string]map[string]openAPIOperation),
+		Components: openAPIComponents{Schemas: make(map[string]*openAPISchema)},
+	}
+
+	for _, rt := range routes {
+		doc := operations[rt.method+" "+rt.pattern]
+		op := openAPIOperation{
+			OperationID: doc.id,
+			Summary:     doc.summary,
+			Responses:   make(map[string]openAPIResponse),
+		}
+
+		for _, name := range pathParams(rt.pattern) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: name, In: "path", Required: true, Schema: &openAPISchema{Type: "string"},
+			})
+		}
+		for _, q := range doc.query {
+			schema := &openAPISchema{Type: "string"}
+			if q.integer {
+				schema = &openAPISchema{Type: "integer", Format: "int32"}
+			} else if q.number {
+				schema = &openAPISchema{Type: "number", Format: "double"}
+			}
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: q.name, In: "query", Description: q.description, Schema: schema,
+			})
+		}
+
+		if doc.request != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content: map[string]openAPIMedia{
+					"application/json": {Schema: spec.schemaFor(reflect.TypeOf(doc.request))},
+				},
+			}
+		}
+
+		for status, body := range doc.responses {
+			resp := openAPIResponse{Description: http.StatusText(status)}
+			if raw, ok := body.(rawBody); ok {
+				resp.Content = map[string]openAPIMedia{raw.contentType: {Schema: &openAPISchema{Type: "string"}}}
+			} else {
+				resp.Content = map[string]openAPIMedia{
+					"application/json": {Schema: spec.schemaFor(reflect.TypeOf(body))},
+				}
+			}
+			op.Responses[strconv.Itoa(status)] = resp
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["default"] = openAPIResponse{Description: "Response"}
+		}
+
+		if spec.Paths[rt.pattern] == nil {
+			spec.Paths[rt.pattern] = make(map[string]openAPIOperation)
+		}
+		spec.Paths[rt.pattern][strings.ToLower(rt.method)] = op
+	}
+	return spec
+}
+
+// pathParams returns the names of the wildcards in a mux pattern
+func pathParams(pattern string) []string {
+	var names []string
+	for _, segment := range strings.Split(pattern, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.TrimSuffix(strings.Trim(segment, "{}"), "..."))
+		}
+	}
+	return names
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema of a type as encoding/json marshals it.
+// Named structs are added to the spec's components and referenced.
+func (s *openAPISpec) schemaFor(t reflect.Type) *openAPISchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := *s.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			// Siblings of $ref are ignored in OpenAPI 3.0
+			return &schema
+		}
+		schema.Nullable = true
+		return &schema
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		format := "int64"
+		if t.Bits() <= 32 {
+			format = "int32"
+		}
+		return &openAPISchema{Type: "integer", Format: format}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: s.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: s.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t == timeType {
+			return &openAPISchema{Type: "string", Format: "date-time"}
+		}
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		if _, ok := s.Components.Schemas[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			s.Components.Schemas[t.Name()] = nil
+			s.Components.Schemas[t.Name()] = s.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		// interface{} and anything else: any JSON value
+		return &openAPISchema{}
+	}
+}
+
+// structSchema returns the object schema of a struct, with properties named
+// by their JSON tags. Fields without omitempty are required.
+func (s *openAPISpec) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := s.structSchema(field.Type)
+			for prop, propSchema := range embedded.Properties {
+				schema.Properties[prop] = propSchema
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.schemaFor(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+// RequestIDHeader carries a request's ID. A client-supplied ID is kept, so
+// calls can be correlated with the client's own logs; otherwise one is
+// generated. Either way it is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied IDs, which end up in logs
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the ID of the API request ctx belongs to, or
+// "" outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns logger with the request ID of ctx attached, if any
+func WithRequestID(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return logger.With("request_id", id)
+	}
+	return logger
+}
+
+// requestIDMiddleware assigns each request an ID, stores it in the request
+// context and sets it on the response
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// validRequestID reports whether a client-supplied ID is safe to log:
+// non-empty, bounded and printable ASCII
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRunID generates an ID for a run that wasn't given one
+func NewRunID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return "run-" + hex.EncodeToString(b[:])
+}
+
+// newRequestID generates a random 128-bit ID
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
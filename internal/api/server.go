@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -19,34 +21,96 @@ type Server struct {
 type ServerConfig struct {
 	Port   int
 	Logger *slog.Logger
+
+	// CORSAllowedOrigins lists browser origins allowed to call the API
+	// ("*" allows any). Empty means same-origin only.
+	CORSAllowedOrigins []string
+}
+
+// route is a single API endpoint registered on the mux
+type route struct {
+	method  string
+	pattern string
+	handler http.HandlerFunc
+}
+
+// apiRoutes returns every API endpoint, served by handlers. It is also the
+// source of the OpenAPI spec, so each route needs an entry in operations.
+func apiRoutes(handlers *Handlers) []route {
+	return []route{
+		{"GET", "/api/targets", handlers.ListTargets},
+		{"POST", "/api/targets", handlers.AddTarget},
+		{"POST", "/api/targets/start-all", handlers.StartAllTargets},
+		{"POST", "/api/targets/stop-all", handlers.StopAllTargets},
+		{"POST", "/api/targets/actions", handlers.TargetActions},
+		{"GET", "/api/targets/{name}", handlers.GetTarget},
+		{"DELETE", "/api/targets/{name}", handlers.RemoveTarget},
+		{"POST", "/api/targets/{name}/start", handlers.StartTarget},
+		{"POST", "/api/targets/{name}/stop", handlers.StopTarget},
+		{"POST", "/api/targets/{name}/trigger", handlers.TriggerRun},
+		{"POST", "/api/targets/{name}/run", handlers.TriggerRun},
+		{"GET", "/api/targets/{name}/results", handlers.GetTargetResults},
+		{"GET", "/api/targets/{name}/results.csv", handlers.ExportResultsCSV},
+		{"GET", "/api/targets/{name}/stream", handlers.StreamTargetResults},
+		{"GET", "/api/targets/{name}/history/percentiles", handlers.GetHistoryPercentiles},
+		{"GET", "/api/targets/{name}/compare", handlers.CompareRuns},
+		{"POST", "/api/targets/{name}/baseline", handlers.SetBaseline},
+		{"GET", "/api/targets/{name}/baseline", handlers.GetBaseline},
+		{"DELETE", "/api/targets/{name}/baseline", handlers.ClearBaseline},
+		{"POST", "/api/targets/{name}/override", handlers.SetOverride},
+		{"DELETE", "/api/targets/{name}/override", handlers.ClearOverride},
+		{"GET", "/api/targets/{name}/config", handlers.GetTargetConfig},
+		{"POST", "/api/targets/{name}/pause", handlers.PauseTarget},
+		{"POST", "/api/targets/{name}/resume", handlers.ResumeTarget},
+		{"POST", "/api/groups/{name}/start", handlers.StartGroup},
+		{"POST", "/api/groups/{name}/stop", handlers.StopGroup},
+		{"GET", "/api/groups/{name}/summary", handlers.GetGroupSummary},
+		{"GET", "/api/runs", handlers.ListRuns},
+		{"GET", "/api/runs/{run_id}", handlers.GetRun},
+		{"GET", "/api/failures", handlers.GetFailures},
+		{"GET", "/api/status", handlers.GetStatus},
+		{"GET", "/api/version", handlers.GetVersion},
+		{"GET", "/api/openapi.json", handlers.GetOpenAPI},
+		{"GET", "/api/summary", handlers.GetSummary},
+		{"GET", "/api/metrics/snapshot", handlers.GetMetricsSnapshot},
+		{"GET", "/api/config", handlers.GetConfig},
+		{"GET", "/api/health", handlers.HealthCheck},
+
+		// Benchmark control routes
+		{"POST", "/api/v1/benchmark/pause", handlers.PauseBenchmark},
+		{"POST", "/api/v1/benchmark/resume", handlers.ResumeBenchmark},
+		{"POST", "/api/v1/benchmark/run", handlers.TriggerManualRun},
+		{"GET", "/api/v1/benchmark/status", handlers.GetBenchmarkStatus},
+	}
 }
 
 // NewServer creates a new API server
 func NewServer(cfg ServerConfig, manager TargetManager) *Server {
 	handlers := NewHandlers(manager, cfg.Logger)
 
+	routes := apiRoutes(handlers)
+	handlers.openAPI = buildOpenAPISpec(routes)
+
 	mux := http.NewServeMux()
 
-	// Register routes
-	mux.HandleFunc("GET /api/targets", handlers.ListTargets)
-	mux.HandleFunc("POST /api/targets", handlers.AddTarget)
-	mux.HandleFunc("GET /api/targets/{name}", handlers.GetTarget)
-	mux.HandleFunc("DELETE /api/targets/{name}", handlers.RemoveTarget)
-	mux.HandleFunc("POST /api/targets/{name}/start", handlers.StartTarget)
-	mux.HandleFunc("POST /api/targets/{name}/stop", handlers.StopTarget)
-	mux.HandleFunc("POST /api/targets/{name}/trigger", handlers.TriggerRun)
-	mux.HandleFunc("GET /api/targets/{name}/results", handlers.GetTargetResults)
-	mux.HandleFunc("GET /api/status", handlers.GetStatus)
-	mux.HandleFunc("GET /api/health", handlers.HealthCheck)
-
-	// Benchmark control routes
-	mux.HandleFunc("POST /api/v1/benchmark/pause", handlers.PauseBenchmark)
-	mux.HandleFunc("POST /api/v1/benchmark/resume", handlers.ResumeBenchmark)
-	mux.HandleFunc("POST /api/v1/benchmark/run", handlers.TriggerManualRun)
-	mux.HandleFunc("GET /api/v1/benchmark/status", handlers.GetBenchmarkStatus)
+	// Register routes, collecting the methods served on each path
+	var patterns []string
+	methods := make(map[string][]string)
+	for _, rt := range routes {
+		mux.HandleFunc(rt.method+" "+rt.pattern, rt.handler)
+		if _, ok := methods[rt.pattern]; !ok {
+			patterns = append(patterns, rt.pattern)
+		}
+		methods[rt.pattern] = append(methods[rt.pattern], rt.method)
+	}
+
+	// Answer CORS preflight requests with the methods actually registered
+	for _, pattern := range patterns {
+		mux.Handle("OPTIONS "+pattern, preflightHandler(append(methods[pattern], http.MethodOptions)))
+	}
 
 	// Wrap with middleware
-	handler := loggingMiddleware(cfg.Logger, recoveryMiddleware(jsonContentTypeMiddleware(mux)))
+	handler := requestIDMiddleware(loggingMiddleware(cfg.Logger, recoveryMiddleware(corsMiddleware(cfg.CORSAllowedOrigins, jsonContentTypeMiddleware(methodNotAllowedMiddleware(handlers, mux))))))
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
@@ -55,6 +119,7 @@ func NewServer(cfg ServerConfig, manager TargetManager) *Server {
 		WriteTimeout: 15 * time.Minute, // Benchmarks can take several minutes
 		IdleTimeout:  60 * time.Second,
 	}
+	server.RegisterOnShutdown(handlers.closeStreams)
 
 	return &Server{
 		server:   server,
@@ -91,6 +156,72 @@ func jsonContentTypeMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// allMethods are the methods checked when a request's method isn't served
+// on its path, in the order they are listed in Allow headers
+var allMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// methodNotAllowedMiddleware answers requests for a known path with a method
+// it doesn't serve with a JSON 405 and an Allow header listing the methods
+// it does. Everything else, including unknown paths, goes to the mux.
+func methodNotAllowedMiddleware(h *Handlers, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern == "" {
+			if allowed := allowedMethods(mux, r); len(allowed) > 0 {
+				w.Header().Set("Allow", strings.Join(allowed, ", "))
+				h.respondError(w, http.StatusMethodNotAllowed, "method not allowed",
+					fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// allowedMethods returns the methods the mux serves on the request's path
+func allowedMethods(mux *http.ServeMux, r *http.Request) []string {
+	var allowed []string
+	for _, method := range allMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := mux.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// corsMiddleware adds CORS headers for requests from allowed origins.
+// Requests from other origins are served without them, so browsers enforce
+// same-origin.
+func corsMiddleware(allowedOrigins []string, next http.Handler) http.Handler {
+	allowAny := slices.Contains(allowedOrigins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && (allowAny || slices.Contains(allowedOrigins, origin)) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflightHandler answers CORS preflight requests for a path
+func preflightHandler(methods []string) http.Handler {
+	allowed := strings.Join(methods, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allowed)
+		w.Header().Set("Access-Control-Allow-Methods", allowed)
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+RequestIDHeader)
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
 // recoveryMiddleware recovers from panics and returns 500 errors
 func recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -119,7 +250,8 @@ func loggingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
 			"path", r.URL.Path,
 			"status", wrapped.statusCode,
 			"duration", time.Since(start).String(),
-			"remote_addr", r.RemoteAddr)
+			"remote_addr", r.RemoteAddr,
+			"request_id", RequestIDFromContext(r.Context()))
 	})
 }
 
@@ -133,3 +265,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap exposes the underlying writer to http.ResponseController, so
+// streaming handlers can flush through the middleware
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
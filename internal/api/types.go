@@ -1,6 +1,8 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"time"
 
 	"github.com/yourorg/guidellm-runner/internal/parser"
@@ -8,15 +10,40 @@ import (
 
 // AddTargetRequest is the request body for adding a new target
 type AddTargetRequest struct {
-	Name        string   `json:"name"`
-	URL         string   `json:"url"`
-	Model       string   `json:"model"`
-	Environment string   `json:"environment,omitempty"` // defaults to "dynamic"
-	APIKey      string   `json:"api_key,omitempty"`
-	Profile     string   `json:"profile,omitempty"`
-	Rate        *float64 `json:"rate,omitempty"`
-	MaxSeconds  *int     `json:"max_seconds,omitempty"`
-	RequestType string   `json:"request_type,omitempty"` // chat_completions or text_completions
+	Name         string   `json:"name"`
+	URL          string   `json:"url"`
+	Model        string   `json:"model"`
+	Environment  string   `json:"environment,omitempty"` // defaults to "dynamic"
+	APIKey       string   `json:"api_key,omitempty"`
+	Profile      string   `json:"profile,omitempty"`
+	Rate         *float64 `json:"rate,omitempty"`
+	MaxSeconds   *int     `json:"max_seconds,omitempty"`
+	RequestType  string   `json:"request_type,omitempty"`  // chat_completions or text_completions
+	HealthPath   string   `json:"health_path,omitempty"`   // defaults to /v1/models
+	Stream       *bool    `json:"stream,omitempty"`        // defaults to false
+	ProbeTimeout *int     `json:"probe_timeout,omitempty"` // seconds
+
+	// ChatFormatter is multimodal or text, for chat_completions targets
+	ChatFormatter string `json:"chat_formatter,omitempty"`
+
+	// ExtraArgs are extra guidellm flags appended to each run's command
+	ExtraArgs []string `json:"extra_args,omitempty"`
+
+	// WarmupRuns are discarded runs made each time the target starts
+	WarmupRuns *int `json:"warmup_runs,omitempty"`
+
+	// MaxRuns stops the target after this many scheduled runs each time it
+	// starts
+	MaxRuns *int `json:"max_runs,omitempty"`
+
+	// Processor is the tokenizer (Hugging Face model ID or local path)
+	Processor string `json:"processor,omitempty"`
+
+	// BackendKwargs are merged over the default guidellm backend kwargs
+	BackendKwargs map[string]interface{} `json:"backend_kwargs,omitempty"`
+
+	// Tags group the target, e.g. {"team": "search", "tier": "prod"}
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // TargetStatus represents the current state of a target
@@ -26,8 +53,35 @@ const (
 	TargetStatusStopped  TargetStatus = "stopped"
 	TargetStatusRunning  TargetStatus = "running"
 	TargetStatusStarting TargetStatus = "starting"
+
+	// A running target whose latest runs failed: degraded after the first
+	// failure, error once the streak reaches the configured threshold
+	TargetStatusDegraded TargetStatus = "degraded"
+	TargetStatusError    TargetStatus = "error"
 )
 
+// IsActive reports whether a target with this status is benchmarking on its
+// schedule, whether or not its runs are succeeding
+func (s TargetStatus) IsActive() bool {
+	return s == TargetStatusRunning || s == TargetStatusDegraded || s == TargetStatusError
+}
+
+// validTargetStatuses are the statuses a target can be listed by
+var validTargetStatuses = []TargetStatus{
+	TargetStatusStopped, TargetStatusRunning, TargetStatusStarting, TargetStatusDegraded, TargetStatusError,
+}
+
+// TargetFilter selects and pages the targets returned by ListTargets. Zero
+// values match everything; a Limit of 0 means no limit.
+type TargetFilter struct {
+	Environment string
+	Status      TargetStatus
+	Query       string            // case-insensitive name substring
+	Tags        map[string]string // required tags; an empty value matches any
+	Limit       int
+	Offset      int
+}
+
 // TargetResponse is the response for a single target
 type TargetResponse struct {
 	Name        string                 `json:"name"`
@@ -41,11 +95,203 @@ type TargetResponse struct {
 	RequestType string                 `json:"request_type,omitempty"`
 	LastRunAt   *time.Time             `json:"last_run_at,omitempty"`
 	LastResults *parser.ParsedResults  `json:"last_results,omitempty"`
+	LastError   string                 `json:"last_error,omitempty"` // most recent run failure
+	LastErrorAt *time.Time             `json:"last_error_at,omitempty"`
+	Override    *TargetOverride        `json:"override,omitempty"`
+
+	// ConsecutiveFailures is the current streak of failed runs
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// NextRunAt is when the target is next scheduled to run, omitted while
+	// it is stopped or the scheduler is paused. A run in progress past it
+	// delays it.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+
+	// Pause is the target's own schedule pause, if any. The global
+	// scheduler pause is reported by the scheduler status instead.
+	Pause *TargetPause `json:"pause,omitempty"`
+
+	// RemainingRuns is how many scheduled runs the target has left before
+	// it stops itself, for targets with max_runs
+	RemainingRuns *int `json:"remaining_runs,omitempty"`
+
+	// AutoStart is whether the target is started when the runner
+	// auto-starts targets, false for targets with auto_start off
+	AutoStart bool `json:"auto_start"`
+
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Reasons a target's schedule is paused
+const (
+	PauseReasonAPI       = "api"        // paused via the API until resumed
+	PauseReasonManualRun = "manual_run" // held during and after a manual run
+)
+
+// TargetPause describes a pause of a single target's scheduled runs
+type TargetPause struct {
+	Reason   string     `json:"reason"`
+	PausedAt time.Time  `json:"paused_at"`
+	Until    *time.Time `json:"until,omitempty"` // omitted until resumed, or while a manual run is in flight
+}
+
+// ResultsResponse is the response for a target's latest results. IsRunning
+// reports that a run is in flight, so fresher results are imminent.
+type ResultsResponse struct {
+	Name      string                `json:"name"`
+	Results   *parser.ParsedResults `json:"results"`
+	LastRunAt *time.Time            `json:"last_run_at,omitempty"`
+	IsRunning bool                  `json:"is_running"`
+	Message   string                `json:"message,omitempty"`
+
+	// Sweep holds the runs of the target's latest complete sweep, one per
+	// step, if it has a sweep
+	Sweep []RunResponse `json:"sweep,omitempty"`
+}
+
+// RunResults are the parsed results of one completed run
+type RunResults struct {
+	At      time.Time             `json:"at"`
+	Results *parser.ParsedResults `json:"results"`
+}
+
+// HistoryPercentilesResponse is the response for latency percentiles merged
+// across a target's runs in a time window
+type HistoryPercentilesResponse struct {
+	Name   string     `json:"name"`
+	Window string     `json:"window"`
+	Runs   int        `json:"runs"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+	Method string     `json:"method"`
+
+	TTFT *PercentileSummary `json:"ttft_seconds,omitempty"`
+	ITL  *PercentileSummary `json:"itl_seconds,omitempty"`
+	E2E  *PercentileSummary `json:"e2e_latency_seconds,omitempty"`
+}
+
+// CompareRequest selects two runs of a target to compare, and optionally
+// the thresholds they are judged by
+type CompareRequest struct {
+	Baseline           string   // run ID
+	Current            string   // run ID, or "" for the latest run
+	MaxThroughputDrop  *float64 // percent
+	MaxLatencyIncrease *float64 // percent
+}
+
+// CompareResponse is the response for comparing two runs of a target. It
+// passes if no metric regressed beyond its threshold.
+type CompareResponse struct {
+	Name     string      `json:"name"`
+	Baseline ComparedRun `json:"baseline"`
+	Current  ComparedRun `json:"current"`
+
+	MaxThroughputDropPercent  float64 `json:"max_throughput_drop_percent"`
+	MaxLatencyIncreasePercent float64 `json:"max_latency_increase_percent"`
+
+	Metrics []MetricComparison `json:"metrics"`
+	Pass    bool               `json:"pass"`
+}
+
+// ComparedRun identifies a run in a comparison
+type ComparedRun struct {
+	RunID string    `json:"run_id"`
+	At    time.Time `json:"at"`
+}
+
+// MetricComparison compares one metric between two runs. DeltaPercent is
+// omitted when the baseline is 0.
+type MetricComparison struct {
+	Metric         string   `json:"metric"` // e.g. output_tokens_per_second or ttft_seconds_p95
+	Baseline       float64  `json:"baseline"`
+	Current        float64  `json:"current"`
+	DeltaPercent   *float64 `json:"delta_percent,omitempty"`
+	HigherIsBetter bool     `json:"higher_is_better"`
+	Pass           bool     `json:"pass"`
+}
+
+// BaselineRequest is the optional request body for pinning a target's
+// baseline
+type BaselineRequest struct {
+	RunID string `json:"run_id,omitempty"` // a run in the target's history; default the latest
+}
+
+// BaselineResponse is a target's pinned baseline: the results of one of its
+// runs, which summaries and regression alerts are measured against
+type BaselineResponse struct {
+	Name    string                `json:"name"`
+	RunID   string                `json:"run_id"`
+	At      time.Time             `json:"at"`     // when the baseline run completed
+	SetAt   time.Time             `json:"set_at"` // when it was pinned
+	Results *parser.ParsedResults `json:"results"`
+}
+
+// BaselineDeltas holds the percentage change of a target's latest results
+// from its pinned baseline. A delta is omitted when either side lacks the
+// metric or the baseline's is 0.
+type BaselineDeltas struct {
+	RunID              string   `json:"run_id"`
+	OutputTokensPerSec *float64 `json:"output_tokens_per_second_percent,omitempty"`
+	RequestsPerSec     *float64 `json:"requests_per_second_percent,omitempty"`
+	TTFTP95            *float64 `json:"ttft_seconds_p95_percent,omitempty"`
+	E2EP95             *float64 `json:"e2e_latency_seconds_p95_percent,omitempty"`
+}
+
+// PercentileSummary holds percentile estimates for a latency metric
+type PercentileSummary struct {
+	Samples int     `json:"samples"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+}
+
+// FailuresResponse is the response for the breakdown of recent run failures
+// across all targets
+type FailuresResponse struct {
+	Window   string           `json:"window"`
+	Total    int              `json:"total"`
+	ByStage  map[string]int   `json:"by_stage"`  // setup, exec, parse or empty
+	ByReason map[string]int   `json:"by_reason"` // failure category
+	Targets  []TargetFailures `json:"targets"`   // most failures first
+}
+
+// TargetFailures is one target's share of a FailuresResponse
+type TargetFailures struct {
+	Name          string         `json:"name"`
+	Environment   string         `json:"environment"`
+	Total         int            `json:"total"`
+	ByStage       map[string]int `json:"by_stage"`
+	ByReason      map[string]int `json:"by_reason"`
+	LastFailureAt time.Time      `json:"last_failure_at"`
+	LastError     string         `json:"last_error"`
+}
+
+// OverrideRequest is the request body for temporarily overriding a target's
+// benchmark settings
+type OverrideRequest struct {
+	Rate            *float64 `json:"rate,omitempty"`
+	MaxSeconds      *int     `json:"max_seconds,omitempty"`
+	Profile         string   `json:"profile,omitempty"`
+	DurationSeconds int      `json:"duration_seconds"`
+}
+
+// TargetOverride is a temporary override of a target's settings, applied to
+// its runs until ExpiresAt
+type TargetOverride struct {
+	Rate       *float64  `json:"rate,omitempty"`
+	MaxSeconds *int      `json:"max_seconds,omitempty"`
+	Profile    string    `json:"profile,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
-// ListTargetsResponse is the response for listing all targets
+// ListTargetsResponse is the response for listing targets: one page of the
+// matching targets sorted by name, and how many matched in total
 type ListTargetsResponse struct {
 	Targets []TargetResponse `json:"targets"`
+	Total   int              `json:"total"`
+	Limit   int              `json:"limit,omitempty"`
+	Offset  int              `json:"offset,omitempty"`
 }
 
 // StatusResponse is the response for the runner status endpoint
@@ -54,8 +300,120 @@ type StatusResponse struct {
 	TargetsCount  int    `json:"targets_count"`
 	ActiveCount   int    `json:"active_count"`
 	StoppedCount  int    `json:"stopped_count"`
+	InFlightRuns  int    `json:"in_flight_runs"`
 	UptimeSeconds int64  `json:"uptime_seconds"`
 	Version       string `json:"version,omitempty"`
+
+	// GuideLLMVersion is the version reported by the guidellm binary
+	GuideLLMVersion string `json:"guidellm_version,omitempty"`
+}
+
+// VersionResponse is the response for the version endpoint: the runner's
+// build information and the version of the guidellm binary it runs
+type VersionResponse struct {
+	Version         string `json:"version"`
+	GitCommit       string `json:"git_commit"`
+	BuildDate       string `json:"build_date"`
+	GuideLLMVersion string `json:"guidellm_version,omitempty"` // empty if not detected
+}
+
+// SummaryResponse is the response for the fleet summary: every target's
+// latest results in one payload, sorted by name
+type SummaryResponse struct {
+	Targets []TargetSummary `json:"targets"`
+
+	// Fleet throughput: the sum of the latest output tokens per second of
+	// the FleetTargets targets that ran within the freshness window
+	FleetOutputTokensPerSec float64 `json:"fleet_output_tokens_per_second"`
+	FleetTargets            int     `json:"fleet_targets"`
+}
+
+// GroupSummaryResponse is the response for the summary of a target group.
+// Throughput is summed as for the fleet, over the ReportingTargets members
+// that ran within the freshness window.
+type GroupSummaryResponse struct {
+	Name    string          `json:"name"`
+	Targets []TargetSummary `json:"targets"`
+
+	OutputTokensPerSec float64 `json:"output_tokens_per_second"`
+	ReportingTargets   int     `json:"reporting_targets"`
+}
+
+// MetricsSnapshotResponse is the response for GET /api/metrics/snapshot:
+// the values of the runner's main gauges, for when Prometheus isn't
+// scraping. They are derived from the runner's own state rather than read
+// from the metrics registry, so they can differ slightly, e.g. a sweep's
+// steps each have their own series but only the latest step is reported.
+type MetricsSnapshotResponse struct {
+	At                      time.Time               `json:"at"`
+	FleetOutputTokensPerSec float64                 `json:"fleet_output_tokens_per_second"`
+	Targets                 []TargetMetricsSnapshot `json:"targets"`
+}
+
+// TargetMetricsSnapshot holds a target's gauge values, sorted by name in a
+// snapshot. Result gauges are omitted until a run has produced results, and
+// keep their values through later failed runs, as the gauges do.
+type TargetMetricsSnapshot struct {
+	Name                   string     `json:"name"`
+	Environment            string     `json:"environment"`
+	Model                  string     `json:"model"`
+	Up                     bool       `json:"up"` // guidellm_runner_up: the target is scheduled
+	OutputTokensPerSec     *float64   `json:"output_tokens_per_second,omitempty"`
+	RequestsPerSec         *float64   `json:"requests_per_second,omitempty"`
+	RequestSuccessRate     *float64   `json:"request_success_rate,omitempty"`
+	LastBenchmarkTimestamp *time.Time `json:"last_benchmark_timestamp,omitempty"`
+	ConsecutiveFailures    int        `json:"consecutive_failures"`
+}
+
+// TargetConfigResponse is the settings a target's scheduled runs use, with
+// the defaults and any active override resolved. A sweep's steps override
+// profile and rate in turn.
+type TargetConfigResponse struct {
+	Name        string   `json:"name"`
+	Environment string   `json:"environment"`
+	URL         string   `json:"url"`
+	Model       string   `json:"model"`
+	Profile     string   `json:"profile"`
+	Rate        *float64 `json:"rate,omitempty"` // omitted for profiles without a rate
+	MaxSeconds  int      `json:"max_seconds"`
+	RequestType string   `json:"request_type"`
+	Interval    int      `json:"interval"`    // seconds between scheduled runs
+	RunTimeout  int      `json:"run_timeout"` // seconds
+	Stream      bool     `json:"stream"`
+	DataSpec    string   `json:"data_spec"`
+	Processor   string   `json:"processor,omitempty"`
+	WarmupRuns  int      `json:"warmup_runs"`
+	MaxRuns     *int     `json:"max_runs,omitempty"`
+	AutoStart   bool     `json:"auto_start"`
+
+	// Command is the guidellm command line a scheduled run executes, with
+	// the API key redacted and a placeholder for its output directory
+	Command []string `json:"command,omitempty"`
+}
+
+// TargetSummary condenses a target's latest run. Result fields are zero
+// (and latencies omitted) until a run has completed.
+type TargetSummary struct {
+	Name               string          `json:"name"`
+	Environment        string          `json:"environment"`
+	Model              string          `json:"model"`
+	Status             TargetStatus    `json:"status"`
+	LastRunAt          *time.Time      `json:"last_run_at,omitempty"`
+	OutputTokensPerSec float64         `json:"output_tokens_per_second"`
+	RequestsPerSec     float64         `json:"requests_per_second"`
+	SuccessRate        *float64        `json:"success_rate,omitempty"` // successful / total requests
+	TTFT               *LatencySummary `json:"ttft_seconds,omitempty"`
+	E2E                *LatencySummary `json:"e2e_latency_seconds,omitempty"`
+
+	// VsBaseline compares the latest results with the pinned baseline, if
+	// one is set
+	VsBaseline *BaselineDeltas `json:"vs_baseline,omitempty"`
+}
+
+// LatencySummary holds the median and tail of a latency metric
+type LatencySummary struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
 }
 
 // HealthResponse is the response for the health endpoint
@@ -67,6 +425,10 @@ type HealthResponse struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+
+	// Fields gives the problem with each invalid field by name, for a
+	// request with more than one (422 Unprocessable Entity)
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // TargetActionResponse is the response for start/stop actions
@@ -76,10 +438,65 @@ type TargetActionResponse struct {
 	Message string       `json:"message,omitempty"`
 }
 
+// Actions accepted by the bulk target action endpoint
+const (
+	BulkActionStart = "start"
+	BulkActionStop  = "stop"
+)
+
+// BulkActionRequest is the request body for starting or stopping several
+// targets at once
+type BulkActionRequest struct {
+	Action string   `json:"action"` // start or stop
+	Names  []string `json:"names"`
+}
+
+// BulkActionResponse reports the outcome of a bulk action for each target.
+// A failure for one target doesn't stop the action for the rest.
+type BulkActionResponse struct {
+	Action    string             `json:"action"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []BulkActionResult `json:"results"`
+}
+
+// BulkActionResult is the outcome of a bulk action for one target
+type BulkActionResult struct {
+	Name    string       `json:"name"`
+	Status  TargetStatus `json:"status,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
 // TriggerRunRequest is the request body for triggering a manual benchmark run
 type TriggerRunRequest struct {
-	RunID           string                 `json:"run_id"`
-	ConfigOverrides map[string]interface{} `json:"config_overrides,omitempty"`
+	RunID           string        `json:"run_id"`
+	ConfigOverrides *RunOverrides `json:"config_overrides,omitempty"`
+}
+
+// RunOverrides override a target's settings for a single manual run,
+// leaving its stored configuration unchanged
+type RunOverrides struct {
+	Rate       *float64 `json:"rate,omitempty"`
+	MaxSeconds *int     `json:"max_seconds,omitempty"`
+	Profile    string   `json:"profile,omitempty"`
+	DataSpec   string   `json:"data_spec,omitempty"`
+}
+
+// UnmarshalJSON rejects unknown keys, so a misspelled override fails the
+// request rather than being silently ignored
+func (o *RunOverrides) UnmarshalJSON(data []byte) error {
+	type plain RunOverrides
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*plain)(o))
+}
+
+// ManualRunRequest is the request body for triggering manual runs of every
+// running target, or of one target if Target is set
+type ManualRunRequest struct {
+	RunID  string `json:"run_id"`
+	Target string `json:"target,omitempty"`
 }
 
 // TriggerRunResponse is the response for a triggered benchmark run
@@ -91,6 +508,43 @@ type TriggerRunResponse struct {
 	Error   string                 `json:"error,omitempty"`
 }
 
+// RunStatus is the state of a benchmark run
+type RunStatus string
+
+const (
+	RunStatusPending RunStatus = "pending" // accepted, not yet started
+	RunStatusRunning RunStatus = "running"
+	RunStatusDone    RunStatus = "done"
+	RunStatusFailed  RunStatus = "failed"
+)
+
+// RunResponse describes a benchmark run and, once it has finished, its
+// results or error
+type RunResponse struct {
+	RunID      string                `json:"run_id"`
+	Target     string                `json:"target"`
+	Trigger    string                `json:"trigger"` // scheduled or manual
+	Overrides  *RunOverrides         `json:"overrides,omitempty"`
+	Status     RunStatus             `json:"status"`
+
+	// The run's actual load profile and rate, after any overrides or
+	// sweep step
+	Profile string  `json:"profile,omitempty"`
+	Rate    float64 `json:"rate,omitempty"`
+
+	CreatedAt  time.Time             `json:"created_at"`
+	StartedAt  *time.Time            `json:"started_at,omitempty"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+	Results    *parser.ParsedResults `json:"results,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// ListRunsResponse is the response for listing runs
+type ListRunsResponse struct {
+	Runs  []RunResponse `json:"runs"`
+	Total int           `json:"total"`
+}
+
 // SchedulerState represents the current state of the scheduler
 type SchedulerState string
 
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -13,7 +17,50 @@ type Config struct {
 	Environments map[string]Environment `yaml:"environments"`
 	Defaults     Defaults               `yaml:"defaults"`
 	Prometheus   PrometheusConfig       `yaml:"prometheus"`
+	Metrics      MetricsConfig          `yaml:"metrics,omitempty"`
 	Discovery    DiscoveryConfig        `yaml:"discovery,omitempty"`
+	API          APIConfig              `yaml:"api,omitempty"`
+	Startup      StartupConfig          `yaml:"startup,omitempty"`
+	Shutdown     ShutdownConfig         `yaml:"shutdown,omitempty"`
+	Webhooks     WebhookConfig          `yaml:"webhooks,omitempty"`
+
+	// GuideLLMBinary is the guidellm executable to run, either a name looked
+	// up on PATH or a path (e.g. into a virtualenv)
+	GuideLLMBinary string `yaml:"guidellm_binary,omitempty"`
+
+	// StrictGuideLLMVersion refuses to start with a guidellm version outside
+	// the tested range, rather than just warning
+	StrictGuideLLMVersion bool `yaml:"strict_guidellm_version,omitempty"`
+
+	// GuideLLMProfiles replaces the load profiles targets may use
+	// (ValidProfiles), e.g. to allow a profile added in a newer guidellm
+	GuideLLMProfiles []string `yaml:"guidellm_profiles,omitempty"`
+
+	// Subprocess sets guidellm's working directory and which of the
+	// runner's environment variables it sees
+	Subprocess SubprocessConfig `yaml:"subprocess,omitempty"`
+
+	// ArchiveRawOutput keeps the raw guidellm JSON of each target's latest
+	// run in memory so it can be exported with ?format=guidellm
+	ArchiveRawOutput bool `yaml:"archive_raw_output,omitempty"`
+
+	// KeepRawOutput copies each run's raw guidellm output file into
+	// RawOutputDir, named by target and timestamp, instead of deleting it
+	// with the run's temp directory
+	KeepRawOutput bool   `yaml:"keep_raw_output,omitempty"`
+	RawOutputDir  string `yaml:"raw_output_dir,omitempty"` // default results/raw
+
+	// ResultsDir, if set, is a directory each completed run's results are
+	// appended to as a line of JSON, in one file per day
+	ResultsDir string `yaml:"results_dir,omitempty"`
+
+	// ResultsS3 uploads each completed run's raw guidellm output and
+	// parsed results to an S3-compatible bucket. Disabled if Bucket is empty.
+	ResultsS3 S3Config `yaml:"results_s3,omitempty"`
+
+	// Groups name sets of targets that can be started, stopped and
+	// summarized together
+	Groups map[string]Group `yaml:"groups,omitempty"`
 }
 
 // Environment represents a deployment environment (e.g., develop, staging)
@@ -28,11 +75,69 @@ type Target struct {
 	Model     string `yaml:"model"`
 	APIKey    string `yaml:"api_key,omitempty"`
 
+	// APIKeyFile is a file the API key is read from at load time, e.g. a
+	// mounted Kubernetes secret. Mutually exclusive with APIKey.
+	APIKeyFile string `yaml:"api_key_file,omitempty"`
+
 	// Per-target overrides (optional)
-	Profile     string   `yaml:"profile,omitempty"`
-	Rate        *float64 `yaml:"rate,omitempty"`
-	MaxSeconds  *int     `yaml:"max_seconds,omitempty"`
-	RequestType string   `yaml:"request_type,omitempty"` // chat_completions or text_completions
+	Profile      string   `yaml:"profile,omitempty"`
+	Rate         *float64 `yaml:"rate,omitempty"`
+	MaxSeconds   *int     `yaml:"max_seconds,omitempty"`
+	RequestType  string   `yaml:"request_type,omitempty"`  // chat_completions or text_completions
+	HealthPath   string   `yaml:"health_path,omitempty"`   // reachability probe path
+	Stream       *bool    `yaml:"stream,omitempty"`        // stream responses (default false)
+	ProbeTimeout *int     `yaml:"probe_timeout,omitempty"` // seconds, for endpoint probes
+
+	// ChatFormatter selects how chat_completions messages are formatted
+	// (see Defaults.ChatFormatter)
+	ChatFormatter string `yaml:"chat_formatter,omitempty"`
+
+	// ExtraArgs are appended verbatim to the guidellm command, for flags the
+	// runner doesn't model (e.g. ["--warmup", "0.1"])
+	ExtraArgs []string `yaml:"extra_args,omitempty"`
+
+	// WarmupRuns overrides Defaults.WarmupRuns
+	WarmupRuns *int `yaml:"warmup_runs,omitempty"`
+
+	// MaxRuns stops the target after this many scheduled runs (a sweep
+	// counting as one) each time it is started, e.g. for one-shot CI jobs.
+	// Unset runs until stopped.
+	MaxRuns *int `yaml:"max_runs,omitempty"`
+
+	// AutoStart set to false leaves the target stopped when the runner
+	// auto-starts targets, e.g. for expensive targets only started on
+	// demand (default true)
+	AutoStart *bool `yaml:"auto_start,omitempty"`
+
+	// Processor overrides Defaults.Processor, e.g. with the target model's
+	// own tokenizer
+	Processor string `yaml:"processor,omitempty"`
+
+	// DataSpec overrides Defaults.DataSpec, the data guidellm sends
+	DataSpec string `yaml:"data_spec,omitempty"`
+
+	// Sweep runs the target at each of several rates and/or profiles in
+	// turn every scheduled cycle, instead of once at its own rate and
+	// profile. Manual runs aren't swept.
+	Sweep []SweepStep `yaml:"sweep,omitempty"`
+
+	// SweepLabels labels the target's request, token, throughput and
+	// latency metrics with each run's rate and profile, so sweep steps get
+	// separate series instead of overwriting each other. Every distinct
+	// rate and profile adds a full set of series, histogram buckets
+	// included, that lasts until the target is removed.
+	SweepLabels bool `yaml:"sweep_labels,omitempty"`
+
+	// BackendKwargs are merged over Defaults.BackendKwargs, key by key
+	BackendKwargs map[string]interface{} `yaml:"backend_kwargs,omitempty"`
+
+	// Throughput regression alerting (see Defaults.RegressionThreshold)
+	RegressionThreshold *float64 `yaml:"regression_threshold,omitempty"` // percent drop
+	RegressionWindow    *int     `yaml:"regression_window,omitempty"`    // baseline runs
+
+	// Tags group targets by e.g. team, tier or hardware, for filtering the
+	// target list. Only keys in Prometheus.TagLabels are exported.
+	Tags map[string]string `yaml:"tags,omitempty"`
 }
 
 // Defaults contains default benchmark settings
@@ -44,17 +149,252 @@ type Defaults struct {
 	MaxTokens   int     `yaml:"max_tokens"`
 	DataSpec    string  `yaml:"data_spec"`    // e.g., "prompt_tokens=256,output_tokens=128"
 	RequestType string  `yaml:"request_type"` // chat_completions or text_completions
+
+	// Outer deadline for a run's guidellm subprocess, in case it hangs.
+	// When RunTimeout is 0 it is derived from the target's max_seconds as
+	// max_seconds * RunTimeoutMultiplier + RunTimeoutPadding.
+	RunTimeout           int     `yaml:"run_timeout"`            // seconds
+	RunTimeoutMultiplier float64 `yaml:"run_timeout_multiplier"` // default 2
+	RunTimeoutPadding    int     `yaml:"run_timeout_padding"`    // seconds, default 60
+
+	// CheckModelOnZeroRequests probes the target's /v1/models after a
+	// zero-request run to detect a model the endpoint doesn't serve
+	CheckModelOnZeroRequests bool `yaml:"check_model_on_zero_requests"`
+
+	// DiagnoseFailures, after a zero-request run, requests the target's
+	// /v1/models and a one-token test completion and logs each answer's
+	// status and body, to show e.g. a 401 or an unknown model
+	DiagnoseFailures bool `yaml:"diagnose_failures"`
+
+	// HealthPath is the path probed to check a target is reachable. Empty
+	// means the target's /v1/models.
+	HealthPath string `yaml:"health_path"`
+
+	// ProbeTimeout bounds endpoint probes (reachability and model checks),
+	// in seconds. 0 means DefaultProbeTimeout.
+	ProbeTimeout int `yaml:"probe_timeout"`
+
+	// MaxConcurrentRuns caps how many guidellm subprocesses run at once
+	// across all targets; further runs queue for a slot. 0 means unlimited.
+	MaxConcurrentRuns int `yaml:"max_concurrent_runs"`
+
+	// APIKeyFile is read for targets that set neither api_key nor
+	// api_key_file
+	APIKeyFile string `yaml:"api_key_file"`
+
+	// ErrorThreshold is how many consecutive failed runs mark a running
+	// target as error rather than degraded (default 3)
+	ErrorThreshold int `yaml:"error_threshold"`
+
+	// MaxObservationsPerRun is the number of histogram observations a single
+	// run may record before a warning is logged (default 10000)
+	MaxObservationsPerRun int `yaml:"max_observations_per_run"`
+
+	// Processor is the tokenizer guidellm counts tokens with: a Hugging
+	// Face model ID or a local path (default DefaultProcessor). Token counts,
+	// and so tokens-per-second, are only exact with the model's own
+	// tokenizer.
+	Processor string `yaml:"processor"`
+
+	// BackendKwargs are passed to guidellm's --backend-kwargs as JSON,
+	// merged over {"validate_backend": false}, e.g. to turn backend
+	// validation on or set timeouts and headers
+	BackendKwargs map[string]interface{} `yaml:"backend_kwargs"`
+
+	// WarmupRuns is how many benchmark runs are made and discarded each time
+	// a target starts, before its runs are recorded, so a cold server's
+	// first numbers don't pollute metrics or baselines (default 0)
+	WarmupRuns int `yaml:"warmup_runs"`
+
+	// ChatFormatter selects how chat_completions requests carry their
+	// prompt: "multimodal" (guidellm's default, an array of content parts,
+	// which vLLM rejects) or "text" (a plain string). Only valid with the
+	// chat_completions request type.
+	ChatFormatter string `yaml:"chat_formatter"`
+
+	// RegressionThreshold alerts via the webhook when a run's output tokens
+	// per second falls more than this percentage below the mean of the
+	// target's previous RegressionWindow runs. 0, the default, disables
+	// alerting.
+	RegressionThreshold float64 `yaml:"regression_threshold"`
+
+	// RegressionWindow is how many previous runs are averaged into the
+	// baseline (default 5)
+	RegressionWindow int `yaml:"regression_window"`
+
+	// Jitter shifts each scheduled run by a random amount of up to this
+	// fraction of the interval either way (e.g. 0.1 for ±10%), so targets
+	// sharing an inference server drift apart instead of running in step.
+	// It also delays a target's first run by up to that much. 0 disables.
+	Jitter float64 `yaml:"jitter"`
+
+	// RunOnStart makes a target's first run as soon as it starts (default
+	// true). When false, the first run waits a random delay of up to one
+	// interval, staggering targets across it.
+	RunOnStart *bool `yaml:"run_on_start"`
 }
 
 // PrometheusConfig contains Prometheus exporter settings
 type PrometheusConfig struct {
 	Port int `yaml:"port"`
+
+	// DataKindLabel exports guidellm_target_data_kind, labelling each target
+	// with whether its benchmarks use synthetic or dataset prompts
+	DataKindLabel bool `yaml:"data_kind_label,omitempty"`
+
+	// Exemplars attaches each run's ID to its latency histogram observations
+	// as an exemplar. Exemplars are only exposed in the OpenMetrics format,
+	// which this also enables on /metrics.
+	Exemplars bool `yaml:"exemplars,omitempty"`
+
+	// TagLabels are the target tag keys exported as labels of
+	// guidellm_target_tags, one tag_<key> label each. Tags not listed
+	// aren't exported, keeping free-form tags from adding series.
+	TagLabels []string `yaml:"tag_labels,omitempty"`
+
+	// Pushgateway pushes metrics to a Prometheus Pushgateway, for --once
+	// runs that exit before they could be scraped
+	Pushgateway PushgatewayConfig `yaml:"pushgateway,omitempty"`
+}
+
+// PushgatewayConfig contains settings for pushing each target's metrics to a
+// Prometheus Pushgateway after every run, grouped by environment and
+// target. Pushing is disabled when URL is empty; /metrics is served either
+// way.
+type PushgatewayConfig struct {
+	URL string `yaml:"url"`
+	Job string `yaml:"job"` // default guidellm-runner
+}
+
+// MetricsConfig contains settings for the exported metrics
+type MetricsConfig struct {
+	Buckets BucketsConfig `yaml:"buckets,omitempty"`
+
+	// FleetFreshness is how recently, in seconds, a target must have run
+	// to count towards the fleet throughput. 0 means twice the interval.
+	FleetFreshness int `yaml:"fleet_freshness,omitempty"`
+}
+
+// BucketsConfig holds the upper bounds, in seconds, of the latency histogram
+// buckets. An empty list keeps the built-in buckets. Changing the buckets of
+// a histogram breaks continuity with its existing series.
+type BucketsConfig struct {
+	TTFT []float64 `yaml:"ttft,omitempty"`
+	ITL  []float64 `yaml:"itl,omitempty"`
+	E2E  []float64 `yaml:"e2e,omitempty"`
+}
+
+// StartupConfig contains settings for starting targets at startup
+type StartupConfig struct {
+	// RetryAttempts is how many times a failed target start is retried
+	// (default 3; negative disables retries)
+	RetryAttempts int `yaml:"retry_attempts"`
+	// RetryBackoff is the delay before the first retry in seconds, doubling
+	// on each further attempt (default 5)
+	RetryBackoff int `yaml:"retry_backoff"`
+	// Stagger is the delay in seconds between auto-started targets' starts,
+	// spreading out their first runs instead of hitting backends all at
+	// once (default 0, starting them together)
+	Stagger float64 `yaml:"stagger,omitempty"`
+}
+
+// ShutdownConfig contains settings for graceful shutdown
+type ShutdownConfig struct {
+	// Timeout bounds the whole shutdown in seconds, including draining runs
+	// and delivering their results (default 30; overridden by
+	// --shutdown-timeout)
+	Timeout int `yaml:"timeout,omitempty"`
+
+	// DrainGrace is how long in seconds benchmark runs in flight at
+	// shutdown may take to finish before they are cancelled, within the
+	// timeout (default two thirds of the timeout, leaving the rest to
+	// deliver their results; 0 cancels them straight away)
+	DrainGrace *int `yaml:"drain_grace,omitempty"`
+}
+
+// DefaultShutdownTimeout bounds shutdown when shutdown.timeout isn't set
+const DefaultShutdownTimeout = 30 * time.Second
+
+// GetTimeout returns how long shutdown may take
+func (s ShutdownConfig) GetTimeout() time.Duration {
+	if s.Timeout > 0 {
+		return time.Duration(s.Timeout) * time.Second
+	}
+	return DefaultShutdownTimeout
+}
+
+// GetDrainGrace returns how long runs in flight at shutdown may take to
+// finish, given the shutdown timeout in effect
+func (s ShutdownConfig) GetDrainGrace(timeout time.Duration) time.Duration {
+	if s.DrainGrace != nil {
+		return time.Duration(*s.DrainGrace) * time.Second
+	}
+	return timeout * 2 / 3
+}
+
+// WebhookConfig contains settings for notifying an external system of
+// completed runs. Webhooks are disabled when URL is empty.
+type WebhookConfig struct {
+	URL string `yaml:"url"`
+
+	// Secret signs each payload with HMAC-SHA256, sent in the
+	// X-Guidellm-Signature header. Unsigned if empty.
+	Secret string `yaml:"secret,omitempty"`
+
+	// Timeout bounds each delivery attempt in seconds (default 5)
+	Timeout int `yaml:"timeout"`
+
+	// Retries is how many times a failed delivery is retried (default 2;
+	// negative disables retries)
+	Retries int `yaml:"retries"`
+
+	// Workers is how many deliveries may be in flight at once (default 2),
+	// and QueueSize how many may wait for a worker before further
+	// notifications are dropped (default 100)
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queue_size"`
+}
+
+// S3Config contains settings for an S3-compatible object store
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint"` // e.g. https://s3.us-east-1.amazonaws.com
+	Bucket          string `yaml:"bucket"`
+	Region          string `yaml:"region"`           // default us-east-1
+	Prefix          string `yaml:"prefix,omitempty"` // prepended to every object key
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+
+	// Timeout bounds each upload in seconds (default 30)
+	Timeout int `yaml:"timeout"`
+}
+
+// APIConfig contains runtime control API settings
+type APIConfig struct {
+	// RestrictEnvironments rejects targets added at runtime whose environment
+	// isn't defined under environments or discovery.environments
+	RestrictEnvironments bool `yaml:"restrict_environments,omitempty"`
+
+	// CORSAllowedOrigins lists browser origins allowed to call the API
+	// ("*" allows any). Empty means same-origin only.
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins,omitempty"`
+
+	// ProbeTargets checks that a runtime-added target's endpoint is
+	// reachable (via its /v1/models) before accepting it
+	ProbeTargets bool `yaml:"probe_targets,omitempty"`
+
+	// RunRetention is how many recent runs are kept for GET /api/runs;
+	// older runs are evicted first
+	RunRetention int `yaml:"run_retention,omitempty"`
 }
 
 // DiscoveryConfig contains model discovery settings
 type DiscoveryConfig struct {
 	Enabled     bool                       `yaml:"enabled"`
 	Environments map[string]DiscoveryEnvConfig `yaml:"environments,omitempty"`
+
+	// Background runs discovery concurrently with starting the configured
+	// targets instead of blocking startup on it
+	Background bool `yaml:"background,omitempty"`
 }
 
 // DiscoveryEnvConfig contains environment-specific discovery settings
@@ -62,8 +402,70 @@ type DiscoveryEnvConfig struct {
 	Endpoint string `yaml:"endpoint"`
 	BaseURL  string `yaml:"base_url"`
 	APIKey   string `yaml:"api_key,omitempty"`
+
+	// Optional model filters (regexes matched against model IDs)
+	Include    []string `yaml:"include,omitempty"`
+	Exclude    []string `yaml:"exclude,omitempty"`
+	ModelTypes []string `yaml:"model_types,omitempty"` // defaults to ["text"]
 }
 
+// Defaults for the derived run timeout
+const (
+	DefaultRunTimeoutMultiplier = 2.0
+	DefaultRunTimeoutPadding    = 60
+)
+
+// Defaults for retrying failed target starts
+const (
+	DefaultStartRetryAttempts = 3
+	DefaultStartRetryBackoff  = 5
+)
+
+// Defaults for webhook delivery
+const (
+	DefaultWebhookTimeout   = 5
+	DefaultWebhookRetries   = 2
+	DefaultWebhookWorkers   = 2
+	DefaultWebhookQueueSize = 100
+)
+
+// DefaultPushgatewayJob is the job metrics are pushed to the Pushgateway as
+const DefaultPushgatewayJob = "guidellm-runner"
+
+// DefaultRunRetention is how many recent runs are kept by default
+const DefaultRunRetention = 100
+
+// DefaultRawOutputDir is where raw guidellm output is kept when
+// keep_raw_output is set
+const DefaultRawOutputDir = "results/raw"
+
+// Defaults for the S3 results sink
+const (
+	DefaultS3Region  = "us-east-1"
+	DefaultS3Timeout = 30
+)
+
+// DefaultProbeTimeout is the default endpoint probe timeout in seconds,
+// matching the discovery client's request timeout
+const DefaultProbeTimeout = 10
+
+// DefaultErrorThreshold is the default number of consecutive failed runs
+// before a running target is reported as error
+const DefaultErrorThreshold = 3
+
+// DefaultProcessor is the default tokenizer. gpt2 avoids needing
+// model-specific tokenizers (many models like mistral need sentencepiece,
+// which isn't installed) at the cost of approximate token counts.
+const DefaultProcessor = "gpt2"
+
+// DefaultRegressionWindow is the default number of previous runs averaged
+// into a target's throughput baseline
+const DefaultRegressionWindow = 5
+
+// DefaultMaxObservationsPerRun is the default warning threshold for
+// histogram observations recorded by a single run
+const DefaultMaxObservationsPerRun = 10000
+
 // Load reads and parses the config file
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -76,6 +478,14 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
+	// Resolve ${VAR} references so secrets can stay out of the file
+	if err := cfg.expandEnvVars(); err != nil {
+		return nil, fmt.Errorf("expanding config: %w", err)
+	}
+	if err := cfg.loadAPIKeyFiles(); err != nil {
+		return nil, fmt.Errorf("loading api keys: %w", err)
+	}
+
 	// Apply defaults
 	if cfg.Defaults.Profile == "" {
 		cfg.Defaults.Profile = "constant"
@@ -97,9 +507,64 @@ func Load(path string) (*Config, error) {
 	}
 	if cfg.Defaults.RequestType == "" {
 		// Use text_completions because guidellm's chat_completions formatter
-		// uses multimodal content format that vLLM doesn't support
+		// uses multimodal content format that vLLM doesn't support (unless
+		// chat_formatter is set to text)
 		cfg.Defaults.RequestType = "text_completions"
 	}
+	if cfg.Defaults.RunTimeoutMultiplier == 0 {
+		cfg.Defaults.RunTimeoutMultiplier = DefaultRunTimeoutMultiplier
+	}
+	if cfg.Defaults.RunTimeoutPadding == 0 {
+		cfg.Defaults.RunTimeoutPadding = DefaultRunTimeoutPadding
+	}
+	if cfg.Defaults.ErrorThreshold == 0 {
+		cfg.Defaults.ErrorThreshold = DefaultErrorThreshold
+	}
+	if cfg.Defaults.MaxObservationsPerRun == 0 {
+		cfg.Defaults.MaxObservationsPerRun = DefaultMaxObservationsPerRun
+	}
+	if cfg.Defaults.Processor == "" {
+		cfg.Defaults.Processor = DefaultProcessor
+	}
+	if cfg.Defaults.RegressionWindow == 0 {
+		cfg.Defaults.RegressionWindow = DefaultRegressionWindow
+	}
+	if cfg.Startup.RetryAttempts == 0 {
+		cfg.Startup.RetryAttempts = DefaultStartRetryAttempts
+	}
+	if cfg.Startup.RetryBackoff == 0 {
+		cfg.Startup.RetryBackoff = DefaultStartRetryBackoff
+	}
+	if cfg.Webhooks.Timeout == 0 {
+		cfg.Webhooks.Timeout = DefaultWebhookTimeout
+	}
+	if cfg.Webhooks.Retries == 0 {
+		cfg.Webhooks.Retries = DefaultWebhookRetries
+	}
+	if cfg.Webhooks.Workers == 0 {
+		cfg.Webhooks.Workers = DefaultWebhookWorkers
+	}
+	if cfg.Webhooks.QueueSize == 0 {
+		cfg.Webhooks.QueueSize = DefaultWebhookQueueSize
+	}
+	if cfg.API.RunRetention == 0 {
+		cfg.API.RunRetention = DefaultRunRetention
+	}
+	if cfg.Prometheus.Pushgateway.Job == "" {
+		cfg.Prometheus.Pushgateway.Job = DefaultPushgatewayJob
+	}
+	if cfg.RawOutputDir == "" {
+		cfg.RawOutputDir = DefaultRawOutputDir
+	}
+	if cfg.ResultsS3.Region == "" {
+		cfg.ResultsS3.Region = DefaultS3Region
+	}
+	if cfg.ResultsS3.Timeout == 0 {
+		cfg.ResultsS3.Timeout = DefaultS3Timeout
+	}
+	if cfg.GuideLLMBinary == "" {
+		cfg.GuideLLMBinary = "guidellm"
+	}
 	if cfg.Prometheus.Port == 0 {
 		cfg.Prometheus.Port = 9090
 	}
@@ -107,11 +572,58 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// HasEnvironment reports whether name is defined in the config, either as a
+// static environment or a discovery environment
+func (c *Config) HasEnvironment(name string) bool {
+	if _, ok := c.Environments[name]; ok {
+		return true
+	}
+	_, ok := c.Discovery.Environments[name]
+	return ok
+}
+
+// ValidateURL checks that a target URL is an absolute http(s) URL with a host
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", raw)
+	}
+	return nil
+}
+
+// ValidateHealthPath checks that a health probe path is a plain absolute
+// path rather than a full URL
+func ValidateHealthPath(path string) error {
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid health_path %q: %w", path, err)
+	}
+	if u.Scheme != "" || u.Host != "" || !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("invalid health_path %q: must be a path starting with /, not a URL", path)
+	}
+	return nil
+}
+
 // GetInterval returns the interval duration
 func (c *Config) GetInterval() time.Duration {
 	return time.Duration(c.Defaults.Interval) * time.Second
 }
 
+// GetFleetFreshness returns how recently a target must have run to count
+// towards the fleet throughput
+func (c *Config) GetFleetFreshness() time.Duration {
+	if c.Metrics.FleetFreshness > 0 {
+		return time.Duration(c.Metrics.FleetFreshness) * time.Second
+	}
+	return 2 * c.GetInterval()
+}
+
 // GetRate returns the effective rate for a target
 func (t *Target) GetRate(defaults Defaults) float64 {
 	if t.Rate != nil {
@@ -120,6 +632,104 @@ func (t *Target) GetRate(defaults Defaults) float64 {
 	return defaults.Rate
 }
 
+// GetHealthPath returns the effective health probe path for a target
+// (empty means the target's /v1/models)
+func (t *Target) GetHealthPath(defaults Defaults) string {
+	if t.HealthPath != "" {
+		return t.HealthPath
+	}
+	return defaults.HealthPath
+}
+
+// GetProbeTimeout returns the effective endpoint probe timeout for a target
+func (t *Target) GetProbeTimeout(defaults Defaults) time.Duration {
+	seconds := defaults.ProbeTimeout
+	if t.ProbeTimeout != nil {
+		seconds = *t.ProbeTimeout
+	}
+	if seconds <= 0 {
+		seconds = DefaultProbeTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetRunOnStart returns whether targets run as soon as they start
+func (d Defaults) GetRunOnStart() bool {
+	return d.RunOnStart == nil || *d.RunOnStart
+}
+
+// GetErrorThreshold returns the consecutive failed runs before a running
+// target is reported as error
+func (d Defaults) GetErrorThreshold() int {
+	if d.ErrorThreshold <= 0 {
+		return DefaultErrorThreshold
+	}
+	return d.ErrorThreshold
+}
+
+// GetProcessor returns the tokenizer guidellm should use for the target
+func (t *Target) GetProcessor(defaults Defaults) string {
+	if t.Processor != "" {
+		return t.Processor
+	}
+	if defaults.Processor != "" {
+		return defaults.Processor
+	}
+	return DefaultProcessor
+}
+
+// DefaultBackendKwargs are the backend kwargs used unless overridden.
+// Backend validation is off as it fails against some vLLM deployments.
+var DefaultBackendKwargs = map[string]interface{}{"validate_backend": false}
+
+// GetBackendKwargs returns the target's --backend-kwargs JSON: the default
+// kwargs, overridden key by key by the defaults' and then the target's
+func (t *Target) GetBackendKwargs(defaults Defaults) (string, error) {
+	kwargs := make(map[string]interface{}, len(DefaultBackendKwargs)+len(defaults.BackendKwargs)+len(t.BackendKwargs))
+	for _, layer := range []map[string]interface{}{DefaultBackendKwargs, defaults.BackendKwargs, t.BackendKwargs} {
+		for k, v := range layer {
+			kwargs[k] = v
+		}
+	}
+
+	data, err := json.Marshal(kwargs)
+	if err != nil {
+		return "", fmt.Errorf("backend_kwargs cannot be encoded as JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// GetWarmupRuns returns how many discarded warmup runs the target makes when
+// it starts
+func (t *Target) GetWarmupRuns(defaults Defaults) int {
+	if t.WarmupRuns != nil {
+		return max(*t.WarmupRuns, 0)
+	}
+	return max(defaults.WarmupRuns, 0)
+}
+
+// GetRegressionThreshold returns the percentage drop in throughput that
+// raises a regression alert for the target (0 if alerting is disabled)
+func (t *Target) GetRegressionThreshold(defaults Defaults) float64 {
+	if t.RegressionThreshold != nil {
+		return *t.RegressionThreshold
+	}
+	return defaults.RegressionThreshold
+}
+
+// GetRegressionWindow returns how many previous runs make up the target's
+// throughput baseline
+func (t *Target) GetRegressionWindow(defaults Defaults) int {
+	runs := defaults.RegressionWindow
+	if t.RegressionWindow != nil {
+		runs = *t.RegressionWindow
+	}
+	if runs <= 0 {
+		return DefaultRegressionWindow
+	}
+	return runs
+}
+
 // GetMaxSeconds returns the effective max_seconds for a target
 func (t *Target) GetMaxSeconds(defaults Defaults) int {
 	if t.MaxSeconds != nil {
@@ -128,6 +738,26 @@ func (t *Target) GetMaxSeconds(defaults Defaults) int {
 	return defaults.MaxSeconds
 }
 
+// GetRunTimeout returns the outer deadline for a target's guidellm run: the
+// explicit run_timeout if set, otherwise derived from its max_seconds
+func (t *Target) GetRunTimeout(defaults Defaults) time.Duration {
+	if defaults.RunTimeout > 0 {
+		return time.Duration(defaults.RunTimeout) * time.Second
+	}
+
+	multiplier := defaults.RunTimeoutMultiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRunTimeoutMultiplier
+	}
+	padding := defaults.RunTimeoutPadding
+	if padding <= 0 {
+		padding = DefaultRunTimeoutPadding
+	}
+
+	seconds := float64(t.GetMaxSeconds(defaults))*multiplier + float64(padding)
+	return time.Duration(seconds * float64(time.Second))
+}
+
 // GetProfile returns the effective profile for a target
 func (t *Target) GetProfile(defaults Defaults) string {
 	if t.Profile != "" {
@@ -136,6 +766,76 @@ func (t *Target) GetProfile(defaults Defaults) string {
 	return defaults.Profile
 }
 
+// GetDataSpec returns the effective data spec for a target
+func (t *Target) GetDataSpec(defaults Defaults) string {
+	if t.DataSpec != "" {
+		return t.DataSpec
+	}
+	return defaults.DataSpec
+}
+
+// StreamingRequestTypes are the request types guidellm can stream
+var StreamingRequestTypes = []string{"chat_completions", "text_completions"}
+
+// GetStream reports whether the target's requests should be streamed.
+// Streaming is off by default since it causes 502 errors with some vLLM
+// deployments. TTFT and ITL are only meaningful when it is on, but are
+// recorded as guidellm reports them either way.
+func (t *Target) GetStream() bool {
+	return t.Stream != nil && *t.Stream
+}
+
+// GetAutoStart reports whether the target is started along with the other
+// targets when the runner auto-starts them
+func (t *Target) GetAutoStart() bool {
+	return t.AutoStart == nil || *t.AutoStart
+}
+
+// ValidateStream checks that streaming, if enabled, is used with a request
+// type that supports it
+func (t *Target) ValidateStream(defaults Defaults) error {
+	if !t.GetStream() {
+		return nil
+	}
+	if requestType := t.GetRequestType(defaults); !slices.Contains(StreamingRequestTypes, requestType) {
+		return fmt.Errorf("stream is not supported with request_type %q (want one of %v)", requestType, StreamingRequestTypes)
+	}
+	return nil
+}
+
+// Chat formatter modes
+const (
+	ChatFormatterMultimodal = "multimodal"
+	ChatFormatterText       = "text"
+)
+
+// ChatFormatters are the valid chat_formatter modes
+var ChatFormatters = []string{ChatFormatterMultimodal, ChatFormatterText}
+
+// GetChatFormatter returns the effective chat formatter mode for a target
+func (t *Target) GetChatFormatter(defaults Defaults) string {
+	if t.ChatFormatter != "" {
+		return t.ChatFormatter
+	}
+	if defaults.ChatFormatter != "" {
+		return defaults.ChatFormatter
+	}
+	return ChatFormatterMultimodal
+}
+
+// ValidateChatFormatter checks that the target's chat formatter is a known
+// mode, and that the text formatter is only used for chat_completions
+func (t *Target) ValidateChatFormatter(defaults Defaults) error {
+	formatter := t.GetChatFormatter(defaults)
+	if !slices.Contains(ChatFormatters, formatter) {
+		return fmt.Errorf("chat_formatter %q is not one of %v", formatter, ChatFormatters)
+	}
+	if requestType := t.GetRequestType(defaults); formatter == ChatFormatterText && requestType != "chat_completions" {
+		return fmt.Errorf("chat_formatter %q requires request_type chat_completions, got %q", formatter, requestType)
+	}
+	return nil
+}
+
 // GetRequestType returns the effective request type for a target
 func (t *Target) GetRequestType(defaults Defaults) string {
 	if t.RequestType != "" {
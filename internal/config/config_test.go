@@ -0,0 +1,602 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetRunTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults Defaults
+		target   Target
+		expected time.Duration
+	}{
+		{
+			name:     "derived from defaults",
+			defaults: Defaults{MaxSeconds: 30},
+			expected: 120 * time.Second, // 30*2 + 60
+		},
+		{
+			name:     "uses target max_seconds",
+			defaults: Defaults{MaxSeconds: 30},
+			target:   Target{MaxSeconds: intPtr(100)},
+			expected: 260 * time.Second,
+		},
+		{
+			name:     "custom multiplier and padding",
+			defaults: Defaults{MaxSeconds: 30, RunTimeoutMultiplier: 1.5, RunTimeoutPadding: 10},
+			expected: 55 * time.Second,
+		},
+		{
+			name:     "explicit run_timeout wins",
+			defaults: Defaults{MaxSeconds: 30, RunTimeout: 45, RunTimeoutMultiplier: 3},
+			expected: 45 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.GetRunTimeout(tt.defaults); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Helper function to create int pointer
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGetProbeTimeout(t *testing.T) {
+	five := 5
+	tests := []struct {
+		name     string
+		target   Target
+		defaults Defaults
+		expected time.Duration
+	}{
+		{"client default", Target{}, Defaults{}, DefaultProbeTimeout * time.Second},
+		{"defaults", Target{}, Defaults{ProbeTimeout: 30}, 30 * time.Second},
+		{"target override", Target{ProbeTimeout: &five}, Defaults{ProbeTimeout: 30}, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.GetProbeTimeout(tt.defaults); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestShutdownTimeouts(t *testing.T) {
+	zero, five := 0, 5
+	tests := []struct {
+		name     string
+		shutdown ShutdownConfig
+		timeout  time.Duration
+		grace    time.Duration
+	}{
+		{"defaults", ShutdownConfig{}, 30 * time.Second, 20 * time.Second},
+		{"longer timeout", ShutdownConfig{Timeout: 600}, 600 * time.Second, 400 * time.Second},
+		{"explicit grace", ShutdownConfig{Timeout: 600, DrainGrace: &five}, 600 * time.Second, 5 * time.Second},
+		{"no grace", ShutdownConfig{DrainGrace: &zero}, 30 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.shutdown.GetTimeout()
+			if timeout != tt.timeout {
+				t.Errorf("expected timeout %s, got %s", tt.timeout, timeout)
+			}
+			if got := tt.shutdown.GetDrainGrace(timeout); got != tt.grace {
+				t.Errorf("expected drain grace %s, got %s", tt.grace, got)
+			}
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"http://localhost:8000/v1", false},
+		{"https://api.example.com/v1/chat/completions", false},
+		{"localhost:8000/v1", true},
+		{"ftp://host/v1", true},
+		{"http:///v1", true},
+		{"htp//typo", true},
+		{"http://host:port", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHealthPath(t *testing.T) {
+	for _, path := range []string{"/v1/models", "/health", "/ready?deep=true"} {
+		if err := ValidateHealthPath(path); err != nil {
+			t.Errorf("expected %q to be valid: %v", path, err)
+		}
+	}
+	for _, path := range []string{"http://host/health", "health", "//host/health"} {
+		if err := ValidateHealthPath(path); err == nil {
+			t.Errorf("expected %q to be rejected", path)
+		}
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	enabled := true
+	defaults := Defaults{RequestType: "text_completions"}
+
+	if err := (&Target{Stream: &enabled}).ValidateStream(defaults); err != nil {
+		t.Errorf("expected streaming text_completions to be valid: %v", err)
+	}
+	if err := (&Target{Stream: &enabled, RequestType: "audio_transcriptions"}).ValidateStream(defaults); err == nil {
+		t.Error("expected streaming audio_transcriptions to be rejected")
+	}
+	if err := (&Target{RequestType: "audio_transcriptions"}).ValidateStream(defaults); err != nil {
+		t.Errorf("expected non-streaming target to be valid: %v", err)
+	}
+}
+
+func TestValidateChatFormatter(t *testing.T) {
+	chat := Defaults{RequestType: "chat_completions"}
+	text := Defaults{RequestType: "text_completions"}
+
+	tests := []struct {
+		name     string
+		target   Target
+		defaults Defaults
+		wantErr  bool
+	}{
+		{"default formatter", Target{}, text, false},
+		{"text formatter for chat", Target{ChatFormatter: ChatFormatterText}, chat, false},
+		{"text formatter from defaults", Target{RequestType: "chat_completions"}, Defaults{ChatFormatter: ChatFormatterText}, false},
+		{"text formatter for text completions", Target{ChatFormatter: ChatFormatterText}, text, true},
+		{"unknown formatter", Target{ChatFormatter: "markdown"}, chat, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.ValidateChatFormatter(tt.defaults)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateChatFormatter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets BucketsConfig
+		wantErr bool
+	}{
+		{"defaults", BucketsConfig{}, false},
+		{"custom", BucketsConfig{ITL: []float64{0.0005, 0.001, 0.01}, E2E: []float64{1, 60, 600}}, false},
+		{"not increasing", BucketsConfig{TTFT: []float64{0.1, 0.1, 1}}, true},
+		{"not positive", BucketsConfig{E2E: []float64{0, 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.buckets.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePushgateway(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     PushgatewayConfig
+		wantErr bool
+	}{
+		{"disabled", PushgatewayConfig{}, false},
+		{"valid", PushgatewayConfig{URL: "http://pushgateway:9091", Job: DefaultPushgatewayJob}, false},
+		{"no scheme", PushgatewayConfig{URL: "pushgateway:9091"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateExtraArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"--warmup", "0.1", "--cooldown=0.1"}, false},
+		{[]string{"--random-seed=42"}, false},
+		{[]string{"0.1"}, true},
+		{[]string{"-w", "0.1"}, true},
+		{[]string{"--"}, true},
+		{[]string{"--rate", "10"}, true},
+		{[]string{"--processor=gpt2"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			target := Target{ExtraArgs: tt.args}
+			if err := target.ValidateExtraArgs(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExtraArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSweepTargets(t *testing.T) {
+	rate, low, high := 5.0, 1.0, 50.0
+	target := Target{Name: "sweep", Rate: &rate, Profile: "constant"}
+	if got := target.SweepTargets(); len(got) != 1 || *got[0].Rate != rate {
+		t.Fatalf("expected just the target without a sweep, got %+v", got)
+	}
+
+	target.Sweep = []SweepStep{{Rate: &low}, {Rate: &high}, {Profile: "throughput"}}
+	if err := target.ValidateSweep(ValidProfiles); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := target.SweepTargets()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 sweep targets, got %d", len(got))
+	}
+	if *got[0].Rate != low || *got[1].Rate != high || got[1].Profile != "constant" {
+		t.Errorf("expected rate steps to keep the profile, got %+v and %+v", got[0], got[1])
+	}
+	if got[2].Profile != "throughput" || *got[2].Rate != rate {
+		t.Errorf("expected a profile step to keep the rate, got %+v", got[2])
+	}
+
+	zero := 0.0
+	for _, step := range []SweepStep{{}, {Rate: &zero}, {Profile: "bursty"}} {
+		target.Sweep = []SweepStep{step}
+		if err := target.ValidateSweep(ValidProfiles); err == nil {
+			t.Errorf("expected sweep step %+v to be invalid", step)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	target := Target{Tags: map[string]string{"team": "search", "tier": "prod"}}
+	if err := target.ValidateTags(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		want  map[string]string
+		match bool
+	}{
+		{nil, true},
+		{map[string]string{"tier": "prod"}, true},
+		{map[string]string{"tier": "prod", "team": "search"}, true},
+		{map[string]string{"team": ""}, true},
+		{map[string]string{"tier": "dev"}, false},
+		{map[string]string{"gpu": ""}, false},
+	}
+	for _, tt := range tests {
+		if got := target.HasTags(tt.want); got != tt.match {
+			t.Errorf("HasTags(%v) = %v, want %v", tt.want, got, tt.match)
+		}
+	}
+
+	for _, tags := range []map[string]string{{"": "x"}, {"gpu-type": "a100"}, {"1tier": "prod"}, {"tier": ""}} {
+		if err := (&Target{Tags: tags}).ValidateTags(); err == nil {
+			t.Errorf("expected tags %v to be invalid", tags)
+		}
+	}
+
+	if err := (PrometheusConfig{TagLabels: []string{"team", "tier"}}).ValidateTagLabels(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, keys := range [][]string{{"team", "team"}, {"gpu-type"}} {
+		if err := (PrometheusConfig{TagLabels: keys}).ValidateTagLabels(); err == nil {
+			t.Errorf("expected tag_labels %v to be invalid", keys)
+		}
+	}
+}
+
+func TestGroupSelects(t *testing.T) {
+	group := Group{Targets: []string{"llama-8b"}, Tags: map[string]string{"family": "llama"}}
+	if err := group.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		target Target
+		member bool
+	}{
+		{Target{Name: "llama-8b"}, true},
+		{Target{Name: "llama-70b", Tags: map[string]string{"family": "llama"}}, true},
+		{Target{Name: "mistral", Tags: map[string]string{"family": "mistral"}}, false},
+	}
+	for _, tt := range tests {
+		if got := group.Selects(&tt.target); got != tt.member {
+			t.Errorf("Selects(%s) = %v, want %v", tt.target.Name, got, tt.member)
+		}
+	}
+
+	// A group of names alone doesn't select every target by its empty tags
+	if (Group{Targets: []string{"llama-8b"}}).Selects(&Target{Name: "mistral"}) {
+		t.Error("expected a name-only group not to select unlisted targets")
+	}
+	for _, bad := range []Group{{}, {Targets: []string{""}}, {Tags: map[string]string{"gpu-type": "a100"}}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected group %+v to be invalid", bad)
+		}
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Profile: "constant", Interval: 300, Rate: 1, MaxSeconds: 60},
+		Environments: map[string]Environment{
+			"staging": {Targets: []Target{{
+				Name:  "llama",
+				URL:   "http://staging:8000/v1",
+				Model: "llama",
+				Sweep: []SweepStep{{Profile: "bursty"}},
+			}}},
+		},
+	}
+	if errs := cfg.Validate(); len(errs) == 0 {
+		t.Error("expected unknown sweep profile to be rejected")
+	}
+
+	cfg.GuideLLMProfiles = []string{"constant", "bursty"}
+	if errs := cfg.Validate(); len(errs) != 0 {
+		t.Errorf("expected guidellm_profiles to admit bursty: %v", errs)
+	}
+	cfg.Defaults.Profile = "poisson"
+	if errs := cfg.Validate(); len(errs) == 0 {
+		t.Error("expected guidellm_profiles to replace the built-in profiles")
+	}
+}
+
+func TestValidateRate(t *testing.T) {
+	rate := 5.0
+	if err := ValidateRate("throughput", &rate); err == nil {
+		t.Error("expected a rate for the throughput profile to be rejected")
+	}
+	for _, profile := range []string{"constant", "sweep", "custom"} {
+		if err := ValidateRate(profile, &rate); err != nil {
+			t.Errorf("expected a rate for profile %s to be accepted: %v", profile, err)
+		}
+	}
+	if err := ValidateRate("synchronous", nil); err != nil {
+		t.Errorf("expected synchronous without a rate to be accepted: %v", err)
+	}
+
+	// A sweep step's rate is checked against the profile it runs with
+	target := Target{Profile: "throughput", Sweep: []SweepStep{{Rate: &rate, Profile: "constant"}, {Profile: "synchronous"}}}
+	if err := target.ValidateSweep(ValidProfiles); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	target.Sweep = append(target.Sweep, SweepStep{Rate: &rate})
+	if err := target.ValidateSweep(ValidProfiles); err == nil {
+		t.Error("expected a rate on a throughput step to be rejected")
+	}
+}
+
+func TestSubprocessEnviron(t *testing.T) {
+	environ := []string{"PATH=/bin", "HF_TOKEN=hf", "HF_HOME=/cache", "AWS_SECRET_ACCESS_KEY=secret"}
+
+	if got := (SubprocessConfig{}).Environ(environ); !slices.Equal(got, environ) {
+		t.Errorf("expected the whole environment by default, got %v", got)
+	}
+	allow := SubprocessConfig{EnvAllow: []string{"PATH", "HF_*"}, EnvDeny: []string{"HF_TOKEN"}}
+	if got, want := allow.Environ(environ), []string{"PATH=/bin", "HF_HOME=/cache"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	deny := SubprocessConfig{EnvDeny: []string{"AWS_*"}}
+	if got, want := deny.Environ(environ), environ[:3]; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if err := (SubprocessConfig{WorkDir: t.TempDir(), EnvAllow: []string{"PATH"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, bad := range []SubprocessConfig{
+		{WorkDir: filepath.Join(t.TempDir(), "missing")},
+		{EnvDeny: []string{""}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", bad)
+		}
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("GUIDELLM_TEST_KEY", "sk-secret")
+	t.Setenv("GUIDELLM_TEST_EMPTY", "")
+
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"${GUIDELLM_TEST_KEY}", "sk-secret", false},
+		{"Bearer ${GUIDELLM_TEST_KEY}!", "Bearer sk-secret!", false},
+		{"${GUIDELLM_TEST_UNSET:-fallback}", "fallback", false},
+		{"${GUIDELLM_TEST_EMPTY:-fallback}", "fallback", false},
+		{"${GUIDELLM_TEST_EMPTY}", "", false},
+		{"http://${GUIDELLM_TEST_UNSET:-localhost}:8000/v1", "http://localhost:8000/v1", false},
+		{"$GUIDELLM_TEST_KEY and ${not valid}", "$GUIDELLM_TEST_KEY and ${not valid}", false},
+		{"${GUIDELLM_TEST_UNSET}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := expandEnv(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandEnv(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("expandEnv(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadExpandsEnvVars(t *testing.T) {
+	t.Setenv("GUIDELLM_TEST_HOST", "llm.internal")
+	t.Setenv("GUIDELLM_TEST_KEY", "sk-secret")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+environments:
+  develop:
+    targets:
+      - name: llama
+        url: http://${GUIDELLM_TEST_HOST}:8000/v1
+        model: llama
+        api_key: ${GUIDELLM_TEST_KEY}
+discovery:
+  environments:
+    develop:
+      endpoint: http://${GUIDELLM_TEST_HOST}/v1/models
+      api_key: ${GUIDELLM_TEST_DISCOVERY_KEY:-none}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	target := cfg.Environments["develop"].Targets[0]
+	if target.URL != "http://llm.internal:8000/v1" || target.APIKey != "sk-secret" {
+		t.Errorf("expected expanded target, got url=%q api_key=%q", target.URL, target.APIKey)
+	}
+	disc := cfg.Discovery.Environments["develop"]
+	if disc.Endpoint != "http://llm.internal/v1/models" || disc.APIKey != "none" {
+		t.Errorf("expected expanded discovery env, got %+v", disc)
+	}
+
+	// A missing variable without a default fails the load
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(data, "${GUIDELLM_TEST_KEY}", "${GUIDELLM_TEST_MISSING}")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path)
+	if err == nil || !strings.Contains(err.Error(), "environments.develop.targets[0].api_key: environment variable GUIDELLM_TEST_MISSING is not set") {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+}
+
+// TestLoadFractionalRates verifies that rates load as floats, whether
+// written as integers or with a fractional part
+func TestLoadFractionalRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+defaults:
+  rate: 2
+environments:
+  develop:
+    targets:
+      - name: poisson
+        url: http://localhost:8000/v1
+        model: llama
+        profile: poisson
+        rate: 2.5
+        sweep:
+          - rate: 0.25
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Defaults.Rate != 2 {
+		t.Errorf("expected default rate 2, got %g", cfg.Defaults.Rate)
+	}
+	target := cfg.Environments["develop"].Targets[0]
+	if got := target.GetRate(cfg.Defaults); got != 2.5 {
+		t.Errorf("expected target rate 2.5, got %g", got)
+	}
+	if got := target.SweepTargets()[0].GetRate(cfg.Defaults); got != 0.25 {
+		t.Errorf("expected sweep step rate 0.25, got %g", got)
+	}
+}
+
+func TestLoadAPIKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	targetKey := filepath.Join(dir, "target-key")
+	defaultKey := filepath.Join(dir, "default-key")
+	if err := os.WriteFile(targetKey, []byte("sk-target\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultKey, []byte("  sk-default  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+defaults:
+  api_key_file: ` + defaultKey + `
+environments:
+  develop:
+    targets:
+      - name: from-file
+        url: http://llm:8000/v1
+        model: llama
+        api_key_file: ` + targetKey + `
+      - name: inline
+        url: http://llm:8000/v1
+        model: llama
+        api_key: sk-inline
+      - name: from-default
+        url: http://llm:8000/v1
+        model: llama
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []string{"sk-target", "sk-inline", "sk-default"}
+	for i, target := range cfg.Environments["develop"].Targets {
+		if target.APIKey != want[i] {
+			t.Errorf("%s: expected api key %q, got %q", target.Name, want[i], target.APIKey)
+		}
+	}
+
+	// Setting both api_key and api_key_file is ambiguous
+	both := strings.Replace(data, "api_key: sk-inline", "api_key: sk-inline\n        api_key_file: "+targetKey, 1)
+	if err := os.WriteFile(path, []byte(both), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path)
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("expected mutually exclusive error, got %v", err)
+	}
+
+	// A missing key file fails the load
+	missing := strings.Replace(data, targetKey, filepath.Join(dir, "missing"), 1)
+	if err := os.WriteFile(path, []byte(missing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for missing api key file")
+	}
+}
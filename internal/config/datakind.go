@@ -0,0 +1,44 @@
+package config
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Kinds of benchmark data, derived from the data spec
+const (
+	DataKindSynthetic = "synthetic"
+	DataKindDataset   = "dataset"
+)
+
+// syntheticParam matches one key=value item of a synthetic data spec
+var syntheticParam = regexp.MustCompile(`^[a-z_]+=[^=]*$`)
+
+// DataKind classifies a guidellm --data spec as synthetic, i.e. generated
+// from token counts like "prompt_tokens=256,output_tokens=128" or the same as
+// a JSON object, or as a dataset such as a Hugging Face dataset ID or a file
+// of real prompts
+func DataKind(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return DataKindDataset
+	}
+
+	if strings.HasPrefix(spec, "{") {
+		var params map[string]any
+		if err := json.Unmarshal([]byte(spec), &params); err == nil {
+			if _, ok := params["prompt_tokens"]; ok {
+				return DataKindSynthetic
+			}
+		}
+		return DataKindDataset
+	}
+
+	for _, item := range strings.Split(spec, ",") {
+		if !syntheticParam.MatchString(strings.TrimSpace(item)) {
+			return DataKindDataset
+		}
+	}
+	return DataKindSynthetic
+}
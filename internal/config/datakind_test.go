@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestDataKind(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+	}{
+		{"prompt_tokens=256,output_tokens=128", DataKindSynthetic},
+		{"prompt_tokens=256, output_tokens=128, samples=100", DataKindSynthetic},
+		{`{"prompt_tokens": 256, "output_tokens": 128}`, DataKindSynthetic},
+		{"openai/gsm8k", DataKindDataset},
+		{"/data/prompts.jsonl", DataKindDataset},
+		{"./prompts.csv", DataKindDataset},
+		{`{"path": "prompts.jsonl"}`, DataKindDataset},
+		{"", DataKindDataset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if got := DataKind(tt.spec); got != tt.expected {
+				t.Errorf("DataKind(%q) = %s, want %s", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// envRefPattern matches ${VAR} and ${VAR:-default} references
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s from the
+// process environment. The default is used when VAR is unset or empty; a
+// reference to an unset variable without a default is an error. Text that
+// isn't a reference is left untouched.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	expanded := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envRefPattern.FindStringSubmatch(ref)
+		name, hasDefault, def := m[1], m[2] != "", m[3]
+
+		if value, ok := os.LookupEnv(name); ok && (value != "" || !hasDefault) {
+			return value
+		}
+		if hasDefault {
+			return def
+		}
+		missing = append(missing, name)
+		return ref
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", missing[0])
+	}
+	return expanded, nil
+}
+
+// expandEnvVars expands environment variable references in the config's
+// URLs, API keys, API key file paths, webhook settings, results storage
+// settings and guidellm binary path
+func (c *Config) expandEnvVars() error {
+	expand := func(field string, value *string) error {
+		v, err := expandEnv(*value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		*value = v
+		return nil
+	}
+
+	if err := expand("guidellm_binary", &c.GuideLLMBinary); err != nil {
+		return err
+	}
+	if err := expand("defaults.api_key_file", &c.Defaults.APIKeyFile); err != nil {
+		return err
+	}
+	if err := expand("results_dir", &c.ResultsDir); err != nil {
+		return err
+	}
+	if err := expand("raw_output_dir", &c.RawOutputDir); err != nil {
+		return err
+	}
+	if err := expand("results_s3.endpoint", &c.ResultsS3.Endpoint); err != nil {
+		return err
+	}
+	if err := expand("results_s3.bucket", &c.ResultsS3.Bucket); err != nil {
+		return err
+	}
+	if err := expand("results_s3.access_key_id", &c.ResultsS3.AccessKeyID); err != nil {
+		return err
+	}
+	if err := expand("results_s3.secret_access_key", &c.ResultsS3.SecretAccessKey); err != nil {
+		return err
+	}
+	if err := expand("webhooks.url", &c.Webhooks.URL); err != nil {
+		return err
+	}
+	if err := expand("webhooks.secret", &c.Webhooks.Secret); err != nil {
+		return err
+	}
+
+	// Walk environments in a stable order so the first error is reproducible
+	envNames := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		targets := c.Environments[envName].Targets
+		for i := range targets {
+			prefix := fmt.Sprintf("environments.%s.targets[%d]", envName, i)
+			if err := expand(prefix+".url", &targets[i].URL); err != nil {
+				return err
+			}
+			if err := expand(prefix+".api_key", &targets[i].APIKey); err != nil {
+				return err
+			}
+			if err := expand(prefix+".api_key_file", &targets[i].APIKeyFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	discoveryNames := make([]string, 0, len(c.Discovery.Environments))
+	for name := range c.Discovery.Environments {
+		discoveryNames = append(discoveryNames, name)
+	}
+	sort.Strings(discoveryNames)
+
+	for _, envName := range discoveryNames {
+		env := c.Discovery.Environments[envName]
+		prefix := "discovery.environments." + envName
+		if err := expand(prefix+".endpoint", &env.Endpoint); err != nil {
+			return err
+		}
+		if err := expand(prefix+".base_url", &env.BaseURL); err != nil {
+			return err
+		}
+		if err := expand(prefix+".api_key", &env.APIKey); err != nil {
+			return err
+		}
+		c.Discovery.Environments[envName] = env
+	}
+
+	return nil
+}
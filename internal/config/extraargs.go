@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ReservedFlags are the guidellm flags the runner sets itself, which
+// extra_args may not repeat
+var ReservedFlags = []string{
+	"--target",
+	"--model",
+	"--profile",
+	"--rate",
+	"--max-seconds",
+	"--data",
+	"--output-dir",
+	"--outputs",
+	"--backend-kwargs",
+	"--request-type",
+	"--processor",
+	"--request-formatter-kwargs",
+}
+
+// ValidateExtraArgs checks the target's extra guidellm arguments: each is a
+// long flag (--name or --name=value) or a value following a flag, and no
+// flag is one the runner already sets
+func (t *Target) ValidateExtraArgs() error {
+	for i, arg := range t.ExtraArgs {
+		if !strings.HasPrefix(arg, "-") {
+			if i == 0 {
+				return fmt.Errorf("extra_args must start with a --flag, got %q", arg)
+			}
+			continue // value for the preceding flag
+		}
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			return fmt.Errorf("extra_args flag %q must be a long --flag", arg)
+		}
+
+		name, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(ReservedFlags, name) {
+			return fmt.Errorf("extra_args flag %s is already set by the runner", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import "fmt"
+
+// Group selects targets to operate on as a unit: those listed by name plus
+// those with every tag in Tags (an empty tag value matches any value).
+// Membership is resolved each time the group is used, so it follows
+// targets as they are added and removed. Listed names need not exist yet.
+type Group struct {
+	Targets []string          `yaml:"targets,omitempty"`
+	Tags    map[string]string `yaml:"tags,omitempty"`
+}
+
+// Selects reports whether t is a member of the group
+func (g Group) Selects(t *Target) bool {
+	for _, name := range g.Targets {
+		if name == t.Name {
+			return true
+		}
+	}
+	return len(g.Tags) > 0 && t.HasTags(g.Tags)
+}
+
+// Validate checks that the group selects targets by name and/or by valid
+// tag keys
+func (g Group) Validate() error {
+	if len(g.Targets) == 0 && len(g.Tags) == 0 {
+		return fmt.Errorf("must list targets and/or tags")
+	}
+	for _, name := range g.Targets {
+		if name == "" {
+			return fmt.Errorf("target names must not be empty")
+		}
+	}
+	for key := range g.Tags {
+		if !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("tag key %q must contain only letters, digits and underscores, and not start with a digit", key)
+		}
+	}
+	return nil
+}
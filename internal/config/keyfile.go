@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// readAPIKeyFile reads an API key from path, trimming surrounding whitespace
+// such as the trailing newline secret files often end with
+func readAPIKeyFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading api key file: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("api key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// loadAPIKeyFiles fills in target API keys from api_key_file, falling back to
+// defaults.api_key_file for targets that set neither api_key nor
+// api_key_file. Setting both api_key and api_key_file on a target is an error.
+func (c *Config) loadAPIKeyFiles() error {
+	var defaultKey string
+	if c.Defaults.APIKeyFile != "" {
+		key, err := readAPIKeyFile(c.Defaults.APIKeyFile)
+		if err != nil {
+			return fmt.Errorf("defaults.api_key_file: %w", err)
+		}
+		defaultKey = key
+	}
+
+	// Walk environments in a stable order so the first error is reproducible
+	envNames := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		targets := c.Environments[envName].Targets
+		for i := range targets {
+			target := &targets[i]
+			prefix := fmt.Sprintf("environments.%s.targets[%d]", envName, i)
+
+			switch {
+			case target.APIKey != "" && target.APIKeyFile != "":
+				return fmt.Errorf("%s: api_key and api_key_file are mutually exclusive", prefix)
+			case target.APIKeyFile != "":
+				key, err := readAPIKeyFile(target.APIKeyFile)
+				if err != nil {
+					return fmt.Errorf("%s.api_key_file: %w", prefix, err)
+				}
+				target.APIKey = key
+			case target.APIKey == "":
+				target.APIKey = defaultKey
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ValidProfiles are the guidellm load profiles a target may use, unless
+// guidellm_profiles replaces them
+var ValidProfiles = []string{"synchronous", "concurrent", "throughput", "constant", "poisson", "sweep"}
+
+// ratelessProfiles run as fast as one request at a time (synchronous) or as
+// the server allows (throughput), so guidellm takes no --rate for them
+var ratelessProfiles = []string{"synchronous", "throughput"}
+
+// ProfileUsesRate reports whether guidellm's --rate applies to profile.
+// Profiles the runner doesn't know of are assumed to take one.
+func ProfileUsesRate(profile string) bool {
+	return !slices.Contains(ratelessProfiles, profile)
+}
+
+// ValidateRate checks that a rate is only set alongside a profile that uses
+// it, since guidellm would otherwise ignore it or reject the run
+func ValidateRate(profile string, rate *float64) error {
+	if rate != nil && !ProfileUsesRate(profile) {
+		return fmt.Errorf("rate is not used by profile %q", profile)
+	}
+	return nil
+}
+
+// GetProfiles returns the load profiles targets may use: guidellm_profiles
+// if set, for guidellm versions with profiles the runner doesn't know of,
+// otherwise ValidProfiles
+func (c *Config) GetProfiles() []string {
+	if len(c.GuideLLMProfiles) > 0 {
+		return c.GuideLLMProfiles
+	}
+	return ValidProfiles
+}
+
+// ValidateProfile checks that profile is one of the known load profiles
+func (c *Config) ValidateProfile(profile string) error {
+	if profiles := c.GetProfiles(); !slices.Contains(profiles, profile) {
+		return fmt.Errorf("profile %q is not one of %v", profile, profiles)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// SubprocessConfig controls the directory and environment guidellm runs in
+type SubprocessConfig struct {
+	// WorkDir is guidellm's working directory, against which relative data
+	// file paths resolve. Empty means the runner's own.
+	WorkDir string `yaml:"work_dir,omitempty"`
+
+	// EnvAllow lists the environment variables passed through to guidellm.
+	// Empty passes the whole environment. A trailing * matches a prefix,
+	// e.g. HF_*.
+	EnvAllow []string `yaml:"env_allow,omitempty"`
+
+	// EnvDeny lists environment variables withheld from guidellm, even if
+	// allowed. A trailing * matches a prefix.
+	EnvDeny []string `yaml:"env_deny,omitempty"`
+
+	// OutputTailBytes bounds how much of the end of each of guidellm's
+	// output streams is kept for reporting failures
+	OutputTailBytes int `yaml:"output_tail_bytes,omitempty"`
+}
+
+// DefaultOutputTailBytes is the default bound on each kept output stream
+const DefaultOutputTailBytes = 64 << 10
+
+// GetOutputTailBytes returns the bytes of each output stream to keep
+func (s SubprocessConfig) GetOutputTailBytes() int {
+	if s.OutputTailBytes > 0 {
+		return s.OutputTailBytes
+	}
+	return DefaultOutputTailBytes
+}
+
+// Validate checks that the working directory, if set, is a directory, that
+// no env pattern is empty and that the output tail isn't negative
+func (s SubprocessConfig) Validate() error {
+	if s.OutputTailBytes < 0 {
+		return fmt.Errorf("subprocess.output_tail_bytes must not be negative, got %d", s.OutputTailBytes)
+	}
+	if s.WorkDir != "" {
+		info, err := os.Stat(s.WorkDir)
+		if err != nil {
+			return fmt.Errorf("subprocess.work_dir: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("subprocess.work_dir: %s is not a directory", s.WorkDir)
+		}
+	}
+	for _, pattern := range append(slices.Clone(s.EnvAllow), s.EnvDeny...) {
+		if pattern == "" {
+			return fmt.Errorf("subprocess: env patterns must not be empty")
+		}
+	}
+	return nil
+}
+
+// Environ filters environ, a list of KEY=value entries as returned by
+// os.Environ, down to the variables guidellm may see
+func (s SubprocessConfig) Environ(environ []string) []string {
+	if len(s.EnvAllow) == 0 && len(s.EnvDeny) == 0 {
+		return environ
+	}
+	filtered := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		key, _, _ := strings.Cut(entry, "=")
+		if len(s.EnvAllow) > 0 && !matchesEnv(s.EnvAllow, key) {
+			continue
+		}
+		if matchesEnv(s.EnvDeny, key) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// matchesEnv reports whether key matches any of patterns, which are exact
+// names or prefixes ending in *
+func matchesEnv(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SweepStep is one run of a target's sweep. Unset fields keep the target's
+// own rate or profile.
+type SweepStep struct {
+	Rate    *float64 `yaml:"rate,omitempty"`
+	Profile string   `yaml:"profile,omitempty"`
+}
+
+// SweepTargets returns the target as run at each step of its sweep, in
+// order, or just the target if it has no sweep
+func (t *Target) SweepTargets() []Target {
+	if len(t.Sweep) == 0 {
+		return []Target{*t}
+	}
+	targets := make([]Target, 0, len(t.Sweep))
+	for _, step := range t.Sweep {
+		target := *t
+		if step.Rate != nil {
+			target.Rate = step.Rate
+		}
+		if step.Profile != "" {
+			target.Profile = step.Profile
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// ValidateSweep checks that each step of the target's sweep sets a positive
+// rate and/or one of profiles, and doesn't set a rate for a profile that
+// takes none
+func (t *Target) ValidateSweep(profiles []string) error {
+	for i, step := range t.Sweep {
+		if step.Rate == nil && step.Profile == "" {
+			return fmt.Errorf("sweep step %d must set rate and/or profile", i+1)
+		}
+		if step.Rate != nil && *step.Rate <= 0 {
+			return fmt.Errorf("sweep step %d: rate must be positive, got %g", i+1, *step.Rate)
+		}
+		if step.Profile != "" && !slices.Contains(profiles, step.Profile) {
+			return fmt.Errorf("sweep step %d: profile %q is not one of %v", i+1, step.Profile, profiles)
+		}
+		if step.Profile != "" || t.Profile != "" {
+			profile := step.Profile
+			if profile == "" {
+				profile = t.Profile
+			}
+			if err := ValidateRate(profile, step.Rate); err != nil {
+				return fmt.Errorf("sweep step %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
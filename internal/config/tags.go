@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// tagKeyPattern restricts tag keys to valid Prometheus label name
+// characters, so any of them can be exported with tag_labels
+var tagKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateTags checks that the target's tag keys are valid label names and
+// its tag values are non-empty
+func (t *Target) ValidateTags() error {
+	for key, value := range t.Tags {
+		if !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("tag key %q must contain only letters, digits and underscores, and not start with a digit", key)
+		}
+		if value == "" {
+			return fmt.Errorf("tag %q must have a value", key)
+		}
+	}
+	return nil
+}
+
+// HasTags reports whether the target has every tag in tags. An empty value
+// matches any value of that tag.
+func (t *Target) HasTags(tags map[string]string) bool {
+	for key, want := range tags {
+		got, ok := t.Tags[key]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateTagLabels checks that tag_labels names distinct, valid tag keys
+func (p PrometheusConfig) ValidateTagLabels() error {
+	seen := make(map[string]bool, len(p.TagLabels))
+	for _, key := range p.TagLabels {
+		if !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("prometheus.tag_labels: %q is not a valid tag key", key)
+		}
+		if seen[key] {
+			return fmt.Errorf("prometheus.tag_labels: %q is listed twice", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Validate checks a loaded config for mistakes and returns every problem
+// found (nil if the config is valid). Defaults are expected to be applied.
+func (c *Config) Validate() []error {
+	var errs []error
+
+	if c.Defaults.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("defaults.interval must be positive, got %d", c.Defaults.Interval))
+	}
+	if c.Defaults.Rate <= 0 {
+		errs = append(errs, fmt.Errorf("defaults.rate must be positive, got %g", c.Defaults.Rate))
+	}
+	if c.Defaults.MaxSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("defaults.max_seconds must be positive, got %d", c.Defaults.MaxSeconds))
+	}
+	if err := c.ValidateProfile(c.Defaults.Profile); err != nil {
+		errs = append(errs, fmt.Errorf("defaults: %w", err))
+	}
+	if c.Defaults.Jitter < 0 || c.Defaults.Jitter >= 1 {
+		errs = append(errs, fmt.Errorf("defaults.jitter must be a fraction of the interval from 0 up to 1, got %g", c.Defaults.Jitter))
+	}
+	if c.Defaults.ProbeTimeout < 0 {
+		errs = append(errs, fmt.Errorf("defaults.probe_timeout must not be negative, got %d", c.Defaults.ProbeTimeout))
+	}
+	if c.Webhooks.URL != "" {
+		if err := ValidateURL(c.Webhooks.URL); err != nil {
+			errs = append(errs, fmt.Errorf("webhooks: %w", err))
+		}
+		if c.Webhooks.Timeout < 0 || c.Webhooks.Workers < 0 || c.Webhooks.QueueSize < 0 {
+			errs = append(errs, fmt.Errorf("webhooks: timeout, workers and queue_size must not be negative"))
+		}
+	}
+	if c.Defaults.RegressionThreshold < 0 || c.Defaults.RegressionThreshold >= 100 {
+		errs = append(errs, fmt.Errorf("defaults.regression_threshold must be a percentage below 100, got %g", c.Defaults.RegressionThreshold))
+	}
+	if c.Defaults.RegressionWindow < 0 {
+		errs = append(errs, fmt.Errorf("defaults.regression_window must not be negative, got %d", c.Defaults.RegressionWindow))
+	}
+	if c.API.RunRetention < 0 {
+		errs = append(errs, fmt.Errorf("api.run_retention must not be negative, got %d", c.API.RunRetention))
+	}
+	if c.Startup.Stagger < 0 {
+		errs = append(errs, fmt.Errorf("startup.stagger must not be negative, got %g", c.Startup.Stagger))
+	}
+	if c.Shutdown.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("shutdown.timeout must not be negative, got %d", c.Shutdown.Timeout))
+	}
+	if c.Shutdown.DrainGrace != nil && *c.Shutdown.DrainGrace < 0 {
+		errs = append(errs, fmt.Errorf("shutdown.drain_grace must not be negative, got %d", *c.Shutdown.DrainGrace))
+	}
+	if c.Metrics.FleetFreshness < 0 {
+		errs = append(errs, fmt.Errorf("metrics.fleet_freshness must not be negative, got %d", c.Metrics.FleetFreshness))
+	}
+	if err := c.Metrics.Buckets.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Prometheus.ValidateTagLabels(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Prometheus.Pushgateway.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Subprocess.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	groupNames := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		if err := c.Groups[name].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("groups.%s: %w", name, err))
+		}
+	}
+	if c.ResultsS3.Bucket != "" {
+		if err := ValidateURL(c.ResultsS3.Endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("results_s3.endpoint: %w", err))
+		}
+		if c.ResultsS3.AccessKeyID == "" || c.ResultsS3.SecretAccessKey == "" {
+			errs = append(errs, fmt.Errorf("results_s3: access_key_id and secret_access_key are required"))
+		}
+	}
+	if _, err := (&Target{}).GetBackendKwargs(c.Defaults); err != nil {
+		errs = append(errs, fmt.Errorf("defaults: %w", err))
+	}
+	if c.Defaults.WarmupRuns < 0 {
+		errs = append(errs, fmt.Errorf("defaults.warmup_runs must not be negative, got %d", c.Defaults.WarmupRuns))
+	}
+	if c.Defaults.ChatFormatter != "" && !slices.Contains(ChatFormatters, c.Defaults.ChatFormatter) {
+		errs = append(errs, fmt.Errorf("defaults.chat_formatter %q is not one of %v", c.Defaults.ChatFormatter, ChatFormatters))
+	}
+	if c.Defaults.HealthPath != "" {
+		if err := ValidateHealthPath(c.Defaults.HealthPath); err != nil {
+			errs = append(errs, fmt.Errorf("defaults: %w", err))
+		}
+	}
+
+	// Walk environments in a stable order so reports are reproducible
+	envNames := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	// Target names are global: a later target with the same name would
+	// replace an earlier one when loaded
+	seen := make(map[string]string)
+	for _, envName := range envNames {
+		for i, target := range c.Environments[envName].Targets {
+			where := fmt.Sprintf("environments.%s.targets[%d]", envName, i)
+			if target.Name != "" {
+				where = fmt.Sprintf("environments.%s.targets[%d] (%s)", envName, i, target.Name)
+			}
+
+			if prev, ok := seen[target.Name]; ok {
+				errs = append(errs, fmt.Errorf("%s: duplicate target name, already used in environment %s", where, prev))
+			} else if target.Name != "" {
+				seen[target.Name] = envName
+			}
+
+			invalid := c.ValidateTarget(target)
+			fields := make([]string, 0, len(invalid))
+			for field := range invalid {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				errs = append(errs, fmt.Errorf("%s: %w", where, invalid[field]))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateTarget checks a target's settings and returns the problem with
+// each invalid one, keyed by its config field name (empty if the target is
+// valid). Defaults are expected to be applied. Target names aren't checked
+// for uniqueness.
+func (c *Config) ValidateTarget(t Target) map[string]error {
+	invalid := map[string]error{}
+	check := func(field string, err error) {
+		if err != nil {
+			invalid[field] = err
+		}
+	}
+
+	if t.Name == "" {
+		check("name", fmt.Errorf("name is required"))
+	}
+	if t.URL == "" {
+		check("url", fmt.Errorf("url is required"))
+	} else {
+		check("url", ValidateURL(t.URL))
+	}
+	if t.Model == "" {
+		check("model", fmt.Errorf("model is required"))
+	}
+	if t.HealthPath != "" {
+		check("health_path", ValidateHealthPath(t.HealthPath))
+	}
+	if t.Profile != "" {
+		check("profile", c.ValidateProfile(t.Profile))
+	}
+	if t.Rate != nil && *t.Rate <= 0 {
+		check("rate", fmt.Errorf("rate must be positive, got %g", *t.Rate))
+	} else if _, badProfile := invalid["profile"]; !badProfile {
+		check("rate", ValidateRate(t.GetProfile(c.Defaults), t.Rate))
+	}
+	if t.MaxSeconds != nil && *t.MaxSeconds <= 0 {
+		check("max_seconds", fmt.Errorf("max_seconds must be positive, got %d", *t.MaxSeconds))
+	}
+	if t.ProbeTimeout != nil && *t.ProbeTimeout <= 0 {
+		check("probe_timeout", fmt.Errorf("probe_timeout must be positive, got %d", *t.ProbeTimeout))
+	}
+	if t.WarmupRuns != nil && *t.WarmupRuns < 0 {
+		check("warmup_runs", fmt.Errorf("warmup_runs must not be negative, got %d", *t.WarmupRuns))
+	}
+	if t.MaxRuns != nil && *t.MaxRuns <= 0 {
+		check("max_runs", fmt.Errorf("max_runs must be positive, got %d", *t.MaxRuns))
+	}
+	if t.RegressionThreshold != nil && (*t.RegressionThreshold < 0 || *t.RegressionThreshold >= 100) {
+		check("regression_threshold", fmt.Errorf("regression_threshold must be a percentage below 100, got %g", *t.RegressionThreshold))
+	}
+	if t.RegressionWindow != nil && *t.RegressionWindow <= 0 {
+		check("regression_window", fmt.Errorf("regression_window must be positive, got %d", *t.RegressionWindow))
+	}
+	check("stream", t.ValidateStream(c.Defaults))
+	check("chat_formatter", t.ValidateChatFormatter(c.Defaults))
+	check("extra_args", t.ValidateExtraArgs())
+	check("sweep", t.ValidateSweep(c.GetProfiles()))
+	check("tags", t.ValidateTags())
+	if _, err := t.GetBackendKwargs(Defaults{}); err != nil {
+		check("backend_kwargs", err)
+	}
+	return invalid
+}
+
+// CheckTargetNames returns an error naming every target defined in more than
+// one environment. Targets are addressed by name alone, so duplicates would
+// silently replace each other when loaded.
+func (c *Config) CheckTargetNames() error {
+	envsByName := make(map[string][]string)
+	for envName, env := range c.Environments {
+		for _, target := range env.Targets {
+			envsByName[target.Name] = append(envsByName[target.Name], envName)
+		}
+	}
+
+	var dups []string
+	for name, envs := range envsByName {
+		if len(envs) > 1 {
+			sort.Strings(envs)
+			dups = append(dups, fmt.Sprintf("%q (environments %s)", name, strings.Join(envs, ", ")))
+		}
+	}
+	if len(dups) == 0 {
+		return nil
+	}
+
+	sort.Strings(dups)
+	return fmt.Errorf("duplicate target names, names must be unique across environments: %s", strings.Join(dups, "; "))
+}
+
+// Validate checks the Pushgateway URL, if pushing is enabled
+func (p PushgatewayConfig) Validate() error {
+	if p.URL == "" {
+		return nil
+	}
+	if err := ValidateURL(p.URL); err != nil {
+		return fmt.Errorf("prometheus.pushgateway: %w", err)
+	}
+	return nil
+}
+
+// Validate checks each configured set of histogram buckets is positive and
+// strictly increasing, as Prometheus requires
+func (b BucketsConfig) Validate() error {
+	for _, set := range []struct {
+		name    string
+		buckets []float64
+	}{{"ttft", b.TTFT}, {"itl", b.ITL}, {"e2e", b.E2E}} {
+		if err := validateBuckets(set.buckets); err != nil {
+			return fmt.Errorf("metrics.buckets.%s: %w", set.name, err)
+		}
+	}
+	return nil
+}
+
+// validateBuckets checks one set of histogram bucket bounds
+func validateBuckets(buckets []float64) error {
+	for i, b := range buckets {
+		if b <= 0 {
+			return fmt.Errorf("bucket bounds must be positive, got %g", b)
+		}
+		if i > 0 && b <= buckets[i-1] {
+			return fmt.Errorf("bucket bounds must be strictly increasing, got %g after %g", b, buckets[i-1])
+		}
+	}
+	return nil
+}
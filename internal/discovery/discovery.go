@@ -1,12 +1,15 @@
 package discovery
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -34,11 +37,20 @@ type Client struct {
 	logger     *slog.Logger
 }
 
+// DefaultTimeout bounds each discovery client request
+const DefaultTimeout = 10 * time.Second
+
 // NewClient creates a new discovery client
 func NewClient(logger *slog.Logger) *Client {
+	return NewClientWithTimeout(logger, DefaultTimeout)
+}
+
+// NewClientWithTimeout creates a discovery client whose requests time out
+// after timeout, for endpoints that need longer or should fail faster
+func NewClientWithTimeout(logger *slog.Logger, timeout time.Duration) *Client {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: timeout,
 		},
 		logger: logger,
 	}
@@ -78,17 +90,233 @@ func (c *Client) DiscoverModels(ctx context.Context, endpoint, apiKey string) ([
 	return modelsResp.Data, nil
 }
 
-// FilterTextModels filters models to only include text generation models
-func FilterTextModels(models []ModelInfo) []ModelInfo {
+// HasModel reports whether model is listed by the given /v1/models endpoint
+func (c *Client) HasModel(ctx context.Context, endpoint, apiKey, model string) (bool, error) {
+	models, err := c.DiscoverModels(ctx, endpoint, apiKey)
+	if err != nil {
+		return false, err
+	}
+
+	for _, m := range models {
+		if m.ID == model {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Probe checks that the given /v1/models endpoint is reachable. Any HTTP
+// response counts, since an auth or routing error still proves the server
+// is there; only connection failures and server errors are reported.
+func (c *Client) Probe(ctx context.Context, endpoint, apiKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// maxDiagnosisBody bounds how much of a response body Diagnose keeps
+const maxDiagnosisBody = 1024
+
+// Diagnosis is an endpoint's answer to a diagnostic request: its HTTP status
+// and the start of its body, or Err if it didn't answer
+type Diagnosis struct {
+	Status int
+	Body   string
+	Err    error
+}
+
+// Diagnose sends a request to endpoint, with body as JSON if not nil, and
+// reports the answer whatever its status, so errors such as a 401 or an
+// unknown model can be shown as the server put them
+func (c *Client) Diagnose(ctx context.Context, method, endpoint, apiKey string, body []byte) Diagnosis {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return Diagnosis{Err: fmt.Errorf("creating request: %w", err)}
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Diagnosis{Err: err}
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxDiagnosisBody))
+	io.Copy(io.Discard, resp.Body)
+	return Diagnosis{Status: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+}
+
+// ModelsEndpoint derives the /v1/models endpoint from a target URL, e.g.
+// "http://host:8000/v1/chat/completions" -> "http://host:8000/v1/models".
+// URLs without a /v1 path segment get /v1/models appended.
+func ModelsEndpoint(targetURL string) (string, error) {
+	return v1Endpoint(targetURL, "models")
+}
+
+// CompletionsEndpoint derives the endpoint serving a request type from a
+// target URL, as ModelsEndpoint does: /v1/chat/completions for
+// chat_completions and /v1/completions for text_completions
+func CompletionsEndpoint(targetURL, requestType string) (string, error) {
+	if requestType == "text_completions" {
+		return v1Endpoint(targetURL, "completions")
+	}
+	return v1Endpoint(targetURL, "chat", "completions")
+}
+
+// v1Endpoint replaces the path of a target URL from its /v1 segment on, if
+// any, with /v1 followed by path
+func v1Endpoint(targetURL string, path ...string) (string, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing target URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("target URL %q must be absolute", targetURL)
+	}
+
+	segments := strings.Split(strings.TrimSuffix(u.Path, "/"), "/")
+	for i, seg := range segments {
+		if seg == "v1" {
+			segments = segments[:i]
+			break
+		}
+	}
+
+	u.Path = strings.Join(append(append(segments, "v1"), path...), "/")
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// HealthEndpoint returns the URL probed to check a target is reachable:
+// healthPath on the target's host, or its /v1/models endpoint when
+// healthPath is empty
+func HealthEndpoint(targetURL, healthPath string) (string, error) {
+	if healthPath == "" {
+		return ModelsEndpoint(targetURL)
+	}
+
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing target URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("target URL %q must be absolute", targetURL)
+	}
+
+	path, err := url.Parse(healthPath)
+	if err != nil {
+		return "", fmt.Errorf("parsing health path: %w", err)
+	}
+	u.Path = path.Path
+	u.RawQuery = path.RawQuery
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// FilterOptions selects which discovered models become benchmark targets
+type FilterOptions struct {
+	// Include keeps only models whose ID matches at least one of these
+	// regular expressions (all models when empty)
+	Include []string
+	// Exclude drops models whose ID matches any of these regular expressions
+	Exclude []string
+	// ModelTypes lists the allowed model_type values (defaults to "text")
+	ModelTypes []string
+}
+
+// FilterModels filters models by type and by include/exclude patterns matched
+// against their IDs. Patterns are unanchored, so use ^ and $ for exact matches.
+func FilterModels(models []ModelInfo, opts FilterOptions) ([]ModelInfo, error) {
+	include, err := compilePatterns(opts.Include)
+	if err != nil {
+		return nil, fmt.Errorf("compiling include patterns: %w", err)
+	}
+	exclude, err := compilePatterns(opts.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("compiling exclude patterns: %w", err)
+	}
+
+	modelTypes := opts.ModelTypes
+	if len(modelTypes) == 0 {
+		modelTypes = []string{"text"}
+	}
+	allowedTypes := make(map[string]bool, len(modelTypes))
+	for _, t := range modelTypes {
+		allowedTypes[t] = true
+	}
+
 	filtered := make([]ModelInfo, 0, len(models))
 	for _, model := range models {
-		if model.ModelType == "text" {
-			filtered = append(filtered, model)
+		if !allowedTypes[model.ModelType] {
+			continue
+		}
+		if len(include) > 0 && !matchesAny(include, model.ID) {
+			continue
 		}
+		if matchesAny(exclude, model.ID) {
+			continue
+		}
+		filtered = append(filtered, model)
 	}
+	return filtered, nil
+}
+
+// FilterTextModels filters models to only include text generation models
+func FilterTextModels(models []ModelInfo) []ModelInfo {
+	// No patterns, so FilterModels can't fail
+	filtered, _ := FilterModels(models, FilterOptions{})
 	return filtered
 }
 
+// compilePatterns compiles a list of regular expressions
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAny reports whether s matches any of the given patterns
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateTargets converts discovered models into benchmark targets
 func GenerateTargets(models []ModelInfo, baseURL, apiKey string, envName string) []config.Target {
 	targets := make([]config.Target, 0, len(models))
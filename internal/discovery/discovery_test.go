@@ -91,6 +91,82 @@ func TestClient_DiscoverModels(t *testing.T) {
 	})
 }
 
+func TestClient_HasModel(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ModelsResponse{
+			Object: "list",
+			Data:   []ModelInfo{{ID: "served-model", ModelType: "text"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(logger)
+
+	present, err := client.HasModel(context.Background(), server.URL+"/v1/models", "", "served-model")
+	require.NoError(t, err)
+	assert.True(t, present)
+
+	present, err = client.HasModel(context.Background(), server.URL+"/v1/models", "", "missing-model")
+	require.NoError(t, err)
+	assert.False(t, present)
+}
+
+func TestModelsEndpoint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"http://host:8000/v1/chat/completions", "http://host:8000/v1/models"},
+		{"http://host:8000/v1", "http://host:8000/v1/models"},
+		{"http://host:8000/v1/", "http://host:8000/v1/models"},
+		{"http://host:8000", "http://host:8000/v1/models"},
+		{"https://gw.example.com/llm/v1/completions?x=1", "https://gw.example.com/llm/v1/models"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ModelsEndpoint(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := ModelsEndpoint("not-a-url")
+	assert.Error(t, err)
+}
+
+func TestCompletionsEndpoint(t *testing.T) {
+	chat, err := CompletionsEndpoint("http://host:8000/v1/models", "chat_completions")
+	require.NoError(t, err)
+	assert.Equal(t, "http://host:8000/v1/chat/completions", chat)
+
+	text, err := CompletionsEndpoint("http://host:8000", "text_completions")
+	require.NoError(t, err)
+	assert.Equal(t, "http://host:8000/v1/completions", text)
+}
+
+func TestDiagnose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": "invalid api key"}` + "\n"))
+	}))
+
+	client := NewClient(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	d := client.Diagnose(context.Background(), http.MethodPost, server.URL+"/v1/chat/completions", "test-key", []byte(`{}`))
+	require.NoError(t, d.Err)
+	assert.Equal(t, http.StatusUnauthorized, d.Status)
+	assert.Equal(t, `{"error": "invalid api key"}`, d.Body)
+
+	server.Close()
+	d = client.Diagnose(context.Background(), http.MethodGet, server.URL+"/v1/models", "", nil)
+	assert.Error(t, d.Err)
+}
+
 func TestFilterTextModels(t *testing.T) {
 	models := []ModelInfo{
 		{ID: "text-1", ModelType: "text"},
@@ -106,6 +182,60 @@ func TestFilterTextModels(t *testing.T) {
 	assert.Equal(t, "text-2", filtered[1].ID)
 }
 
+func TestFilterModels(t *testing.T) {
+	models := []ModelInfo{
+		{ID: "prod-llama-8b", ModelType: "text"},
+		{ID: "prod-llama-8b-canary", ModelType: "text"},
+		{ID: "dev-llama-8b", ModelType: "text"},
+		{ID: "prod-qwen-vl", ModelType: "vision-language"},
+		{ID: "prod-embed", ModelType: "embedding"},
+	}
+
+	ids := func(models []ModelInfo) []string {
+		out := make([]string, 0, len(models))
+		for _, m := range models {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	t.Run("defaults to text models", func(t *testing.T) {
+		filtered, err := FilterModels(models, FilterOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-llama-8b", "prod-llama-8b-canary", "dev-llama-8b"}, ids(filtered))
+	})
+
+	t.Run("include pattern", func(t *testing.T) {
+		filtered, err := FilterModels(models, FilterOptions{Include: []string{"^prod-"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-llama-8b", "prod-llama-8b-canary"}, ids(filtered))
+	})
+
+	t.Run("exclude wins over include", func(t *testing.T) {
+		filtered, err := FilterModels(models, FilterOptions{
+			Include: []string{"^prod-"},
+			Exclude: []string{"-canary$"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-llama-8b"}, ids(filtered))
+	})
+
+	t.Run("allowed types", func(t *testing.T) {
+		filtered, err := FilterModels(models, FilterOptions{
+			Include:    []string{"^prod-"},
+			ModelTypes: []string{"text", "vision-language"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-llama-8b", "prod-llama-8b-canary", "prod-qwen-vl"}, ids(filtered))
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := FilterModels(models, FilterOptions{Exclude: []string{"("}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exclude")
+	})
+}
+
 func TestGenerateTargets(t *testing.T) {
 	models := []ModelInfo{
 		{ID: "unsloth/gpt-oss-20b", ModelType: "text"},
@@ -152,3 +282,21 @@ func TestNormalizeModelName(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthEndpoint(t *testing.T) {
+	tests := []struct {
+		url, path, expected string
+	}{
+		{"http://host:8000/v1/chat/completions", "", "http://host:8000/v1/models"},
+		{"http://host:8000/v1/chat/completions", "/health", "http://host:8000/health"},
+		{"https://gw.example.com/llm/v1", "/llm/ready?full=1", "https://gw.example.com/llm/ready?full=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url+tt.path, func(t *testing.T) {
+			result, err := HealthEndpoint(tt.url, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -5,17 +5,48 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// Default latency histogram buckets, in seconds
+var (
+	DefaultTTFTBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	DefaultITLBuckets  = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+	DefaultE2EBuckets  = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100}
+)
+
+// Options of the latency histograms
+var (
+	ttftOpts = prometheus.HistogramOpts{
+		Name:    "guidellm_ttft_seconds",
+		Help:    "Time to first token in seconds",
+		Buckets: DefaultTTFTBuckets,
+	}
+	itlOpts = prometheus.HistogramOpts{
+		Name:    "guidellm_itl_seconds",
+		Help:    "Inter-token latency in seconds",
+		Buckets: DefaultITLBuckets,
+	}
+	e2eOpts = prometheus.HistogramOpts{
+		Name:    "guidellm_e2e_latency_seconds",
+		Help:    "End-to-end request latency in seconds",
+		Buckets: DefaultE2EBuckets,
+	}
+)
+
 var (
 	// Labels used for all metrics
 	labels = []string{"environment", "target", "model"}
 
+	// Labels of the metrics describing a run's requests, tokens, throughput
+	// and latency: labels plus the run's rate and profile. These are empty,
+	// adding no series, unless the target sets sweep_labels.
+	runLabels = []string{"environment", "target", "model", "rate", "profile"}
+
 	// Request metrics
 	RequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "guidellm_requests_total",
 			Help: "Total number of requests made to the LLM",
 		},
-		labels,
+		runLabels,
 	)
 
 	RequestsSuccessful = promauto.NewCounterVec(
@@ -23,7 +54,7 @@ var (
 			Name: "guidellm_requests_successful_total",
 			Help: "Total number of successful requests",
 		},
-		labels,
+		runLabels,
 	)
 
 	RequestsFailed = promauto.NewCounterVec(
@@ -31,35 +62,22 @@ var (
 			Name: "guidellm_requests_failed_total",
 			Help: "Total number of failed requests",
 		},
-		labels,
+		runLabels,
 	)
 
-	// Latency metrics
-	TimeToFirstToken = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "guidellm_ttft_seconds",
-			Help:    "Time to first token in seconds",
-			Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
-		},
-		labels,
-	)
+	// Latency metrics, with the buckets from config (see SetLatencyBuckets)
+	TimeToFirstToken  = promauto.NewHistogramVec(ttftOpts, runLabels)
+	InterTokenLatency = promauto.NewHistogramVec(itlOpts, runLabels)
+	EndToEndLatency   = promauto.NewHistogramVec(e2eOpts, runLabels)
 
-	InterTokenLatency = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "guidellm_itl_seconds",
-			Help:    "Inter-token latency in seconds",
-			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	// Histogram observations recorded by the latest run, to spot runaway
+	// synthetic sample generation
+	HistogramObservations = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_histogram_observations",
+			Help: "Latency histogram observations recorded by the last benchmark run",
 		},
-		labels,
-	)
-
-	EndToEndLatency = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "guidellm_e2e_latency_seconds",
-			Help:    "End-to-end request latency in seconds",
-			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100},
-		},
-		labels,
+		runLabels,
 	)
 
 	// Throughput metrics
@@ -68,7 +86,7 @@ var (
 			Name: "guidellm_output_tokens_per_second",
 			Help: "Output tokens generated per second",
 		},
-		labels,
+		runLabels,
 	)
 
 	RequestsPerSecond = promauto.NewGaugeVec(
@@ -76,7 +94,17 @@ var (
 			Name: "guidellm_requests_per_second",
 			Help: "Requests completed per second",
 		},
-		labels,
+		runLabels,
+	)
+
+	// Fraction of the latest run's requests that succeeded, so dashboards
+	// needn't divide counters that reset
+	RequestSuccessRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_request_success_rate",
+			Help: "Fraction of requests in the last benchmark run that succeeded (0 if it made none)",
+		},
+		runLabels,
 	)
 
 	// Token metrics
@@ -85,7 +113,7 @@ var (
 			Name: "guidellm_prompt_tokens_total",
 			Help: "Total prompt tokens sent",
 		},
-		labels,
+		runLabels,
 	)
 
 	OutputTokensTotal = promauto.NewCounterVec(
@@ -93,7 +121,7 @@ var (
 			Name: "guidellm_output_tokens_total",
 			Help: "Total output tokens received",
 		},
-		labels,
+		runLabels,
 	)
 
 	// Benchmark run metrics
@@ -105,11 +133,42 @@ var (
 		labels,
 	)
 
+	// Failed runs by reason, a small fixed set of failure categories
 	BenchmarkRunsFailed = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "guidellm_benchmark_runs_failed_total",
 			Help: "Total number of failed benchmark runs",
 		},
+		append(labels[:len(labels):len(labels)], "reason"),
+	)
+
+	// Runs that completed without making a single request, which usually
+	// means guidellm couldn't reach or authenticate against the target
+	ZeroRequestRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "guidellm_zero_request_runs_total",
+			Help: "Total number of benchmark runs that completed with zero requests",
+		},
+		labels,
+	)
+
+	// Runs that failed before guidellm started (temp dir, missing binary,
+	// bad working directory), which point at the host rather than the
+	// target. Also counted as failed runs with reason spawn_error.
+	SpawnErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "guidellm_spawn_errors_total",
+			Help: "Total number of benchmark runs that failed before guidellm could start",
+		},
+		labels,
+	)
+
+	// Current streak of failed runs, reset to 0 by a successful run
+	ConsecutiveFailures = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_consecutive_failures",
+			Help: "Number of consecutive failed benchmark runs (0 after a successful run)",
+		},
 		labels,
 	)
 
@@ -121,6 +180,16 @@ var (
 		labels,
 	)
 
+	// Kind of benchmark data a target runs with (synthetic or dataset),
+	// as an info-style series to join on. Only set when enabled in config.
+	TargetDataKind = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_target_data_kind",
+			Help: "Kind of benchmark data used for the target, in the data_kind label (always 1)",
+		},
+		append(labels[:len(labels):len(labels)], "data_kind"),
+	)
+
 	// Runner status
 	RunnerUp = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -130,6 +199,35 @@ var (
 		labels,
 	)
 
+	// Targets that could not be started even after retrying
+	TargetStartFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "guidellm_target_start_failures_total",
+			Help: "Total number of targets that failed to start after all retries",
+		},
+		labels,
+	)
+
+	// Whether the target's model is listed by its endpoint's /v1/models.
+	// Only set when model checks are enabled.
+	TargetModelPresent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_target_model_present",
+			Help: "Whether the target's model is served by its endpoint (1 = present, 0 = missing)",
+		},
+		labels,
+	)
+
+	// Webhook notifications by outcome: delivered, failed (after all
+	// retries) or dropped (delivery queue full)
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "guidellm_webhook_deliveries_total",
+			Help: "Total number of webhook notifications by result",
+		},
+		[]string{"result"},
+	)
+
 	// Scheduler status
 	SchedulerPaused = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -147,3 +245,166 @@ func Labels(environment, target, model string) prometheus.Labels {
 		"model":       model,
 	}
 }
+
+// FailureLabels returns labels extended with a failure reason, for
+// BenchmarkRunsFailed
+func FailureLabels(labels prometheus.Labels, reason string) prometheus.Labels {
+	return withLabel(labels, "reason", reason)
+}
+
+// RunLabels returns labels extended with a run's rate and profile, for the
+// request, token, throughput and latency metrics. Both are empty for targets
+// without sweep_labels.
+func RunLabels(labels prometheus.Labels, rate, profile string) prometheus.Labels {
+	return withLabel(withLabel(labels, "rate", rate), "profile", profile)
+}
+
+// DataKindLabels returns labels extended with a data kind, for
+// TargetDataKind
+func DataKindLabels(labels prometheus.Labels, dataKind string) prometheus.Labels {
+	return withLabel(labels, "data_kind", dataKind)
+}
+
+// withLabel returns a copy of labels with name set to value
+func withLabel(labels prometheus.Labels, name, value string) prometheus.Labels {
+	extended := make(prometheus.Labels, len(labels)+1)
+	for k, v := range labels {
+		extended[k] = v
+	}
+	extended[name] = value
+	return extended
+}
+
+// SetLatencyBuckets replaces the TTFT, ITL and end-to-end latency
+// histograms with ones using the given buckets. An empty set keeps that
+// histogram's current buckets. It must be called at startup, before any
+// observations are made or the histograms are used concurrently.
+func SetLatencyBuckets(ttft, itl, e2e []float64) {
+	TimeToFirstToken = replaceHistogram(TimeToFirstToken, ttftOpts, ttft)
+	InterTokenLatency = replaceHistogram(InterTokenLatency, itlOpts, itl)
+	EndToEndLatency = replaceHistogram(EndToEndLatency, e2eOpts, e2e)
+}
+
+// replaceHistogram unregisters vec and registers a histogram like it with
+// buckets instead, unless buckets is empty
+func replaceHistogram(vec *prometheus.HistogramVec, opts prometheus.HistogramOpts, buckets []float64) *prometheus.HistogramVec {
+	if len(buckets) == 0 {
+		return vec
+	}
+	prometheus.DefaultRegisterer.Unregister(vec)
+	opts.Buckets = buckets
+	return promauto.NewHistogramVec(opts, runLabels)
+}
+
+// tagKeys are the target tag keys exported by TargetTags
+var tagKeys []string
+
+// TargetTags exports each target's allow-listed tags, one tag_<key> label
+// per key, as an info-style series to join on. It is nil, exporting
+// nothing, until SetTagLabels is called with some keys.
+var TargetTags *prometheus.GaugeVec
+
+// SetTagLabels registers TargetTags with a label for each of keys. It must
+// be called at startup, before SetTargetTags is used concurrently.
+func SetTagLabels(keys []string) {
+	if TargetTags != nil {
+		prometheus.DefaultRegisterer.Unregister(TargetTags)
+		TargetTags = nil
+	}
+	tagKeys = keys
+	if len(keys) == 0 {
+		return
+	}
+	names := append([]string(nil), labels...)
+	for _, key := range keys {
+		names = append(names, "tag_"+key)
+	}
+	TargetTags = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "guidellm_target_tags",
+			Help: "Allow-listed tags of the target, in tag_<key> labels (always 1)",
+		},
+		names,
+	)
+}
+
+// SetTargetTags sets the TargetTags series of a target, with an empty label
+// for each allow-listed key it isn't tagged with. It does nothing unless
+// SetTagLabels enabled tag labels.
+func SetTargetTags(labels prometheus.Labels, tags map[string]string) {
+	if TargetTags == nil {
+		return
+	}
+	tagged := labels
+	for _, key := range tagKeys {
+		tagged = withLabel(tagged, "tag_"+key, tags[key])
+	}
+	TargetTags.With(tagged).Set(1)
+}
+
+// FleetOutputTokensPerSecond is the sum of the latest output tokens per
+// second of every target that ran recently, computed at each scrape so
+// targets drop out of it as their runs go stale. It is nil until
+// SetFleetThroughput is called.
+var FleetOutputTokensPerSecond prometheus.GaugeFunc
+
+// SetFleetThroughput registers FleetOutputTokensPerSecond to report what fn
+// returns, replacing any registered before. fn is called on each scrape.
+func SetFleetThroughput(fn func() float64) {
+	if FleetOutputTokensPerSecond != nil {
+		prometheus.DefaultRegisterer.Unregister(FleetOutputTokensPerSecond)
+	}
+	FleetOutputTokensPerSecond = promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "guidellm_fleet_output_tokens_per_second",
+			Help: "Total output tokens per second across targets with a recent run",
+		},
+		fn,
+	)
+}
+
+// targetVec is a per-target metric vector
+type targetVec interface {
+	DeletePartialMatch(prometheus.Labels) int
+}
+
+// targetVecs returns the per-target metric vectors, cleared when a target
+// is removed
+func targetVecs() []targetVec {
+	vecs := []targetVec{
+		RequestsTotal,
+		RequestsSuccessful,
+		RequestsFailed,
+		TimeToFirstToken,
+		InterTokenLatency,
+		EndToEndLatency,
+		HistogramObservations,
+		OutputTokensPerSecond,
+		RequestsPerSecond,
+		RequestSuccessRate,
+		PromptTokensTotal,
+		OutputTokensTotal,
+		BenchmarkRunsTotal,
+		BenchmarkRunsFailed,
+		ZeroRequestRuns,
+		SpawnErrors,
+		ConsecutiveFailures,
+		LastBenchmarkTimestamp,
+		TargetDataKind,
+		RunnerUp,
+		TargetStartFailures,
+		TargetModelPresent,
+	}
+	if TargetTags != nil {
+		vecs = append(vecs, TargetTags)
+	}
+	return vecs
+}
+
+// DeleteTarget deletes every metric series for a target in an environment
+func DeleteTarget(environment, target string) {
+	match := prometheus.Labels{"environment": environment, "target": target}
+	for _, vec := range targetVecs() {
+		vec.DeletePartialMatch(match)
+	}
+}
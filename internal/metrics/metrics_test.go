@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSetLatencyBuckets(t *testing.T) {
+	t.Cleanup(func() { SetLatencyBuckets(DefaultTTFTBuckets, nil, nil) })
+
+	itl := InterTokenLatency
+	SetLatencyBuckets([]float64{0.5, 1, 2}, nil, nil)
+	if InterTokenLatency != itl {
+		t.Error("expected histograms without configured buckets to be kept")
+	}
+
+	labels := RunLabels(Labels("test", "bucket-target", "test-model"), "", "")
+	TimeToFirstToken.With(labels).Observe(0.75)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "guidellm_ttft_seconds" {
+			continue
+		}
+		buckets := family.GetMetric()[0].GetHistogram().GetBucket()
+		var bounds []float64
+		for _, b := range buckets {
+			bounds = append(bounds, b.GetUpperBound())
+		}
+		if len(bounds) != 3 || bounds[0] != 0.5 || bounds[2] != 2 {
+			t.Errorf("expected buckets [0.5 1 2], got %v", bounds)
+		}
+		if got := buckets[1].GetCumulativeCount(); got != 1 {
+			t.Errorf("expected the observation in the 1s bucket, got count %d", got)
+		}
+		return
+	}
+	t.Error("guidellm_ttft_seconds not registered")
+}
+
+func TestSetTargetTags(t *testing.T) {
+	t.Cleanup(func() { SetTagLabels(nil) })
+
+	labels := Labels("test", "tagged-target", "test-model")
+	SetTargetTags(labels, map[string]string{"team": "search"})
+	if TargetTags != nil {
+		t.Fatal("expected no tag series without tag labels")
+	}
+
+	SetTagLabels([]string{"team", "tier"})
+	SetTargetTags(labels, map[string]string{"team": "search", "owner": "alice"})
+	tagged := withLabel(withLabel(labels, "tag_team", "search"), "tag_tier", "")
+	if got := testutil.ToFloat64(TargetTags.With(tagged)); got != 1 {
+		t.Errorf("expected the allow-listed tags to be exported, got %g", got)
+	}
+	if got := testutil.CollectAndCount(TargetTags); got != 1 {
+		t.Errorf("expected 1 tag series, got %d", got)
+	}
+
+	DeleteTarget("test", "tagged-target")
+	if got := testutil.CollectAndCount(TargetTags); got != 0 {
+		t.Errorf("expected removing the target to delete its tag series, got %d", got)
+	}
+}
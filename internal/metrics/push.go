@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// pushTimeout bounds each push to the Pushgateway
+const pushTimeout = 10 * time.Second
+
+// Pusher pushes targets' metrics to a Prometheus Pushgateway, for runs that
+// exit before Prometheus could scrape them
+type Pusher struct {
+	url      string
+	job      string
+	gatherer prometheus.Gatherer
+	client   *http.Client
+}
+
+// NewPusher creates a pusher of the default registry's metrics to the
+// Pushgateway at url, as job
+func NewPusher(url, job string) *Pusher {
+	return &Pusher{
+		url:      url,
+		job:      job,
+		gatherer: prometheus.DefaultGatherer,
+		client:   &http.Client{Timeout: pushTimeout},
+	}
+}
+
+// PushTarget replaces the metrics of a target's group on the Pushgateway,
+// grouped by environment and target, with the target's current series.
+// Series without the target's labels, such as fleet totals, aren't pushed.
+func (p *Pusher) PushTarget(ctx context.Context, environment, target string) error {
+	err := push.New(p.url, p.job).
+		Client(p.client).
+		Gatherer(targetGatherer(p.gatherer, environment, target)).
+		Grouping("environment", environment).
+		Grouping("target", target).
+		PushContext(ctx)
+	if err != nil {
+		return fmt.Errorf("pushing metrics of target %q: %w", target, err)
+	}
+	return nil
+}
+
+// targetGatherer returns a gatherer of just the series of g labelled with
+// the given environment and target. Those labels are dropped, as the push
+// client refuses series carrying a grouping label; the Pushgateway adds them
+// back from the group.
+func targetGatherer(g prometheus.Gatherer, environment, target string) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		if err != nil {
+			return nil, err
+		}
+		var filtered []*dto.MetricFamily
+		for _, mf := range families {
+			var series []*dto.Metric
+			for _, m := range mf.GetMetric() {
+				if hasLabel(m, "environment", environment) && hasLabel(m, "target", target) {
+					series = append(series, &dto.Metric{
+						Label:       withoutLabels(m.GetLabel(), "environment", "target"),
+						Gauge:       m.Gauge,
+						Counter:     m.Counter,
+						Summary:     m.Summary,
+						Untyped:     m.Untyped,
+						Histogram:   m.Histogram,
+						TimestampMs: m.TimestampMs,
+					})
+				}
+			}
+			if len(series) > 0 {
+				filtered = append(filtered, &dto.MetricFamily{
+					Name:   mf.Name,
+					Help:   mf.Help,
+					Type:   mf.Type,
+					Unit:   mf.Unit,
+					Metric: series,
+				})
+			}
+		}
+		return filtered, nil
+	})
+}
+
+// hasLabel reports whether m has the label name set to value
+func hasLabel(m *dto.Metric, name, value string) bool {
+	for _, lp := range m.GetLabel() {
+		if lp.GetName() == name {
+			return lp.GetValue() == value
+		}
+	}
+	return false
+}
+
+// withoutLabels returns the label pairs other than those named
+func withoutLabels(pairs []*dto.LabelPair, names ...string) []*dto.LabelPair {
+	kept := make([]*dto.LabelPair, 0, len(pairs))
+	for _, lp := range pairs {
+		if !slices.Contains(names, lp.GetName()) {
+			kept = append(kept, lp)
+		}
+	}
+	return kept
+}
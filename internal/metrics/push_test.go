@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestTargetGatherer verifies that only a target's own series are gathered
+// for pushing, without the labels its group already carries
+func TestTargetGatherer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_up", Help: "Test gauge"}, labels)
+	fleet := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_fleet", Help: "Test fleet gauge"})
+	reg.MustRegister(gauge, fleet)
+	gauge.With(Labels("dev", "a", "m")).Set(1)
+	gauge.With(Labels("dev", "b", "m")).Set(2)
+	gauge.With(Labels("prod", "a", "m")).Set(3)
+	fleet.Set(4)
+
+	families, err := targetGatherer(reg, "dev", "a").Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) != 1 || families[0].GetName() != "test_up" || len(families[0].GetMetric()) != 1 {
+		t.Fatalf("expected just the target's test_up series, got %v", families)
+	}
+	m := families[0].GetMetric()[0]
+	if m.GetGauge().GetValue() != 1 {
+		t.Errorf("expected the dev/a series, got value %g", m.GetGauge().GetValue())
+	}
+	if pairs := m.GetLabel(); len(pairs) != 1 || pairs[0].GetName() != "model" {
+		t.Errorf("expected only the model label left, got %v", pairs)
+	}
+}
+
+func TestPushTarget(t *testing.T) {
+	var method, path string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_up", Help: "Test gauge"}, labels)
+	reg.MustRegister(gauge)
+	gauge.With(Labels("dev", "a", "m")).Set(1)
+
+	pusher := NewPusher(gateway.URL, "guidellm-runner")
+	pusher.gatherer = reg
+	if err := pusher.PushTarget(context.Background(), "dev", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The grouping labels may come in either order
+	group := []string{"/metrics/job/guidellm-runner/environment/dev/target/a", "/metrics/job/guidellm-runner/target/a/environment/dev"}
+	if method != http.MethodPut || !slices.Contains(group, path) {
+		t.Errorf("expected PUT %s, got %s %s", group[0], method, path)
+	}
+
+	gateway.Close()
+	if err := pusher.PushTarget(context.Background(), "dev", "a"); err == nil {
+		t.Error("expected an error once the pushgateway is gone")
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server serves Prometheus metrics and a liveness check on its own mux, so
+// it doesn't depend on http.DefaultServeMux and can be shut down
+type Server struct {
+	server *http.Server
+	logger *slog.Logger
+}
+
+// NewServer creates a metrics server listening on port. With openMetrics,
+// scrapers that ask for the OpenMetrics format get it, exemplars included.
+func NewServer(port int, openMetrics bool, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: openMetrics}),
+	))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	return &Server{
+		server: &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Start listens on the server's address and serves until Shutdown
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves metrics on ln until Shutdown
+func (s *Server) Serve(ln net.Listener) error {
+	s.logger.Info("starting prometheus metrics server", "addr", ln.Addr().String())
+	if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the metrics server
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("shutting down metrics server")
+	return s.server.Shutdown(ctx)
+}
+
+// Addr returns the server address
+func (s *Server) Addr() string {
+	return s.server.Addr
+}
@@ -243,3 +243,26 @@ func generateValuesFromDistribution(stats *DistributionSummary) []float64 {
 
 	return values
 }
+
+// Percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks. values must be sorted ascending;
+// returns 0 for an empty slice.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return values[0]
+	}
+	if p >= 100 {
+		return values[len(values)-1]
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(rank)
+	frac := rank - float64(lower)
+	if lower+1 >= len(values) {
+		return values[lower]
+	}
+	return values[lower] + frac*(values[lower+1]-values[lower])
+}
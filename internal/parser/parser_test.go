@@ -273,3 +273,27 @@ func TestGenerateValuesFromDistribution_ZeroCount(t *testing.T) {
 		t.Error("Expected nil for zero count")
 	}
 }
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{50, 3},
+		{25, 2},
+		{90, 4.6},
+		{100, 5},
+	}
+	for _, tt := range tests {
+		if got := Percentile(values, tt.p); got < tt.expected-1e-9 || got > tt.expected+1e-9 {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.expected)
+		}
+	}
+
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 for empty input, got %v", got)
+	}
+}
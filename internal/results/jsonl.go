@@ -0,0 +1,73 @@
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// Record is one completed run as archived to disk
+type Record struct {
+	Timestamp   time.Time             `json:"timestamp"`
+	Environment string                `json:"environment"`
+	Target      string                `json:"target"`
+	Model       string                `json:"model"`
+	RunID       string                `json:"run_id,omitempty"`
+	Error       string                `json:"error,omitempty"` // set for runs that completed but failed
+	Results     *parser.ParsedResults `json:"results"`
+}
+
+// DirWriter appends records as JSON lines to a directory, in one file per
+// UTC day named results-YYYY-MM-DD.jsonl. The directory is created on the
+// first write.
+type DirWriter struct {
+	dir string
+	mu  sync.Mutex // serializes appends so lines never interleave
+}
+
+// NewDirWriter creates a writer for dir
+func NewDirWriter(dir string) *DirWriter {
+	return &DirWriter{dir: dir}
+}
+
+// Path returns the file records timestamped at t are appended to
+func (w *DirWriter) Path(t time.Time) string {
+	return filepath.Join(w.dir, "results-"+t.UTC().Format(time.DateOnly)+".jsonl")
+}
+
+// Store appends rec to the file for its day. The raw guidellm output isn't
+// kept on disk.
+func (w *DirWriter) Store(_ context.Context, rec Record, _ []byte) error {
+	return w.Write(rec)
+}
+
+// Write appends rec to the file for its day
+func (w *DirWriter) Write(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding record: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := os.MkdirAll(w.dir, 0o755); err != nil {
+		return fmt.Errorf("creating results directory: %w", err)
+	}
+	f, err := os.OpenFile(w.Path(rec.Timestamp), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening results file: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("writing results file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+func TestDirWriterRotatesByDay(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "results")
+	w := NewDirWriter(dir)
+
+	day1 := time.Date(2025, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	for _, rec := range []Record{
+		{Timestamp: day1, Target: "a", Results: &parser.ParsedResults{TotalRequests: 1}},
+		{Timestamp: day1, Target: "b", Results: &parser.ParsedResults{TotalRequests: 2}},
+		{Timestamp: day2, Target: "a", Results: &parser.ParsedResults{TotalRequests: 3}},
+	} {
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	read := func(name string) []Record {
+		t.Helper()
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		var recs []Record
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var rec Record
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+			}
+			recs = append(recs, rec)
+		}
+		return recs
+	}
+
+	if recs := read("results-2025-03-01.jsonl"); len(recs) != 2 || recs[1].Target != "b" || recs[1].Results.TotalRequests != 2 {
+		t.Errorf("unexpected day 1 records %+v", recs)
+	}
+	if recs := read("results-2025-03-02.jsonl"); len(recs) != 1 || !recs[0].Timestamp.Equal(day2) {
+		t.Errorf("unexpected day 2 records %+v", recs)
+	}
+}
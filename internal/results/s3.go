@@ -0,0 +1,176 @@
+package results
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+// S3Sink uploads each run to an S3-compatible bucket as two objects under
+// <prefix><environment>/<target>/<timestamp>: the raw guidellm output
+// (-guidellm.json) and the parsed record (-summary.json). Requests are
+// signed with AWS Signature Version 4 and use path-style URLs, which
+// MinIO and other S3-compatible stores also accept.
+type S3Sink struct {
+	endpoint   *url.URL
+	bucket     string
+	prefix     string
+	region     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Sink creates a sink for cfg. It returns an error if the endpoint or
+// credentials are missing or invalid, as every upload would then fail.
+func NewS3Sink(cfg config.S3Config) (*S3Sink, error) {
+	if err := config.ValidateURL(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("endpoint: %w", err)
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("access_key_id and secret_access_key are required")
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing endpoint: %w", err)
+	}
+	return &S3Sink{
+		endpoint:   endpoint,
+		bucket:     cfg.Bucket,
+		prefix:     cfg.Prefix,
+		region:     cfg.Region,
+		accessKey:  cfg.AccessKeyID,
+		secretKey:  cfg.SecretAccessKey,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+		now:        time.Now,
+	}, nil
+}
+
+// Key returns the object key prefix for rec, without a suffix
+func (s *S3Sink) Key(rec Record) string {
+	return fmt.Sprintf("%s%s/%s/%s", s.prefix, rec.Environment, rec.Target, rec.Timestamp.UTC().Format("20060102T150405Z"))
+}
+
+// Store uploads the raw guidellm output (if any) and the parsed record
+func (s *S3Sink) Store(ctx context.Context, rec Record, raw []byte) error {
+	key := s.Key(rec)
+	if len(raw) > 0 {
+		if err := s.put(ctx, key+"-guidellm.json", raw); err != nil {
+			return err
+		}
+	}
+
+	summary, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding record: %w", err)
+	}
+	return s.put(ctx, key+"-summary.json", summary)
+}
+
+// put uploads body as a JSON object
+func (s *S3Sink) put(ctx context.Context, key string, body []byte) error {
+	u := *s.endpoint
+	u.Path = "/" + s.bucket + "/" + key
+	u.RawPath = "/" + escapePath(s.bucket) + "/" + escapePath(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.sign(req, body)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("uploading %s: unexpected status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (s *S3Sink) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"", // no query string
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey(s.secretKey, date, s.region, "s3"), []byte(stringToSign)))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// escapePath percent-encodes every byte of an object key except unreserved
+// characters and "/", as Signature Version 4 canonical URIs require
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// signingKey derives the Signature Version 4 signing key for a day, region
+// and service
+func signingKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(service))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package results
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// TestSigningKey checks key derivation against the example in the AWS
+// Signature Version 4 documentation
+func TestSigningKey(t *testing.T) {
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got, want := hex.EncodeToString(key), "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestS3SinkUploadsRawAndSummary(t *testing.T) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20250301/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get("X-Amz-Content-Sha256"); got != sha256Hex(body) {
+			t.Errorf("payload hash %q doesn't match body", got)
+		}
+
+		mu.Lock()
+		objects[r.URL.EscapedPath()] = body
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	sink, err := NewS3Sink(config.S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "bench",
+		Region:          "eu-west-1",
+		Prefix:          "runs/",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Timeout:         5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	rec := Record{
+		Timestamp:   time.Date(2025, 3, 1, 11, 59, 30, 0, time.UTC),
+		Environment: "prod",
+		Target:      "llama 3",
+		Results:     &parser.ParsedResults{TotalRequests: 5},
+	}
+	if err := sink.Store(context.Background(), rec, []byte(`{"benchmarks":[]}`)); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+
+	if got := string(objects["/bench/runs/prod/llama%203/20250301T115930Z-guidellm.json"]); got != `{"benchmarks":[]}` {
+		t.Errorf("unexpected raw object %q (objects: %v)", got, objects)
+	}
+	var summary Record
+	if err := json.Unmarshal(objects["/bench/runs/prod/llama%203/20250301T115930Z-summary.json"], &summary); err != nil {
+		t.Fatalf("invalid summary object: %v", err)
+	}
+	if summary.Target != "llama 3" || summary.Results.TotalRequests != 5 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestS3SinkReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	sink, err := NewS3Sink(config.S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "bench",
+		Region:          "us-east-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Timeout:         5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sink.Store(context.Background(), Record{Environment: "prod", Target: "llama"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestNewS3SinkRejectsInvalidConfig(t *testing.T) {
+	valid := config.S3Config{
+		Endpoint:        "https://s3.us-east-1.amazonaws.com",
+		Bucket:          "bench",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}
+	if _, err := NewS3Sink(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*config.S3Config)
+	}{
+		{"empty endpoint", func(c *config.S3Config) { c.Endpoint = "" }},
+		{"endpoint without scheme", func(c *config.S3Config) { c.Endpoint = "s3.amazonaws.com" }},
+		{"missing access key", func(c *config.S3Config) { c.AccessKeyID = "" }},
+		{"missing secret key", func(c *config.S3Config) { c.SecretAccessKey = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := NewS3Sink(cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package results
+
+import "context"
+
+// ResultSink stores the results of completed runs. Implementations are
+// called after each run with its record and the raw guidellm JSON it was
+// parsed from.
+type ResultSink interface {
+	Store(ctx context.Context, rec Record, raw []byte) error
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// SetBaseline pins a run in a target's results history, the latest with
+// output throughput if runID is "", as the target's baseline. Summaries then
+// report the latest results relative to it, and regression alerts compare
+// against it rather than the rolling window. A run without output
+// throughput can't serve as a baseline. The baseline is kept in memory, so
+// it doesn't survive a restart.
+func (m *DefaultTargetManager) SetBaseline(name string, runID string) (*api.BaselineResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+
+	var entry historyEntry
+	if runID == "" {
+		var found bool
+		for i := len(mt.history) - 1; i >= 0 && !found; i-- {
+			entry, found = mt.history[i], mt.history[i].results.OutputTokensPerSec > 0
+		}
+		if !found {
+			return nil, fmt.Errorf("target %q has no results with output throughput to use as a baseline: %w", name, api.ErrConflict)
+		}
+	} else {
+		var found bool
+		if entry, found = findHistoryEntry(mt.history, runID); !found {
+			return nil, fmt.Errorf("run %q in the history of target %q %w", runID, name, api.ErrNotFound)
+		}
+		if entry.results.OutputTokensPerSec <= 0 {
+			return nil, fmt.Errorf("run %q of target %q has no output throughput to use as a baseline: %w", runID, name, api.ErrConflict)
+		}
+	}
+
+	mt.baseline = &api.BaselineResponse{
+		Name:    name,
+		RunID:   entry.runID,
+		At:      entry.at,
+		SetAt:   time.Now(),
+		Results: entry.results,
+	}
+	m.logger.Info("baseline set", "target", name, "run_id", entry.runID)
+
+	baseline := *mt.baseline
+	return &baseline, nil
+}
+
+// GetBaseline returns a target's pinned baseline
+func (m *DefaultTargetManager) GetBaseline(name string) (*api.BaselineResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+	if mt.baseline == nil {
+		return nil, fmt.Errorf("baseline of target %q %w", name, api.ErrNotFound)
+	}
+	baseline := *mt.baseline
+	return &baseline, nil
+}
+
+// ClearBaseline unpins a target's baseline
+func (m *DefaultTargetManager) ClearBaseline(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return errTargetNotFound(name)
+	}
+	if mt.baseline == nil {
+		return fmt.Errorf("baseline of target %q %w", name, api.ErrNotFound)
+	}
+	mt.baseline = nil
+	m.logger.Info("baseline cleared", "target", name)
+	return nil
+}
+
+// baselineDeltas returns the percentage change of latest from a pinned
+// baseline's results
+func baselineDeltas(baseline *api.BaselineResponse, latest *parser.ParsedResults) *api.BaselineDeltas {
+	deltas := &api.BaselineDeltas{RunID: baseline.RunID}
+	base := baseline.Results
+
+	deltas.OutputTokensPerSec = percentChange(base.OutputTokensPerSec, latest.OutputTokensPerSec)
+	deltas.RequestsPerSec = percentChange(base.RequestsPerSec, latest.RequestsPerSec)
+	baseTTFT, latestTTFT := summarizeLatency(base.TTFTValues, nil), summarizeLatency(latest.TTFTValues, nil)
+	if baseTTFT != nil && latestTTFT != nil {
+		deltas.TTFTP95 = percentChange(baseTTFT.P95, latestTTFT.P95)
+	}
+	baseE2E, latestE2E := summarizeLatency(base.E2EValues, base.E2EStats), summarizeLatency(latest.E2EValues, latest.E2EStats)
+	if baseE2E != nil && latestE2E != nil {
+		deltas.E2EP95 = percentChange(baseE2E.P95, latestE2E.P95)
+	}
+	return deltas
+}
+
+// percentChange returns the change from base to v as a percentage of base,
+// or nil if base is 0
+func percentChange(base, v float64) *float64 {
+	if base == 0 {
+		return nil
+	}
+	change := (v - base) / base * 100
+	return &change
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// DefaultCompareThreshold is the percentage regression that fails a run
+// comparison when neither the request nor the target's
+// regression_threshold sets one
+const DefaultCompareThreshold = 10.0
+
+// CompareRuns compares the throughput and latency percentiles of two runs
+// in a target's results history, judging each metric against the
+// thresholds: throughput may not drop, nor a latency percentile rise, by
+// more than its threshold percentage
+func (m *DefaultTargetManager) CompareRuns(name string, req api.CompareRequest) (*api.CompareResponse, error) {
+	if req.Baseline == "" {
+		return nil, fmt.Errorf("%w baseline: a run ID is required", api.ErrInvalid)
+	}
+	for _, threshold := range []*float64{req.MaxThroughputDrop, req.MaxLatencyIncrease} {
+		if threshold != nil && *threshold < 0 {
+			return nil, fmt.Errorf("%w threshold: must not be negative, got %g", api.ErrInvalid, *threshold)
+		}
+	}
+
+	m.mu.RLock()
+	mt, exists := m.targets[name]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, errTargetNotFound(name)
+	}
+	baseline, baselineFound := findHistoryEntry(mt.history, req.Baseline)
+	var current historyEntry
+	currentFound := false
+	if req.Current == "" {
+		if n := len(mt.history); n > 0 {
+			current, currentFound = mt.history[n-1], true
+		}
+	} else {
+		current, currentFound = findHistoryEntry(mt.history, req.Current)
+	}
+	defaultThreshold := mt.target.GetRegressionThreshold(m.cfg.Defaults)
+	m.mu.RUnlock()
+
+	if !baselineFound {
+		return nil, fmt.Errorf("run %q in the history of target %q %w", req.Baseline, name, api.ErrNotFound)
+	}
+	if !currentFound {
+		if req.Current == "" {
+			return nil, fmt.Errorf("runs of target %q %w", name, api.ErrNotFound)
+		}
+		return nil, fmt.Errorf("run %q in the history of target %q %w", req.Current, name, api.ErrNotFound)
+	}
+
+	if defaultThreshold <= 0 {
+		defaultThreshold = DefaultCompareThreshold
+	}
+	resp := &api.CompareResponse{
+		Name:                      name,
+		Baseline:                  api.ComparedRun{RunID: baseline.runID, At: baseline.at},
+		Current:                   api.ComparedRun{RunID: current.runID, At: current.at},
+		MaxThroughputDropPercent:  defaultThreshold,
+		MaxLatencyIncreasePercent: defaultThreshold,
+		Pass:                      true,
+	}
+	if req.MaxThroughputDrop != nil {
+		resp.MaxThroughputDropPercent = *req.MaxThroughputDrop
+	}
+	if req.MaxLatencyIncrease != nil {
+		resp.MaxLatencyIncreasePercent = *req.MaxLatencyIncrease
+	}
+
+	add := func(metric string, b, c float64, higherIsBetter bool) {
+		cmp := api.MetricComparison{Metric: metric, Baseline: b, Current: c, HigherIsBetter: higherIsBetter, Pass: true}
+		if b != 0 {
+			delta := (c - b) / b * 100
+			cmp.DeltaPercent = &delta
+			if higherIsBetter {
+				cmp.Pass = -delta <= resp.MaxThroughputDropPercent
+			} else {
+				cmp.Pass = delta <= resp.MaxLatencyIncreasePercent
+			}
+		}
+		resp.Metrics = append(resp.Metrics, cmp)
+		resp.Pass = resp.Pass && cmp.Pass
+	}
+
+	b, c := baseline.results, current.results
+	add("output_tokens_per_second", b.OutputTokensPerSec, c.OutputTokensPerSec, true)
+	add("requests_per_second", b.RequestsPerSec, c.RequestsPerSec, true)
+	for _, latency := range []struct {
+		metric   string
+		baseline *api.PercentileSummary
+		current  *api.PercentileSummary
+	}{
+		{"ttft_seconds", runPercentiles(b.TTFTValues, nil), runPercentiles(c.TTFTValues, nil)},
+		{"itl_seconds", runPercentiles(b.ITLValues, nil), runPercentiles(c.ITLValues, nil)},
+		{"e2e_latency_seconds", runPercentiles(b.E2EValues, b.E2EStats), runPercentiles(c.E2EValues, c.E2EStats)},
+	} {
+		// A percentile only compares if both runs measured it
+		if latency.baseline == nil || latency.current == nil {
+			continue
+		}
+		add(latency.metric+"_p50", latency.baseline.P50, latency.current.P50, false)
+		add(latency.metric+"_p95", latency.baseline.P95, latency.current.P95, false)
+		add(latency.metric+"_p99", latency.baseline.P99, latency.current.P99, false)
+	}
+
+	return resp, nil
+}
+
+// findHistoryEntry returns the entry of history with the given run ID
+func findHistoryEntry(history []historyEntry, runID string) (historyEntry, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].runID == runID {
+			return history[i], true
+		}
+	}
+	return historyEntry{}, false
+}
+
+// runPercentiles returns the percentiles of a run's latency values, falling
+// back to guidellm's distribution stats when there are no individual values
+// (nil if neither is available). values is left unsorted.
+func runPercentiles(values []float64, stats *parser.DistributionSummary) *api.PercentileSummary {
+	if len(values) == 0 {
+		if stats == nil || stats.Count == 0 {
+			return nil
+		}
+		return &api.PercentileSummary{
+			Samples: stats.Count,
+			P50:     stats.Percentiles.P50,
+			P90:     stats.Percentiles.P90,
+			P95:     stats.Percentiles.P95,
+			P99:     stats.Percentiles.P99,
+		}
+	}
+	return summarizePercentiles(slices.Clone(values))
+}
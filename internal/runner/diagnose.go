@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/discovery"
+)
+
+// diagnosticPrompt is the prompt of the test completion made by
+// diagnoseTarget
+const diagnosticPrompt = "Hello"
+
+// diagnoseTarget requests the target's /v1/models and a one-token test
+// completion after a zero-request run, logging the status and body of each
+// answer so the cause (a 401, an unknown model, a wrong path) is visible
+// without reproducing the run by hand
+func (r *Runner) diagnoseTarget(ctx context.Context, target config.Target, apiKey string, logger *slog.Logger) {
+	client := discovery.NewClientWithTimeout(logger, target.GetProbeTimeout(r.cfg.Defaults))
+
+	if endpoint, err := discovery.ModelsEndpoint(target.URL); err != nil {
+		logger.Warn("cannot derive models endpoint for diagnosis", "error", err)
+	} else {
+		logDiagnosis(logger, "models", endpoint, client.Diagnose(ctx, http.MethodGet, endpoint, apiKey, nil), apiKey)
+	}
+
+	requestType := target.GetRequestType(r.cfg.Defaults)
+	endpoint, err := discovery.CompletionsEndpoint(target.URL, requestType)
+	if err != nil {
+		logger.Warn("cannot derive completions endpoint for diagnosis", "error", err)
+		return
+	}
+	body, _ := json.Marshal(testCompletion(target.Model, requestType))
+	logDiagnosis(logger, "completion", endpoint, client.Diagnose(ctx, http.MethodPost, endpoint, apiKey, body), apiKey)
+}
+
+// testCompletion returns the smallest completion request of the given type
+// for model
+func testCompletion(model, requestType string) map[string]any {
+	req := map[string]any{"model": model, "max_tokens": 1}
+	if requestType == "text_completions" {
+		req["prompt"] = diagnosticPrompt
+	} else {
+		req["messages"] = []map[string]string{{"role": "user", "content": diagnosticPrompt}}
+	}
+	return req
+}
+
+// logDiagnosis logs the answer to one diagnostic request, with the API key
+// redacted should the server echo it back
+func logDiagnosis(logger *slog.Logger, check, endpoint string, d discovery.Diagnosis, apiKey string) {
+	if d.Err != nil {
+		logger.Warn("diagnosis: endpoint did not answer",
+			"check", check,
+			"endpoint", endpoint,
+			"error", redactString(d.Err.Error(), apiKey))
+		return
+	}
+	level := slog.LevelInfo
+	if d.Status >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	logger.Log(context.Background(), level, "diagnosis: endpoint answered",
+		"check", check,
+		"endpoint", endpoint,
+		"status", d.Status,
+		"body", redactString(d.Body, apiKey))
+}
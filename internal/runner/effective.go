@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// secretKeys are the config keys whose values GetConfig redacts
+var secretKeys = map[string]bool{
+	"api_key":           true,
+	"secret":            true,
+	"access_key_id":     true,
+	"secret_access_key": true,
+}
+
+// GetConfig returns the runner's configuration as loaded, with defaults
+// applied and environment variables expanded, keyed as in the config file.
+// Secrets are redacted, as are bearer tokens anywhere in it (e.g. in
+// backend_kwargs headers). Targets added at runtime aren't part of it.
+func (m *DefaultTargetManager) GetConfig() (map[string]any, error) {
+	data, err := yaml.Marshal(m.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	var effective map[string]any
+	if err := yaml.Unmarshal(data, &effective); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	return redactConfig(effective).(map[string]any), nil
+}
+
+// redactConfig masks the secrets in a decoded config value, returning it
+func redactConfig(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && secretKeys[key] && s != "" {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactConfig(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactConfig(value)
+		}
+	case string:
+		return redactString(v)
+	}
+	return v
+}
+
+// GetTargetConfig returns the settings a target's scheduled runs use, with
+// the defaults and any active override resolved
+func (m *DefaultTargetManager) GetTargetConfig(name string) (*api.TargetConfigResponse, error) {
+	m.mu.RLock()
+	mt, exists := m.targets[name]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, errTargetNotFound(name)
+	}
+	target := mt.effectiveTarget(time.Now())
+	environment := mt.environment
+	m.mu.RUnlock()
+
+	defaults := m.cfg.Defaults
+	resp := &api.TargetConfigResponse{
+		Name:        target.Name,
+		Environment: environment,
+		URL:         target.URL,
+		Model:       target.Model,
+		Profile:     target.GetProfile(defaults),
+		MaxSeconds:  target.GetMaxSeconds(defaults),
+		RequestType: target.GetRequestType(defaults),
+		Interval:    int(m.cfg.GetInterval() / time.Second),
+		RunTimeout:  int(target.GetRunTimeout(defaults) / time.Second),
+		Stream:      target.GetStream(),
+		DataSpec:    target.GetDataSpec(defaults),
+		Processor:   target.GetProcessor(defaults),
+		WarmupRuns:  target.GetWarmupRuns(defaults),
+		MaxRuns:     target.MaxRuns,
+		AutoStart:   target.GetAutoStart(),
+	}
+	if config.ProfileUsesRate(resp.Profile) {
+		rate := target.GetRate(defaults)
+		resp.Rate = &rate
+	}
+	if m.runner != nil {
+		resp.Command = m.runner.redactedCommand(target)
+	}
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+func TestGetConfigRedactsSecrets(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Environments = map[string]config.Environment{
+		"prod": {Targets: []config.Target{{
+			Name:   "llama",
+			URL:    "http://localhost:8000",
+			Model:  "llama-3",
+			APIKey: "sk-target-12345",
+		}}},
+	}
+	manager.cfg.Webhooks.Secret = "webhook-secret"
+	manager.cfg.ResultsS3.AccessKeyID = "AKIAEXAMPLE"
+	manager.cfg.ResultsS3.SecretAccessKey = "s3-secret"
+
+	cfg, err := manager.GetConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	target := cfg["environments"].(map[string]any)["prod"].(map[string]any)["targets"].([]any)[0].(map[string]any)
+	if target["api_key"] != redactedValue {
+		t.Errorf("expected target api_key to be redacted, got %v", target["api_key"])
+	}
+	if target["model"] != "llama-3" {
+		t.Errorf("expected model llama-3, got %v", target["model"])
+	}
+	if secret := cfg["webhooks"].(map[string]any)["secret"]; secret != redactedValue {
+		t.Errorf("expected webhook secret to be redacted, got %v", secret)
+	}
+	s3 := cfg["results_s3"].(map[string]any)
+	if s3["access_key_id"] != redactedValue || s3["secret_access_key"] != redactedValue {
+		t.Errorf("expected S3 credentials to be redacted, got %v and %v", s3["access_key_id"], s3["secret_access_key"])
+	}
+	if profile := cfg["defaults"].(map[string]any)["profile"]; profile != "constant" {
+		t.Errorf("expected default profile constant, got %v", profile)
+	}
+	if manager.cfg.Webhooks.Secret != "webhook-secret" {
+		t.Error("expected GetConfig to leave the loaded config unchanged")
+	}
+}
+
+func TestGetTargetConfig(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = "guidellm"
+	manager.SetRunner(New(manager.cfg, manager.logger))
+
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:   "test-target",
+		URL:    "http://localhost:8000",
+		Model:  "test-model",
+		APIKey: "sk-target-12345",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := manager.GetTargetConfig("test-target")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Profile != "constant" || got.MaxSeconds != 60 || got.RequestType != "text_completions" || got.Interval != 300 {
+		t.Errorf("expected the defaults to be resolved, got %+v", got)
+	}
+	if got.Rate == nil || *got.Rate != 1.0 {
+		t.Errorf("expected rate 1.0, got %v", got.Rate)
+	}
+	if got.Environment != "dynamic" {
+		t.Errorf("expected environment dynamic, got %q", got.Environment)
+	}
+	if len(got.Command) == 0 || got.Command[0] != "guidellm" {
+		t.Errorf("expected the guidellm command, got %v", got.Command)
+	}
+	if !slices.Contains(got.Command, "--rate") {
+		t.Errorf("expected --rate in the command, got %v", got.Command)
+	}
+	if strings.Contains(strings.Join(got.Command, " "), "sk-target-12345") {
+		t.Errorf("expected the API key to be redacted, got %v", got.Command)
+	}
+
+	if _, err := manager.GetTargetConfig("missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
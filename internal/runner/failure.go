@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FailureCategory classifies why a benchmark run failed. Categories are
+// used as the reason label on the failed-runs metric, so the set is small
+// and fixed.
+type FailureCategory string
+
+const (
+	// The run never got going or never produced usable output
+	FailureSpawn   FailureCategory = "spawn_error"
+	FailureTimeout FailureCategory = "timeout"
+	FailureParse   FailureCategory = "parse_error"
+
+	// guidellm ran but the benchmark itself failed
+	FailureZeroRequests FailureCategory = "zero_requests"
+	FailureAllFailed    FailureCategory = "all_failed"
+
+	// guidellm exited non-zero, refined by known signatures in its output
+	FailureConnectionRefused FailureCategory = "connection_refused"
+	FailureUnauthorized      FailureCategory = "unauthorized"
+	FailureModelNotFound     FailureCategory = "model_not_found"
+	FailureServerOOM         FailureCategory = "server_oom"
+	FailureNonzeroExit       FailureCategory = "nonzero_exit"
+)
+
+// Stages of a run that a failure can occur in, for coarse triage
+const (
+	StageSetup = "setup" // preparing or spawning guidellm
+	StageExec  = "exec"  // guidellm ran and failed or timed out
+	StageParse = "parse" // guidellm's output couldn't be read or parsed
+	StageEmpty = "empty" // the benchmark completed without a successful request
+)
+
+// Stage returns the stage of a run the failure category belongs to
+func (c FailureCategory) Stage() string {
+	switch c {
+	case FailureSpawn:
+		return StageSetup
+	case FailureParse:
+		return StageParse
+	case FailureZeroRequests, FailureAllFailed:
+		return StageEmpty
+	default:
+		return StageExec
+	}
+}
+
+// RunError is a failed guidellm run, categorized from its output
+type RunError struct {
+	Category FailureCategory
+	Err      error
+	Detail   string // last line of guidellm's output, if it printed any
+	Stderr   string // last lines of guidellm's stderr, if it printed any
+	Stdout   string // last lines of guidellm's stdout, if it printed any
+}
+
+func (e *RunError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("guidellm run failed (%s): %v: %s", e.Category, e.Err, e.Detail)
+	}
+	return fmt.Sprintf("guidellm run failed (%s): %v", e.Category, e.Err)
+}
+
+func (e *RunError) Unwrap() error {
+	return e.Err
+}
+
+// Report returns the error followed by the tails of guidellm's stderr and
+// stdout, to tell its error output from its progress output
+func (e *RunError) Report() string {
+	var b strings.Builder
+	b.WriteString(e.Error())
+	if e.Stderr != "" {
+		b.WriteString("\nstderr:\n" + e.Stderr)
+	}
+	if e.Stdout != "" {
+		b.WriteString("\nstdout:\n" + e.Stdout)
+	}
+	return b.String()
+}
+
+// errorReport describes a failed run, with guidellm's output if it ran
+func errorReport(err error) string {
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		return runErr.Report()
+	}
+	return err.Error()
+}
+
+// failureSignatures maps lowercase substrings of guidellm output to failure
+// categories, checked in order. Server-side errors come first because they
+// are often reported wrapped in a connection or HTTP error.
+var failureSignatures = []struct {
+	substr   string
+	category FailureCategory
+}{
+	{"cuda out of memory", FailureServerOOM},
+	{"outofmemoryerror", FailureServerOOM},
+	{"401 unauthorized", FailureUnauthorized},
+	{"status code 401", FailureUnauthorized},
+	{"invalid api key", FailureUnauthorized},
+	{"incorrect api key", FailureUnauthorized},
+	{"model not found", FailureModelNotFound},
+	{"notfounderror", FailureModelNotFound},
+	{"does not exist", FailureModelNotFound},
+	{"connection refused", FailureConnectionRefused},
+	{"connecterror", FailureConnectionRefused},
+	{"failed to establish a new connection", FailureConnectionRefused},
+}
+
+// classifyFailure returns the category of the first known failure signature
+// found in a run's output, or FailureNonzeroExit
+func classifyFailure(output string) FailureCategory {
+	lower := strings.ToLower(output)
+	for _, sig := range failureSignatures {
+		if strings.Contains(lower, sig.substr) {
+			return sig.category
+		}
+	}
+	return FailureNonzeroExit
+}
+
+// maxDetailLength bounds the output line kept on a RunError
+const maxDetailLength = 200
+
+// lastLine returns the last non-empty line of output, truncated to
+// maxDetailLength. Python tracebacks end with the exception, which is
+// usually the most telling part.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > maxDetailLength {
+		line = line[:maxDetailLength] + "..."
+	}
+	return line
+}
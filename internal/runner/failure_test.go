@@ -0,0 +1,224 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected FailureCategory
+	}{
+		{"httpx.ConnectError: [Errno 111] Connection refused", FailureConnectionRefused},
+		{"HTTPStatusError: Client error '401 Unauthorized' for url", FailureUnauthorized},
+		{"openai.AuthenticationError: Incorrect API key provided", FailureUnauthorized},
+		{"The model `llama-70b` does not exist", FailureModelNotFound},
+		{"Error: model not found: llama", FailureModelNotFound},
+		{"500 Internal Server Error: CUDA out of memory. Tried to allocate 2.00 GiB", FailureServerOOM},
+		{"Traceback (most recent call last): KeyError: 'benchmarks'", FailureNonzeroExit},
+		{"", FailureNonzeroExit},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.expected), func(t *testing.T) {
+			if got := classifyFailure(tt.output); got != tt.expected {
+				t.Errorf("classifyFailure(%q) = %s, want %s", tt.output, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRunFailureCountedByReason verifies that each way a guidellm run can
+// fail is counted under its reason
+func TestRunFailureCountedByReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		binary   string
+		expected FailureCategory
+	}{
+		{"missing binary", filepath.Join(t.TempDir(), "no-such-guidellm"), FailureSpawn},
+		{"recognized output", writeFakeGuidellm(t, "echo 'httpx.ConnectError: [Errno 111] Connection refused' >&2; exit 1"), FailureConnectionRefused},
+		{"unrecognized output", writeFakeGuidellm(t, "echo 'something broke' >&2; exit 3"), FailureNonzeroExit},
+		{"no results written", writeFakeGuidellm(t, "exit 0"), FailureParse},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GuideLLMBinary: tt.binary,
+				Defaults: config.Defaults{
+					Profile:     "constant",
+					Rate:        1,
+					MaxSeconds:  1,
+					DataSpec:    "prompt_tokens=10,output_tokens=10",
+					RequestType: "text_completions",
+				},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			runner := New(cfg, logger)
+
+			target := config.Target{Name: fmt.Sprintf("failing-target-%d", i), URL: "http://test.local/v1", Model: "test-model"}
+			output, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+			if output != nil {
+				t.Fatalf("expected failed run, got %+v", output)
+			}
+			var runErr *RunError
+			if !errors.As(err, &runErr) || runErr.Category != tt.expected {
+				t.Errorf("expected RunError with category %s, got %v", tt.expected, err)
+			}
+
+			labels := metrics.Labels("test", target.Name, target.Model)
+			failed := metrics.BenchmarkRunsFailed.With(metrics.FailureLabels(labels, string(tt.expected)))
+			if got := testutil.ToFloat64(failed); got != 1 {
+				t.Errorf("expected 1 %s failure, got %v", tt.expected, got)
+			}
+			var wantSpawn float64
+			if tt.expected == FailureSpawn {
+				wantSpawn = 1
+			}
+			if got := testutil.ToFloat64(metrics.SpawnErrors.With(labels)); got != wantSpawn {
+				t.Errorf("expected %v spawn errors, got %v", wantSpawn, got)
+			}
+		})
+	}
+}
+
+// TestZeroRequestRunsCounted verifies that runs completing without any
+// requests are counted separately from other failures
+func TestZeroRequestRunsCounted(t *testing.T) {
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `while [ $# -gt 0 ]; do
+  if [ "$1" = "--output-dir" ]; then echo '{"benchmarks": []}' > "$2/benchmarks.json"; fi
+  shift
+done`),
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "zero-request-target", URL: "http://test.local/v1", Model: "test-model"}
+
+	for i := 0; i < 2; i++ {
+		_, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+		var runErr *RunError
+		if !errors.As(err, &runErr) || runErr.Category != FailureZeroRequests {
+			t.Fatalf("expected a zero requests failure, got %v", err)
+		}
+	}
+
+	labels := metrics.Labels("test", target.Name, target.Model)
+	if got := testutil.ToFloat64(metrics.ZeroRequestRuns.With(labels)); got != 2 {
+		t.Errorf("expected 2 zero request runs, got %v", got)
+	}
+}
+
+func TestRunErrorUnwraps(t *testing.T) {
+	cause := errors.New("exit status 1")
+	err := error(&RunError{Category: FailureUnauthorized, Err: cause})
+
+	var runErr *RunError
+	if !errors.As(err, &runErr) || runErr.Category != FailureUnauthorized {
+		t.Errorf("expected RunError with unauthorized category, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected RunError to unwrap to its cause")
+	}
+}
+
+// TestRunErrorSeparatesOutput verifies that guidellm's stderr and stdout
+// are kept apart on a failed run, with the error taken from stderr even
+// when stdout is noisier
+func TestRunErrorSeparatesOutput(t *testing.T) {
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `i=0
+while [ $i -lt 5000 ]; do echo "progress $i: Connection refused by nothing"; i=$((i+1)); done
+echo 'openai.AuthenticationError: Incorrect API key provided' >&2
+echo 'progress done'
+exit 1`),
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runner := New(cfg, logger)
+
+	target := config.Target{Name: "noisy-target", URL: "http://test.local/v1", Model: "test-model"}
+	_, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	var runErr *RunError
+	if !errors.As(err, &runErr) {
+		t.Fatalf("expected a RunError, got %v", err)
+	}
+	if runErr.Category != FailureUnauthorized {
+		t.Errorf("expected the stderr error to classify the run, got %s", runErr.Category)
+	}
+	if runErr.Detail != "openai.AuthenticationError: Incorrect API key provided" {
+		t.Errorf("expected detail from stderr, got %q", runErr.Detail)
+	}
+	if runErr.Stderr != runErr.Detail {
+		t.Errorf("expected stderr tail %q, got %q", runErr.Detail, runErr.Stderr)
+	}
+	if lines := strings.Split(runErr.Stdout, "\n"); len(lines) != maxTailLines || lines[len(lines)-1] != "progress done" {
+		t.Errorf("expected the last %d stdout lines, got %q", maxTailLines, runErr.Stdout)
+	}
+	report := runErr.Report()
+	if !strings.Contains(report, "stderr:\nopenai.AuthenticationError") || !strings.Contains(report, "stdout:\n") {
+		t.Errorf("expected report to include both tails, got %q", report)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	buf := newTailBuffer(16)
+	fmt.Fprint(buf, "first line\n")
+	if got := buf.String(); got != "first line\n" {
+		t.Errorf("expected output kept whole under the limit, got %q", got)
+	}
+	fmt.Fprint(buf, "second\nthird\n")
+	if got := buf.String(); got != "second\nthird\n" {
+		t.Errorf("expected only whole lines of the last 16 bytes, got %q", got)
+	}
+	fmt.Fprint(buf, strings.Repeat("x", 40)+"\nend\n")
+	if len(buf.buf) != 16 || buf.String() != "end\n" {
+		t.Errorf("expected a write over the limit to keep its tail, got %q", buf.buf)
+	}
+}
+
+// TestLineWriter verifies that streams sharing a log are written to it a
+// whole line at a time
+func TestLineWriter(t *testing.T) {
+	var log strings.Builder
+	var mu sync.Mutex
+	stdout, stderr := newLineWriter(&log, &mu), newLineWriter(&log, &mu)
+	fmt.Fprint(stdout, "progress: Bea")
+	fmt.Fprint(stderr, "RuntimeError: failed\n")
+	fmt.Fprint(stdout, "rer token\nprogress: do")
+	if got := log.String(); got != "RuntimeError: failed\nprogress: Bearer token\n" {
+		t.Errorf("expected whole lines only, got %q", got)
+	}
+	if err := stdout.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := log.String(); !strings.HasSuffix(got, "\nprogress: do") {
+		t.Errorf("expected the partial line flushed, got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+)
+
+// groupMembers returns the targets currently selected by a configured
+// group, sorted by name. Must be called with m.mu held.
+func (m *DefaultTargetManager) groupMembers(name string) ([]*managedTarget, error) {
+	group, exists := m.cfg.Groups[name]
+	if !exists {
+		return nil, fmt.Errorf("group %q %w", name, api.ErrNotFound)
+	}
+	var members []*managedTarget
+	for _, mt := range m.targets {
+		if group.Selects(&mt.target) {
+			members = append(members, mt)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].target.Name < members[j].target.Name
+	})
+	return members, nil
+}
+
+// GroupAction starts or stops every target currently in a group, as
+// BulkAction does for a list of targets
+func (m *DefaultTargetManager) GroupAction(ctx context.Context, group string, action string) (*api.BulkActionResponse, error) {
+	m.mu.RLock()
+	members, err := m.groupMembers(group)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty list would mean every target to BulkAction
+	if len(members) == 0 {
+		if action != api.BulkActionStart && action != api.BulkActionStop {
+			return nil, fmt.Errorf("unknown action %q (want %s or %s)", action, api.BulkActionStart, api.BulkActionStop)
+		}
+		return &api.BulkActionResponse{Action: action, Results: []api.BulkActionResult{}}, nil
+	}
+	names := make([]string, len(members))
+	for i, mt := range members {
+		names[i] = mt.target.Name
+	}
+	return m.BulkAction(ctx, action, names)
+}
+
+// GetGroupSummary returns the latest results of every target currently in
+// a group, and their combined throughput
+func (m *DefaultTargetManager) GetGroupSummary(group string) (*api.GroupSummaryResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	members, err := m.groupMembers(group)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.GroupSummaryResponse{
+		Name:    group,
+		Targets: make([]api.TargetSummary, 0, len(members)),
+	}
+	for _, mt := range members {
+		resp.Targets = append(resp.Targets, m.targetSummary(mt))
+	}
+	resp.OutputTokensPerSec, resp.ReportingTargets = m.freshThroughput(time.Now(), members)
+	return resp, nil
+}
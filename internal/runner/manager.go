@@ -2,8 +2,11 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,12 +15,14 @@ import (
 	"github.com/yourorg/guidellm-runner/internal/discovery"
 	"github.com/yourorg/guidellm-runner/internal/metrics"
 	"github.com/yourorg/guidellm-runner/internal/parser"
+	"github.com/yourorg/guidellm-runner/internal/results"
+	"github.com/yourorg/guidellm-runner/internal/webhook"
 )
 
 // TargetManager manages runtime target lifecycle
 type TargetManager interface {
-	// AddTarget adds a new target at runtime
-	AddTarget(ctx context.Context, req api.AddTargetRequest) error
+	// AddTarget adds a new target at runtime and returns it as registered
+	AddTarget(ctx context.Context, req api.AddTargetRequest) (*api.TargetResponse, error)
 
 	// RemoveTarget removes a target by name
 	RemoveTarget(name string) error
@@ -28,11 +33,37 @@ type TargetManager interface {
 	// StopTarget stops benchmarking for a target
 	StopTarget(name string) error
 
-	// TriggerRun triggers an immediate benchmark run for a target
-	TriggerRun(ctx context.Context, name string, runID string) (*parser.ParsedResults, error)
+	// TriggerRun triggers an immediate benchmark run for a target, which is
+	// cancelled if ctx is. Overrides, if set, apply to this run only.
+	TriggerRun(ctx context.Context, name string, runID string, overrides *api.RunOverrides) (*parser.ParsedResults, error)
 
-	// ListTargets returns all registered targets
-	ListTargets() []api.TargetResponse
+	// StartRun triggers a run for a target in the background, returning
+	// it as registered for polling with GetRun
+	StartRun(ctx context.Context, name string, runID string, overrides *api.RunOverrides) (*api.RunResponse, error)
+
+	// GetRun returns a recent run by ID
+	GetRun(runID string) (*api.RunResponse, error)
+
+	// ListRuns returns recent runs, newest first, optionally filtered by
+	// target and status
+	ListRuns(target string, status api.RunStatus) []api.RunResponse
+
+	// CompareRuns compares two runs in a target's results history
+	CompareRuns(name string, req api.CompareRequest) (*api.CompareResponse, error)
+
+	// SetBaseline pins a run in a target's results history, the latest if
+	// runID is "", as the target's baseline
+	SetBaseline(name string, runID string) (*api.BaselineResponse, error)
+
+	// GetBaseline returns a target's pinned baseline
+	GetBaseline(name string) (*api.BaselineResponse, error)
+
+	// ClearBaseline unpins a target's baseline
+	ClearBaseline(name string) error
+
+	// ListTargets returns the page of targets matching filter, sorted by
+	// name, and the total number that matched
+	ListTargets(filter api.TargetFilter) ([]api.TargetResponse, int)
 
 	// GetTarget returns a single target by name
 	GetTarget(name string) (*api.TargetResponse, bool)
@@ -40,8 +71,72 @@ type TargetManager interface {
 	// GetStatus returns the overall runner status
 	GetStatus() api.StatusResponse
 
+	// GetVersion returns the runner's build information and guidellm version
+	GetVersion() api.VersionResponse
+
+	// GetSummary returns every target's latest results, for rendering the
+	// whole fleet in one call
+	GetSummary() api.SummaryResponse
+
+	// GetMetricsSnapshot returns the values of the main per-target gauges,
+	// derived from the manager's state, for debugging without Prometheus
+	GetMetricsSnapshot() api.MetricsSnapshotResponse
+
+	// GetConfig returns the effective configuration, defaults applied and
+	// secrets redacted
+	GetConfig() (map[string]any, error)
+
+	// GetTargetConfig returns the resolved settings of a target's runs
+	GetTargetConfig(name string) (*api.TargetConfigResponse, error)
+
+	// GroupAction starts or stops every target currently in a group
+	GroupAction(ctx context.Context, group string, action string) (*api.BulkActionResponse, error)
+
+	// GetGroupSummary returns the latest results of a group's targets
+	GetGroupSummary(group string) (*api.GroupSummaryResponse, error)
+
 	// GetLatestResults returns the latest benchmark results for a target
-	GetLatestResults(name string) (*parser.ParsedResults, bool)
+	// (nil results if no run has completed yet), along with whether a run
+	// is currently in flight
+	GetLatestResults(name string) (*api.ResultsResponse, error)
+
+	// GetRawResults returns the archived raw guidellm JSON of the target's
+	// latest run (nil if nothing has been archived)
+	GetRawResults(name string) ([]byte, error)
+
+	// SubscribeResults registers for the target's results as each run
+	// completes. The returned func unsubscribes; the channel is closed if the
+	// target is removed.
+	SubscribeResults(name string) (<-chan *parser.ParsedResults, func(), error)
+
+	// GetHistoryPercentiles merges latency percentiles across the target's
+	// runs that completed within window
+	GetHistoryPercentiles(name string, window time.Duration) (*api.HistoryPercentilesResponse, error)
+
+	// GetResultsHistory returns the target's runs that completed within
+	// window, oldest first
+	GetResultsHistory(name string, window time.Duration) ([]api.RunResults, error)
+
+	// GetFailures breaks down run failures within window across all
+	// targets, by stage, reason and target
+	GetFailures(window time.Duration) (*api.FailuresResponse, error)
+
+	// BulkAction starts or stops the named targets, or every target when
+	// names is empty, reporting the outcome for each
+	BulkAction(ctx context.Context, action string, names []string) (*api.BulkActionResponse, error)
+
+	// SetOverride temporarily overrides a target's settings for a duration
+	SetOverride(name string, req api.OverrideRequest) (*api.TargetResponse, error)
+
+	// ClearOverride removes a target's override before it expires
+	ClearOverride(name string) (*api.TargetResponse, error)
+
+	// PauseTarget pauses a target's scheduled runs, leaving other targets'
+	// schedules running
+	PauseTarget(name string) (*api.TargetResponse, error)
+
+	// ResumeTarget resumes a target's scheduled runs
+	ResumeTarget(name string) (*api.TargetResponse, error)
 
 	// PauseScheduler pauses scheduled benchmark runs
 	PauseScheduler() error
@@ -61,6 +156,91 @@ type managedTarget struct {
 	cancel      context.CancelFunc
 	lastRunAt   *time.Time
 	lastResults *parser.ParsedResults
+	lastRaw     []byte // raw guidellm JSON, only kept when archiving is enabled
+	lastError   string // most recent run failure, kept after later successes
+	lastErrorAt *time.Time
+	override    *api.TargetOverride
+
+	// consecutiveFailures is the current streak of failed runs, reset by a
+	// successful one
+	consecutiveFailures int
+
+	// runsInFlight counts scheduled and manual runs currently executing;
+	// runs lets removal wait for them before deleting the target's series
+	runsInFlight int
+	runs         sync.WaitGroup
+	removed      bool
+
+	// history holds the target's most recent successful runs, oldest first
+	history []historyEntry
+
+	// lastSweep holds the runs of the latest complete sweep, one per step
+	lastSweep []api.RunResponse
+
+	// nextRunAt is when the target's loop will next run it
+	nextRunAt *time.Time
+
+	// remainingRuns counts down the scheduled runs left before the target
+	// stops itself, for targets with max_runs. Reset on each start.
+	remainingRuns *int
+
+	// pausedAt is set while the target's own schedule is paused, which
+	// lasts until pauseUntil, or until resumed if that is nil. Other
+	// targets' schedules are unaffected. pauseReason is one of the
+	// api.PauseReason values.
+	pausedAt    *time.Time
+	pauseUntil  *time.Time
+	pauseReason string
+
+	// baseline is the pinned baseline, if any
+	baseline *api.BaselineResponse
+}
+
+// historyEntry is a completed run kept in a target's results history
+type historyEntry struct {
+	at      time.Time
+	runID   string
+	results *parser.ParsedResults
+}
+
+// maxHistoryEntries bounds each target's results history
+const maxHistoryEntries = 100
+
+// failureEvent is a failed run kept in the manager's failure log
+type failureEvent struct {
+	at          time.Time
+	target      string
+	environment string
+	category    FailureCategory
+	message     string
+}
+
+// maxFailureEvents bounds the failure log shared by all targets
+const maxFailureEvents = 1000
+
+// activeOverride returns the target's override if it hasn't expired yet
+func (mt *managedTarget) activeOverride(now time.Time) *api.TargetOverride {
+	if mt.override == nil || !now.Before(mt.override.ExpiresAt) {
+		return nil
+	}
+	return mt.override
+}
+
+// effectiveTarget returns the target config with any active override applied
+func (mt *managedTarget) effectiveTarget(now time.Time) config.Target {
+	target := mt.target
+	if o := mt.activeOverride(now); o != nil {
+		if o.Rate != nil {
+			target.Rate = o.Rate
+		}
+		if o.MaxSeconds != nil {
+			target.MaxSeconds = o.MaxSeconds
+		}
+		if o.Profile != "" {
+			target.Profile = o.Profile
+		}
+	}
+	return target
 }
 
 // DefaultTargetManager is the default implementation of TargetManager
@@ -74,7 +254,50 @@ type DefaultTargetManager struct {
 	wg                sync.WaitGroup
 	schedulerPaused   bool
 	schedulerPausedAt *time.Time
-	autoResumeTimer   *time.Timer
+
+	// Results subscribers by target name, guarded by mu
+	subscribers map[string]map[chan *parser.ParsedResults]struct{}
+
+	// failures logs recent run failures across all targets, oldest first,
+	// guarded by mu
+	failures []failureEvent
+
+	// Retry policy for StartAllConfigured, and the start function it retries
+	startRetryAttempts int
+	startRetryBackoff  time.Duration
+	startFn            func(ctx context.Context, name string) error
+
+	// startStagger spaces out the starts of StartAllConfigured
+	startStagger time.Duration
+
+	// notifier, if set, receives an event for each completed run
+	notifier *webhook.Notifier
+
+	// sinks store each completed run's results, e.g. on disk or in S3
+	sinks []results.ResultSink
+
+	// pusher, if set, pushes each target's metrics to a Pushgateway after
+	// every run
+	pusher *metrics.Pusher
+
+	// buildInfo is the runner's version, commit and build date
+	buildInfo api.VersionResponse
+
+	// runs are recent runs by ID, guarded by mu
+	runs runRegistry
+
+	// stopCtx is cancelled by StopAll, cancelling manual runs in flight
+	stopCtx    context.Context
+	cancelStop context.CancelFunc
+
+	// draining is closed by Drain, stopping target loops from scheduling
+	// further runs while those in flight finish
+	draining  chan struct{}
+	drainOnce sync.Once
+
+	// once is set by RunOnce: targets then start with a single scheduled
+	// run, made straight away. Guarded by mu.
+	once bool
 }
 
 // NewTargetManager creates a new DefaultTargetManager
@@ -82,12 +305,22 @@ func NewTargetManager(cfg *config.Config, logger *slog.Logger) *DefaultTargetMan
 	// Initialize metric to 0 (running)
 	metrics.SchedulerPaused.Set(0)
 
-	return &DefaultTargetManager{
-		targets:   make(map[string]*managedTarget),
-		cfg:       cfg,
-		logger:    logger,
-		startTime: time.Now(),
+	m := &DefaultTargetManager{
+		targets:            make(map[string]*managedTarget),
+		subscribers:        make(map[string]map[chan *parser.ParsedResults]struct{}),
+		cfg:                cfg,
+		logger:             logger,
+		startTime:          time.Now(),
+		startRetryAttempts: max(cfg.Startup.RetryAttempts, 0),
+		startRetryBackoff:  time.Duration(cfg.Startup.RetryBackoff) * time.Second,
+		startStagger:       time.Duration(cfg.Startup.Stagger * float64(time.Second)),
+		runs:               newRunRegistry(cfg.API.RunRetention),
 	}
+	m.startFn = m.StartTarget
+	m.stopCtx, m.cancelStop = context.WithCancel(context.Background())
+	m.draining = make(chan struct{})
+	metrics.SetFleetThroughput(m.currentFleetThroughput)
+	return m
 }
 
 // SetRunner sets the runner reference for running benchmarks
@@ -95,37 +328,75 @@ func (m *DefaultTargetManager) SetRunner(r *Runner) {
 	m.runner = r
 }
 
-// AddTarget adds a new target at runtime
-func (m *DefaultTargetManager) AddTarget(ctx context.Context, req api.AddTargetRequest) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// SetNotifier sets the webhook notifier told about each completed run
+func (m *DefaultTargetManager) SetNotifier(n *webhook.Notifier) {
+	m.notifier = n
+}
 
-	// Check for duplicate
-	if _, exists := m.targets[req.Name]; exists {
-		return fmt.Errorf("target %q already exists", req.Name)
-	}
+// SetResultSinks sets the sinks each completed run's results are stored to
+func (m *DefaultTargetManager) SetResultSinks(sinks ...results.ResultSink) {
+	m.sinks = sinks
+}
+
+// SetPusher sets the Pushgateway pusher the targets' metrics are pushed
+// with after each run
+func (m *DefaultTargetManager) SetPusher(p *metrics.Pusher) {
+	m.pusher = p
+}
+
+// SetBuildInfo sets the runner version, git commit and build date reported
+// by GetStatus and GetVersion
+func (m *DefaultTargetManager) SetBuildInfo(version, commit, buildDate string) {
+	m.buildInfo = api.VersionResponse{Version: version, GitCommit: commit, BuildDate: buildDate}
+}
 
-	// Validate required fields
-	if req.Name == "" {
-		return fmt.Errorf("name is required")
+// AddTarget adds a new target at runtime and returns it as registered
+func (m *DefaultTargetManager) AddTarget(ctx context.Context, req api.AddTargetRequest) (*api.TargetResponse, error) {
+	// Create config.Target from request
+	target := config.Target{
+		Name:         req.Name,
+		URL:          req.URL,
+		Model:        req.Model,
+		APIKey:       req.APIKey,
+		Profile:      req.Profile,
+		Rate:         req.Rate,
+		MaxSeconds:   req.MaxSeconds,
+		RequestType:  req.RequestType,
+		HealthPath:   req.HealthPath,
+		Stream:       req.Stream,
+		ProbeTimeout: req.ProbeTimeout,
+
+		ChatFormatter: req.ChatFormatter,
+		ExtraArgs:     req.ExtraArgs,
+		WarmupRuns:    req.WarmupRuns,
+		MaxRuns:       req.MaxRuns,
+		Processor:     req.Processor,
+		BackendKwargs: req.BackendKwargs,
+		Tags:          req.Tags,
 	}
-	if req.URL == "" {
-		return fmt.Errorf("url is required")
+
+	// Validate every field, so all the problems are reported at once
+	if invalid := m.cfg.ValidateTarget(target); len(invalid) > 0 {
+		fields := make(map[string]string, len(invalid))
+		for field, err := range invalid {
+			fields[field] = err.Error()
+		}
+		return nil, &api.ValidationError{Fields: fields}
 	}
-	if req.Model == "" {
-		return fmt.Errorf("model is required")
+
+	// Probe before taking the lock, as it may take a while
+	if m.cfg.API.ProbeTargets {
+		if err := m.probeTarget(ctx, target); err != nil {
+			return nil, fmt.Errorf("target url %q failed reachability check: %w", req.URL, err)
+		}
 	}
 
-	// Create config.Target from request
-	target := config.Target{
-		Name:        req.Name,
-		URL:         req.URL,
-		Model:       req.Model,
-		APIKey:      req.APIKey,
-		Profile:     req.Profile,
-		Rate:        req.Rate,
-		MaxSeconds:  req.MaxSeconds,
-		RequestType: req.RequestType,
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check for duplicate
+	if _, exists := m.targets[req.Name]; exists {
+		return nil, fmt.Errorf("target %q already exists", req.Name)
 	}
 
 	// Default environment to "dynamic" for runtime-added targets
@@ -133,20 +404,56 @@ func (m *DefaultTargetManager) AddTarget(ctx context.Context, req api.AddTargetR
 	if env == "" {
 		env = "dynamic"
 	}
+	if m.cfg.API.RestrictEnvironments && !m.cfg.HasEnvironment(env) {
+		return nil, fmt.Errorf("unknown environment %q: runtime targets are restricted to configured environments", env)
+	}
 
-	m.targets[req.Name] = &managedTarget{
+	mt := &managedTarget{
 		target:      target,
 		environment: env,
 		status:      api.TargetStatusStopped,
 	}
+	m.targets[req.Name] = mt
 
-	m.logger.Info("target added",
+	api.WithRequestID(ctx, m.logger).Info("target added",
 		"name", req.Name,
 		"url", req.URL,
 		"model", req.Model,
 		"environment", env)
 
-	return nil
+	resp := m.toTargetResponse(mt)
+	return &resp, nil
+}
+
+// deleteSeriesAfterRuns waits for a removed target's runs to finish, then
+// deletes its metric series
+func (m *DefaultTargetManager) deleteSeriesAfterRuns(mt *managedTarget) {
+	defer m.wg.Done()
+
+	mt.runs.Wait()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Leave the series alone if the target was re-added under the same name
+	// and environment in the meantime, as they now belong to it
+	if current, exists := m.targets[mt.target.Name]; exists && current.environment == mt.environment {
+		return
+	}
+	metrics.DeleteTarget(mt.environment, mt.target.Name)
+	m.logger.Debug("deleted metric series for removed target", "name", mt.target.Name)
+}
+
+// probeTarget checks that the target's endpoint answers on its health path
+// (/v1/models by default)
+func (m *DefaultTargetManager) probeTarget(ctx context.Context, target config.Target) error {
+	endpoint, err := discovery.HealthEndpoint(target.URL, target.GetHealthPath(m.cfg.Defaults))
+	if err != nil {
+		return err
+	}
+	apiKey, _ := resolveAPIKey(target)
+
+	return discovery.NewClientWithTimeout(m.logger, target.GetProbeTimeout(m.cfg.Defaults)).Probe(ctx, endpoint, apiKey)
 }
 
 // RemoveTarget removes a target by name
@@ -156,7 +463,7 @@ func (m *DefaultTargetManager) RemoveTarget(name string) error {
 
 	mt, exists := m.targets[name]
 	if !exists {
-		return fmt.Errorf("target %q not found", name)
+		return errTargetNotFound(name)
 	}
 
 	// Stop if running
@@ -165,6 +472,19 @@ func (m *DefaultTargetManager) RemoveTarget(name string) error {
 	}
 
 	delete(m.targets, name)
+	mt.removed = true
+
+	// Delete the target's metric series once in-flight runs have finished
+	// writing them, so they aren't resurrected by a run finishing late
+	m.wg.Add(1)
+	go m.deleteSeriesAfterRuns(mt)
+
+	// End any results streams for the target
+	for ch := range m.subscribers[name] {
+		close(ch)
+	}
+	delete(m.subscribers, name)
+
 	m.logger.Info("target removed", "name", name)
 	return nil
 }
@@ -175,12 +495,12 @@ func (m *DefaultTargetManager) StartTarget(ctx context.Context, name string) err
 	mt, exists := m.targets[name]
 	if !exists {
 		m.mu.Unlock()
-		return fmt.Errorf("target %q not found", name)
+		return errTargetNotFound(name)
 	}
 
 	if mt.status == api.TargetStatusRunning {
 		m.mu.Unlock()
-		return fmt.Errorf("target %q is already running", name)
+		return fmt.Errorf("target %q is %w", name, errAlreadyRunning)
 	}
 
 	// Create cancellable context for this target
@@ -189,16 +509,77 @@ func (m *DefaultTargetManager) StartTarget(ctx context.Context, name string) err
 	targetCtx, cancel := context.WithCancel(context.Background())
 	mt.cancel = cancel
 	mt.status = api.TargetStatusRunning
+	mt.remainingRuns = nil
+	if m.once {
+		one := 1
+		mt.remainingRuns = &one
+	} else if mt.target.MaxRuns != nil {
+		remaining := *mt.target.MaxRuns
+		mt.remainingRuns = &remaining
+	}
 	m.mu.Unlock()
 
 	// Start the benchmark loop in a goroutine
 	m.wg.Add(1)
-	go m.runTargetLoop(targetCtx, name)
+	go m.runTargetLoop(targetCtx, mt)
 
 	m.logger.Info("target started", "name", name)
 	return nil
 }
 
+// BulkAction starts or stops the named targets, or every target when names
+// is empty, reporting the outcome for each. A target already in the
+// requested state counts as a success; other per-target errors are reported
+// without aborting the rest of the batch.
+func (m *DefaultTargetManager) BulkAction(ctx context.Context, action string, names []string) (*api.BulkActionResponse, error) {
+	var apply func(name string) error
+	var done api.TargetStatus
+	var alreadyDone error
+	switch action {
+	case api.BulkActionStart:
+		apply = func(name string) error { return m.StartTarget(ctx, name) }
+		done, alreadyDone = api.TargetStatusRunning, errAlreadyRunning
+	case api.BulkActionStop:
+		apply = m.StopTarget
+		done, alreadyDone = api.TargetStatusStopped, errNotRunning
+	default:
+		return nil, fmt.Errorf("unknown action %q (want %s or %s)", action, api.BulkActionStart, api.BulkActionStop)
+	}
+
+	if len(names) == 0 {
+		m.mu.RLock()
+		for name := range m.targets {
+			names = append(names, name)
+		}
+		m.mu.RUnlock()
+		sort.Strings(names)
+	}
+
+	resp := &api.BulkActionResponse{Action: action, Results: make([]api.BulkActionResult, 0, len(names))}
+	for _, name := range names {
+		result := api.BulkActionResult{Name: name}
+		switch err := apply(name); {
+		case err == nil:
+			result.Status = done
+		case errors.Is(err, alreadyDone):
+			result.Status = done
+			result.Message = "already " + string(done)
+		default:
+			result.Error = err.Error()
+		}
+
+		if result.Error != "" {
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	m.logger.Info("bulk target action", "action", action, "succeeded", resp.Succeeded, "failed", resp.Failed)
+	return resp, nil
+}
+
 // StopTarget stops benchmarking for a target
 func (m *DefaultTargetManager) StopTarget(name string) error {
 	m.mu.Lock()
@@ -206,11 +587,11 @@ func (m *DefaultTargetManager) StopTarget(name string) error {
 
 	mt, exists := m.targets[name]
 	if !exists {
-		return fmt.Errorf("target %q not found", name)
+		return errTargetNotFound(name)
 	}
 
 	if mt.status != api.TargetStatusRunning {
-		return fmt.Errorf("target %q is not running", name)
+		return fmt.Errorf("target %q is %w", name, errNotRunning)
 	}
 
 	if mt.cancel != nil {
@@ -223,16 +604,44 @@ func (m *DefaultTargetManager) StopTarget(name string) error {
 	return nil
 }
 
-// ListTargets returns all registered targets
-func (m *DefaultTargetManager) ListTargets() []api.TargetResponse {
+// ListTargets returns the page of targets matching filter, sorted by name,
+// and the total number that matched
+func (m *DefaultTargetManager) ListTargets(filter api.TargetFilter) ([]api.TargetResponse, int) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	targets := make([]api.TargetResponse, 0, len(m.targets))
-	for _, mt := range m.targets {
+	query := strings.ToLower(filter.Query)
+	var matched []*managedTarget
+	for name, mt := range m.targets {
+		if filter.Environment != "" && mt.environment != filter.Environment {
+			continue
+		}
+		if filter.Status != "" && m.reportedStatus(mt) != filter.Status {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		if !mt.target.HasTags(filter.Tags) {
+			continue
+		}
+		matched = append(matched, mt)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].target.Name < matched[j].target.Name
+	})
+
+	total := len(matched)
+	page := matched[min(filter.Offset, total):]
+	if filter.Limit > 0 && filter.Limit < len(page) {
+		page = page[:filter.Limit]
+	}
+
+	targets := make([]api.TargetResponse, 0, len(page))
+	for _, mt := range page {
 		targets = append(targets, m.toTargetResponse(mt))
 	}
-	return targets
+	return targets, total
 }
 
 // GetTarget returns a single target by name
@@ -264,47 +673,227 @@ func (m *DefaultTargetManager) GetStatus() api.StatusResponse {
 		}
 	}
 
+	inFlight := 0
+	guidellmVersion := ""
+	if m.runner != nil {
+		inFlight = m.runner.InFlight()
+		guidellmVersion = m.runner.GuideLLMVersion()
+	}
+
 	return api.StatusResponse{
-		Running:       true,
-		TargetsCount:  len(m.targets),
-		ActiveCount:   activeCount,
-		StoppedCount:  stoppedCount,
-		UptimeSeconds: int64(time.Since(m.startTime).Seconds()),
+		Running:         true,
+		Version:         m.buildInfo.Version,
+		TargetsCount:    len(m.targets),
+		ActiveCount:     activeCount,
+		StoppedCount:    stoppedCount,
+		InFlightRuns:    inFlight,
+		UptimeSeconds:   int64(time.Since(m.startTime).Seconds()),
+		GuideLLMVersion: guidellmVersion,
+	}
+}
+
+// GetVersion returns the runner's build information and the detected
+// guidellm version
+func (m *DefaultTargetManager) GetVersion() api.VersionResponse {
+	info := m.buildInfo
+	if m.runner != nil {
+		info.GuideLLMVersion = m.runner.GuideLLMVersion()
+	}
+	return info
+}
+
+// GetSummary returns every target's latest throughput, latency and success
+// rate, sorted by name
+func (m *DefaultTargetManager) GetSummary() api.SummaryResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summaries := make([]api.TargetSummary, 0, len(m.targets))
+	for _, mt := range m.targets {
+		summaries = append(summaries, m.targetSummary(mt))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	fleet, fleetTargets := m.fleetThroughput(time.Now())
+	return api.SummaryResponse{
+		Targets:                 summaries,
+		FleetOutputTokensPerSec: fleet,
+		FleetTargets:            fleetTargets,
+	}
+}
+
+// targetSummary condenses a target's latest run. Must be called with m.mu
+// held.
+func (m *DefaultTargetManager) targetSummary(mt *managedTarget) api.TargetSummary {
+	summary := api.TargetSummary{
+		Name:        mt.target.Name,
+		Environment: mt.environment,
+		Model:       mt.target.Model,
+		Status:      m.reportedStatus(mt),
+		LastRunAt:   mt.lastRunAt,
+	}
+	if results := mt.lastResults; results != nil {
+		summary.OutputTokensPerSec = results.OutputTokensPerSec
+		summary.RequestsPerSec = results.RequestsPerSec
+		if results.TotalRequests > 0 {
+			rate := float64(results.SuccessfulRequests) / float64(results.TotalRequests)
+			summary.SuccessRate = &rate
+		}
+		summary.TTFT = summarizeLatency(results.TTFTValues, nil)
+		summary.E2E = summarizeLatency(results.E2EValues, results.E2EStats)
+		if mt.baseline != nil {
+			summary.VsBaseline = baselineDeltas(mt.baseline, results)
+		}
+	}
+	return summary
+}
+
+// fleetThroughput sums the latest output tokens per second of the targets
+// that ran within the fleet freshness window, so stopped or failing-to-run
+// targets drop out of the total. It returns the total and how many targets
+// it includes. Must be called with m.mu held.
+func (m *DefaultTargetManager) fleetThroughput(now time.Time) (float64, int) {
+	all := make([]*managedTarget, 0, len(m.targets))
+	for _, mt := range m.targets {
+		all = append(all, mt)
+	}
+	return m.freshThroughput(now, all)
+}
+
+// freshThroughput is fleetThroughput over just the given targets. Must be
+// called with m.mu held.
+func (m *DefaultTargetManager) freshThroughput(now time.Time, mts []*managedTarget) (float64, int) {
+	freshness := m.cfg.GetFleetFreshness()
+	var total float64
+	var targets int
+	for _, mt := range mts {
+		if mt.lastResults == nil || mt.lastRunAt == nil || now.Sub(*mt.lastRunAt) > freshness {
+			continue
+		}
+		total += mt.lastResults.OutputTokensPerSec
+		targets++
+	}
+	return total, targets
+}
+
+// currentFleetThroughput returns the fleet throughput as of now, for the
+// fleet throughput gauge to report when scraped
+func (m *DefaultTargetManager) currentFleetThroughput() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	total, _ := m.fleetThroughput(time.Now())
+	return total
+}
+
+// summarizeLatency returns the p50 and p95 of values, falling back to
+// guidellm's distribution stats when there are no individual values (nil if
+// neither is available). values is left unsorted.
+func summarizeLatency(values []float64, stats *parser.DistributionSummary) *api.LatencySummary {
+	if len(values) == 0 {
+		if stats == nil || stats.Count == 0 {
+			return nil
+		}
+		return &api.LatencySummary{P50: stats.Percentiles.P50, P95: stats.Percentiles.P95}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return &api.LatencySummary{
+		P50: parser.Percentile(sorted, 50),
+		P95: parser.Percentile(sorted, 95),
 	}
 }
 
 // GetLatestResults returns the latest benchmark results for a target
-func (m *DefaultTargetManager) GetLatestResults(name string) (*parser.ParsedResults, bool) {
+// (nil if no run has completed yet)
+func (m *DefaultTargetManager) GetLatestResults(name string) (*api.ResultsResponse, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	mt, exists := m.targets[name]
 	if !exists {
-		return nil, false
+		return nil, errTargetNotFound(name)
+	}
+
+	return &api.ResultsResponse{
+		Name:      name,
+		Results:   mt.lastResults,
+		LastRunAt: mt.lastRunAt,
+		IsRunning: mt.runsInFlight > 0,
+		Sweep:     mt.lastSweep,
+	}, nil
+}
+
+// GetResultsHistory returns the target's runs with results that completed
+// within window, oldest first
+func (m *DefaultTargetManager) GetResultsHistory(name string, window time.Duration) ([]api.RunResults, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+
+	since := time.Now().Add(-window)
+	runs := []api.RunResults{}
+	for _, entry := range mt.history {
+		if !entry.at.Before(since) {
+			runs = append(runs, api.RunResults{At: entry.at, Results: entry.results})
+		}
+	}
+	return runs, nil
+}
+
+// GetRawResults returns the archived raw guidellm JSON of the target's
+// latest run (nil if nothing has been archived)
+func (m *DefaultTargetManager) GetRawResults(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
 	}
 
-	return mt.lastResults, mt.lastResults != nil
+	return mt.lastRaw, nil
 }
 
 // TriggerRun triggers an immediate benchmark run for a target
-// This runs synchronously and returns the results when complete
-// After a manual run, scheduled runs are auto-paused for 60 minutes
-func (m *DefaultTargetManager) TriggerRun(ctx context.Context, name string, runID string) (*parser.ParsedResults, error) {
+// This runs synchronously and returns the results when complete, unless ctx
+// is cancelled first, which cancels the run
+// After a manual run, the target's own scheduled runs are paused for 60 minutes
+func (m *DefaultTargetManager) TriggerRun(ctx context.Context, name string, runID string, overrides *api.RunOverrides) (*parser.ParsedResults, error) {
+	if runID == "" {
+		runID = api.NewRunID()
+	}
+
 	m.mu.RLock()
 	mt, exists := m.targets[name]
 	if !exists {
 		m.mu.RUnlock()
-		return nil, fmt.Errorf("target %q not found", name)
+		return nil, errTargetNotFound(name)
 	}
-	target := mt.target
+	target := mt.effectiveTarget(time.Now())
 	envName := mt.environment
 	m.mu.RUnlock()
 
+	if err := validateRunOverrides(m.cfg, target, overrides); err != nil {
+		return nil, err
+	}
+	target = applyRunOverrides(target, overrides)
+
 	if m.runner == nil {
 		return nil, fmt.Errorf("runner not initialized")
 	}
 
-	logger := m.logger.With(
+	logger := api.WithRequestID(ctx, m.logger).With(
 		"environment", envName,
 		"target", name,
 		"model", target.Model,
@@ -312,58 +901,77 @@ func (m *DefaultTargetManager) TriggerRun(ctx context.Context, name string, runI
 		"trigger", "manual",
 	)
 
+	if overrides != nil {
+		logger = logger.With("overrides", *overrides)
+	}
 	logger.Info("triggering manual benchmark run")
 
-	// Pause scheduler before manual run
+	// Pause the target's schedule for the manual run, so a scheduled run
+	// doesn't overlap or immediately follow it. Other targets keep their
+	// schedules, and a target that isn't running on one has nothing to
+	// hold off.
 	m.mu.Lock()
-	wasAlreadyPaused := m.schedulerPaused
-	if !wasAlreadyPaused {
-		m.schedulerPaused = true
+	run, err := m.beginRunRecord(runID, target, "manual")
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	run.Overrides = overrides
+	if !m.beginRun(mt) {
+		err := errTargetNotFound(name)
+		m.finishRunRecord(run, nil, err)
+		m.mu.Unlock()
+		return nil, err
+	}
+	pauseSchedule := mt.status == api.TargetStatusRunning && mt.pausedAt == nil
+	if pauseSchedule {
 		now := time.Now()
-		m.schedulerPausedAt = &now
-		metrics.SchedulerPaused.Set(1)
-		logger.Info("scheduler paused for manual run")
+		mt.pausedAt, mt.pauseReason = &now, api.PauseReasonManualRun
+		logger.Info("target schedule paused for manual run")
 	}
 	m.mu.Unlock()
 
+	// The run is cancelled with ctx, or by StopAll on shutdown, and shutdown
+	// waits for it to wind down. Callers that shouldn't cancel it, like API
+	// requests whose client may disconnect, pass a detached context.
+	m.wg.Add(1)
+	defer m.wg.Done()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(m.stopCtx, cancel)
+	defer stop()
+
 	// Run the benchmark synchronously
-	results := m.runner.runBenchmarkWithResults(ctx, envName, target, logger)
+	output, runErr := m.runner.runBenchmarkWithResults(runCtx, envName, target, runID, logger)
 
-	// Update last run time and results
+	// Update last run time and results. The target may have been removed
+	// while the run was in flight, in which case the update is discarded
+	// along with it.
 	m.mu.Lock()
-	if mt, exists := m.targets[name]; exists {
-		now := time.Now()
-		mt.lastRunAt = &now
-		mt.lastResults = results
-	}
-
-	// Set up auto-resume timer (60 minutes) if scheduler was not already paused
-	if !wasAlreadyPaused {
-		// Cancel existing timer if any
-		if m.autoResumeTimer != nil {
-			m.autoResumeTimer.Stop()
-		}
-
-		m.autoResumeTimer = time.AfterFunc(60*time.Minute, func() {
-			m.mu.Lock()
-			defer m.mu.Unlock()
-
-			if m.schedulerPaused {
-				m.schedulerPaused = false
-				m.schedulerPausedAt = nil
-				m.autoResumeTimer = nil
-				metrics.SchedulerPaused.Set(0)
-				m.logger.Info("scheduler auto-resumed after manual run delay")
-			}
-		})
-
-		logger.Info("scheduler will auto-resume in 60 minutes")
+	m.recordRun(mt, output, runErr)
+	m.finishRunRecord(run, output, runErr)
+	m.notifyRun(mt, target, "manual", runID, output, runErr)
+	ranAt := *mt.lastRunAt
+
+	// The target's schedule resumes by itself a while after the run, unless
+	// it was resumed or paused via the API in the meantime
+	if pauseSchedule && mt.pauseReason == api.PauseReasonManualRun {
+		resumeAt := time.Now().Add(manualRunPause)
+		mt.pauseUntil = &resumeAt
+		logger.Info("target schedule will resume after manual run", "resume_at", resumeAt)
 	}
 	m.mu.Unlock()
 
-	if results == nil {
+	m.archiveRun(envName, target, runID, ranAt, output, runErr)
+	m.pushMetrics(envName, target)
+
+	if output == nil {
+		if runErr != nil {
+			return nil, runErr
+		}
 		return nil, fmt.Errorf("benchmark produced no results")
 	}
+	results := output.results
 
 	logger.Info("manual benchmark run completed",
 		"requests", results.TotalRequests,
@@ -373,8 +981,24 @@ func (m *DefaultTargetManager) TriggerRun(ctx context.Context, name string, runI
 	return results, nil
 }
 
-// LoadFromConfig loads targets from configuration (for backwards compatibility)
-func (m *DefaultTargetManager) LoadFromConfig() {
+// manualRunPause is how long a target's schedule stays paused after a
+// manual run of it
+const manualRunPause = 60 * time.Minute
+
+// schedulePaused reports whether a target's scheduled runs are held, by the
+// global scheduler pause or its own. Must be called with m.mu held.
+func (m *DefaultTargetManager) schedulePaused(mt *managedTarget, now time.Time) bool {
+	return m.schedulerPaused || mt.activePause(now) != nil
+}
+
+// LoadFromConfig loads targets from configuration (for backwards compatibility).
+// It fails without loading anything if a target name is used in more than
+// one environment.
+func (m *DefaultTargetManager) LoadFromConfig() error {
+	if err := m.cfg.CheckTargetNames(); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -389,6 +1013,7 @@ func (m *DefaultTargetManager) LoadFromConfig() {
 	}
 
 	m.logger.Info("loaded targets from config", "count", len(m.targets))
+	return nil
 }
 
 // LoadFromDiscovery discovers and loads targets dynamically from /v1/models endpoints
@@ -401,71 +1026,269 @@ func (m *DefaultTargetManager) LoadFromDiscovery(ctx context.Context) error {
 	discoveryClient := discovery.NewClient(m.logger)
 
 	for envName, envConfig := range m.cfg.Discovery.Environments {
-		m.logger.Info("discovering models for environment",
-			"environment", envName,
-			"endpoint", envConfig.Endpoint)
-
-		// Fetch models from API
-		models, err := discoveryClient.DiscoverModels(ctx, envConfig.Endpoint, envConfig.APIKey)
-		if err != nil {
-			m.logger.Error("failed to discover models",
-				"environment", envName,
-				"error", err)
-			continue
-		}
-
-		// Filter to text models only
-		textModels := discovery.FilterTextModels(models)
-		m.logger.Info("filtered to text models",
-			"environment", envName,
-			"total", len(models),
-			"text_models", len(textModels))
+		m.discoverEnvironment(ctx, discoveryClient, envName, envConfig, nil)
+	}
 
-		// Generate targets
-		targets := discovery.GenerateTargets(textModels, envConfig.BaseURL, envConfig.APIKey, envName)
+	return nil
+}
 
-		// Add to manager
-		m.mu.Lock()
-		for _, target := range targets {
-			// Skip if target already exists (static config takes precedence)
-			if _, exists := m.targets[target.Name]; exists {
-				m.logger.Debug("target already exists, skipping",
-					"name", target.Name,
-					"environment", envName)
-				continue
-			}
+// StartDiscovery runs discovery in the background, one goroutine per
+// environment, so it can overlap with starting the configured targets.
+// Each discovered target is started as soon as it is added when autoStart
+// is set. The returned channel is closed once discovery has finished.
+func (m *DefaultTargetManager) StartDiscovery(ctx context.Context, autoStart bool) <-chan struct{} {
+	done := make(chan struct{})
+	if !m.cfg.Discovery.Enabled {
+		m.logger.Info("model discovery disabled")
+		close(done)
+		return done
+	}
 
-			m.targets[target.Name] = &managedTarget{
-				target:      target,
-				environment: envName,
-				status:      api.TargetStatusStopped,
+	var onAdded func(name string)
+	if autoStart {
+		onAdded = func(name string) {
+			if err := m.StartTarget(ctx, name); err != nil {
+				m.logger.Error("failed to start discovered target", "name", name, "error", err)
 			}
-
-			m.logger.Info("discovered target added",
-				"name", target.Name,
-				"model", target.Model,
-				"environment", envName)
 		}
-		m.mu.Unlock()
 	}
 
-	return nil
-}
+	discoveryClient := discovery.NewClient(m.logger)
 
-// StartAllConfigured starts all targets loaded from configuration
-func (m *DefaultTargetManager) StartAllConfigured(ctx context.Context) {
-	m.mu.RLock()
-	names := make([]string, 0, len(m.targets))
-	for name := range m.targets {
-		names = append(names, name)
+	var wg sync.WaitGroup
+	for envName, envConfig := range m.cfg.Discovery.Environments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.discoverEnvironment(ctx, discoveryClient, envName, envConfig, onAdded)
+		}()
 	}
-	m.mu.RUnlock()
 
-	for _, name := range names {
-		if err := m.StartTarget(ctx, name); err != nil {
-			m.logger.Error("failed to start target", "name", name, "error", err)
-		}
-	}
+	go func() {
+		wg.Wait()
+		m.logger.Info("background discovery complete")
+		close(done)
+	}()
+
+	return done
+}
+
+// discoverEnvironment discovers the models for one environment and adds a
+// target for each selected model not already registered. onAdded, if set, is
+// called (without the lock held) for each newly added target.
+func (m *DefaultTargetManager) discoverEnvironment(ctx context.Context, discoveryClient *discovery.Client, envName string, envConfig config.DiscoveryEnvConfig, onAdded func(name string)) {
+	m.logger.Info("discovering models for environment",
+		"environment", envName,
+		"endpoint", envConfig.Endpoint)
+
+	// Fetch models from API
+	models, err := discoveryClient.DiscoverModels(ctx, envConfig.Endpoint, envConfig.APIKey)
+	if err != nil {
+		m.logger.Error("failed to discover models",
+			"environment", envName,
+			"error", err)
+		return
+	}
+
+	// Filter by model type and include/exclude patterns
+	selected, err := discovery.FilterModels(models, discovery.FilterOptions{
+		Include:    envConfig.Include,
+		Exclude:    envConfig.Exclude,
+		ModelTypes: envConfig.ModelTypes,
+	})
+	if err != nil {
+		m.logger.Error("failed to filter discovered models",
+			"environment", envName,
+			"error", err)
+		return
+	}
+	m.logger.Info("filtered discovered models",
+		"environment", envName,
+		"total", len(models),
+		"selected", len(selected))
+
+	// Generate targets
+	targets := discovery.GenerateTargets(selected, envConfig.BaseURL, envConfig.APIKey, envName)
+
+	// Add to manager
+	added := make([]string, 0, len(targets))
+	m.mu.Lock()
+	for _, target := range targets {
+		// Skip if target already exists (static config takes precedence)
+		if _, exists := m.targets[target.Name]; exists {
+			m.logger.Debug("target already exists, skipping",
+				"name", target.Name,
+				"environment", envName)
+			continue
+		}
+
+		m.targets[target.Name] = &managedTarget{
+			target:      target,
+			environment: envName,
+			status:      api.TargetStatusStopped,
+		}
+		added = append(added, target.Name)
+
+		m.logger.Info("discovered target added",
+			"name", target.Name,
+			"model", target.Model,
+			"environment", envName)
+	}
+	m.mu.Unlock()
+
+	if onAdded != nil {
+		for _, name := range added {
+			onAdded(name)
+		}
+	}
+}
+
+// StartAllConfigured starts all targets loaded from configuration, in name
+// order, except those with auto_start off. With a startup stagger the starts
+// are spaced out in the background, until ctx is cancelled or StopAll is
+// called.
+func (m *DefaultTargetManager) StartAllConfigured(ctx context.Context) {
+	names, skipped := m.autoStartTargets()
+	if len(skipped) > 0 {
+		m.logger.Info("not auto-starting targets with auto_start off", "targets", skipped)
+	}
+
+	if m.startStagger <= 0 || len(names) < 2 {
+		for _, name := range names {
+			m.startConfigured(ctx, name)
+		}
+		return
+	}
+
+	m.logger.Info("staggering target starts", "targets", len(names), "stagger", m.startStagger)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for i, name := range names {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-m.stopCtx.Done():
+					return
+				case <-time.After(m.startStagger):
+				}
+			}
+			m.startConfigured(ctx, name)
+		}
+	}()
+}
+
+// autoStartTargets returns the names of the targets StartAllConfigured
+// starts and of those it skips as their auto_start is off, each in order
+func (m *DefaultTargetManager) autoStartTargets() (names, skipped []string) {
+	m.mu.RLock()
+	for name, mt := range m.targets {
+		if mt.target.GetAutoStart() {
+			names = append(names, name)
+		} else {
+			skipped = append(skipped, name)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+	sort.Strings(skipped)
+	return names, skipped
+}
+
+// startConfigured starts a configured target, retrying in the background
+// if it fails
+func (m *DefaultTargetManager) startConfigured(ctx context.Context, name string) {
+	err := m.startFn(ctx, name)
+	if err == nil {
+		return
+	}
+	if !retryableStartError(err) || m.startRetryAttempts == 0 {
+		m.startFailed(name, err)
+		return
+	}
+
+	// Retry in the background so one failing target doesn't hold up the rest
+	m.logger.Warn("failed to start target, will retry",
+		"name", name,
+		"error", err,
+		"attempts", m.startRetryAttempts)
+	m.wg.Add(1)
+	go m.retryStart(ctx, name)
+}
+
+// retryStart retries starting a target with exponential backoff, giving up
+// after the configured number of attempts or when ctx is cancelled
+func (m *DefaultTargetManager) retryStart(ctx context.Context, name string) {
+	defer m.wg.Done()
+
+	backoff := m.startRetryBackoff
+	var err error
+	for attempt := 1; attempt <= m.startRetryAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if err = m.startFn(ctx, name); err == nil {
+			m.logger.Info("target started after retry", "name", name, "attempt", attempt)
+			return
+		}
+		if !retryableStartError(err) {
+			break
+		}
+		m.logger.Warn("retrying target start failed", "name", name, "attempt", attempt, "error", err)
+	}
+	m.startFailed(name, err)
+}
+
+// startFailed records a target that could not be started
+func (m *DefaultTargetManager) startFailed(name string, err error) {
+	m.logger.Error("failed to start target", "name", name, "error", err)
+
+	m.mu.RLock()
+	mt, exists := m.targets[name]
+	var labels map[string]string
+	if exists {
+		labels = metrics.Labels(mt.environment, mt.target.Name, mt.target.Model)
+	}
+	m.mu.RUnlock()
+	if exists {
+		metrics.TargetStartFailures.With(labels).Inc()
+	}
+}
+
+// retryableStartError reports whether a start failure may be transient.
+// Unknown and already-running targets won't change by retrying.
+func retryableStartError(err error) bool {
+	return !errors.Is(err, api.ErrNotFound) && !errors.Is(err, errAlreadyRunning)
+}
+
+// DryRun logs the guidellm command each registered target would run,
+// in name order, without executing anything
+func (m *DefaultTargetManager) DryRun() {
+	if m.runner == nil {
+		m.logger.Error("runner not set, cannot dry run")
+		return
+	}
+
+	m.mu.RLock()
+	targets := make([]*managedTarget, 0, len(m.targets))
+	for _, mt := range m.targets {
+		targets = append(targets, mt)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].target.Name < targets[j].target.Name
+	})
+
+	for _, mt := range targets {
+		m.runner.DryRun(mt.environment, mt.target, m.logger)
+	}
+	m.logger.Info("dry run complete", "targets", len(targets))
 }
 
 // Wait waits for all running targets to complete
@@ -473,8 +1296,11 @@ func (m *DefaultTargetManager) Wait() {
 	m.wg.Wait()
 }
 
-// StopAll stops all running targets
+// StopAll stops all running targets and cancels manual runs in flight, for
+// shutdown
 func (m *DefaultTargetManager) StopAll() {
+	m.cancelStop()
+
 	m.mu.Lock()
 	for name, mt := range m.targets {
 		if mt.status == api.TargetStatusRunning && mt.cancel != nil {
@@ -487,23 +1313,21 @@ func (m *DefaultTargetManager) StopAll() {
 	m.mu.Unlock()
 }
 
-// runTargetLoop runs the benchmark loop for a single target
-func (m *DefaultTargetManager) runTargetLoop(ctx context.Context, name string) {
+// runTargetLoop runs the benchmark loop for a single target. It works on the
+// managedTarget captured by StartTarget rather than looking the name up again,
+// so a concurrent remove (or remove and re-add) can't redirect its updates.
+func (m *DefaultTargetManager) runTargetLoop(ctx context.Context, mt *managedTarget) {
 	defer m.wg.Done()
 
 	m.mu.RLock()
-	mt, exists := m.targets[name]
-	if !exists {
-		m.mu.RUnlock()
-		return
-	}
 	target := mt.target
 	envName := mt.environment
+	once := m.once
 	m.mu.RUnlock()
 
 	logger := m.logger.With(
 		"environment", envName,
-		"target", name,
+		"target", target.Name,
 		"model", target.Model,
 	)
 
@@ -512,72 +1336,719 @@ func (m *DefaultTargetManager) runTargetLoop(ctx context.Context, name string) {
 		"profile", target.GetProfile(m.cfg.Defaults),
 		"rate", target.GetRate(m.cfg.Defaults))
 
-	ticker := time.NewTicker(m.cfg.GetInterval())
-	defer ticker.Stop()
+	schedule := newRunSchedule(m.cfg.Defaults, m.cfg.GetInterval(), time.Now())
+	if once {
+		// A one-off run doesn't wait for its place in the schedule
+		schedule.next = time.Now()
+	}
+	m.setNextRunAt(ctx, mt, schedule.next)
+	timer := time.NewTimer(time.Until(schedule.next))
+	defer timer.Stop()
 
-	// Run immediately, then on interval
-	m.runBenchmarkWithCallback(ctx, envName, target, logger, name)
+	// Warm the server up just before the first run
+	warmedUp := false
 
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("stopping benchmark loop")
 			m.mu.Lock()
-			if mt, exists := m.targets[name]; exists {
+			// Only mark stopped if no newer loop has been started for this
+			// target since we were cancelled
+			if mt.cancel == nil {
 				mt.status = api.TargetStatusStopped
 			}
 			m.mu.Unlock()
 			return
-		case <-ticker.C:
-			// Check if scheduler is paused
-			m.mu.RLock()
-			paused := m.schedulerPaused
-			m.mu.RUnlock()
+		case <-m.draining:
+			logger.Info("benchmark loop drained for shutdown")
+			return
+		case <-timer.C:
+			// Shutdown may have started while this loop was waiting
+			if m.isDraining() {
+				logger.Info("benchmark loop drained for shutdown")
+				return
+			}
+
+			// The next run is due an interval after this one was scheduled
+			schedule.advance(time.Now())
+			m.setNextRunAt(ctx, mt, schedule.next)
+
+			// Check if the scheduler or this target's schedule is paused
+			m.mu.Lock()
+			paused := m.schedulePaused(mt, time.Now())
+			if !paused && mt.pausedAt != nil {
+				mt.clearPause()
+				logger.Info("target schedule resumed")
+			}
+			m.mu.Unlock()
 
 			if !paused {
-				m.runBenchmarkWithCallback(ctx, envName, target, logger, name)
+				if !warmedUp {
+					m.runWarmups(ctx, m.scheduledTarget(mt, logger), logger)
+					warmedUp = true
+				}
+				m.runCycle(ctx, envName, m.scheduledTarget(mt, logger), logger, mt)
+				if m.countScheduledRun(ctx, mt) {
+					logger.Info("target completed its runs, stopping")
+					return
+				}
 			} else {
-				logger.Debug("skipping scheduled run (scheduler paused)")
+				logger.Debug("skipping scheduled run (schedule paused)")
+			}
+			if schedule.skipMissed(time.Now()) {
+				m.setNextRunAt(ctx, mt, schedule.next)
 			}
+			timer.Reset(time.Until(schedule.next))
 		}
 	}
 }
 
-// runBenchmarkWithCallback runs a benchmark and updates the target's last results
-func (m *DefaultTargetManager) runBenchmarkWithCallback(ctx context.Context, envName string, target config.Target, logger *slog.Logger, name string) {
+// countScheduledRun counts a scheduled run against the target's max_runs,
+// stopping the target once none remain. It returns true if the loop should
+// exit, which it leaves alone if the loop has already been stopped.
+func (m *DefaultTargetManager) countScheduledRun(ctx context.Context, mt *managedTarget) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ctx.Err() != nil || mt.remainingRuns == nil {
+		return false
+	}
+	*mt.remainingRuns--
+	if *mt.remainingRuns > 0 {
+		return false
+	}
+	if mt.cancel != nil {
+		mt.cancel()
+		mt.cancel = nil
+	}
+	mt.status = api.TargetStatusStopped
+	return true
+}
+
+// setNextRunAt records when a target's loop will next run it, unless the
+// loop has been stopped (and possibly replaced by a newer one)
+func (m *DefaultTargetManager) setNextRunAt(ctx context.Context, mt *managedTarget, next time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ctx.Err() == nil {
+		mt.nextRunAt = &next
+	}
+}
+
+// nextRunAt returns when a target is next scheduled to run, or nil if it
+// is stopped or its schedule is paused. Must be called with m.mu held.
+func (m *DefaultTargetManager) nextRunAt(mt *managedTarget) *time.Time {
+	if mt.status != api.TargetStatusRunning || mt.nextRunAt == nil || m.schedulePaused(mt, time.Now()) {
+		return nil
+	}
+	next := *mt.nextRunAt
+	return &next
+}
+
+// runWarmups executes the target's warmup runs, discarding their results so
+// a cold server's first numbers don't pollute metrics or history. Failed
+// warmups are logged and don't hold up the loop.
+func (m *DefaultTargetManager) runWarmups(ctx context.Context, target config.Target, logger *slog.Logger) {
+	warmups := target.GetWarmupRuns(m.cfg.Defaults)
+	if m.runner == nil || warmups == 0 {
+		return
+	}
+
+	for i := 1; i <= warmups && ctx.Err() == nil; i++ {
+		logger.Info("running warmup benchmark", "warmup", i, "of", warmups)
+		if err := m.runner.runWarmup(ctx, target, logger); err != nil && ctx.Err() == nil {
+			logger.Warn("warmup benchmark failed", "warmup", i, "error", err)
+		}
+	}
+}
+
+// scheduledTarget returns the target config for the next scheduled run,
+// dropping the target's override (and reverting to its configured settings)
+// once it has expired
+func (m *DefaultTargetManager) scheduledTarget(mt *managedTarget, logger *slog.Logger) config.Target {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if mt.override != nil && mt.activeOverride(now) == nil {
+		mt.override = nil
+		logger.Info("target override expired, reverting to configured settings")
+	}
+	return mt.effectiveTarget(now)
+}
+
+// runCycle makes a target's scheduled run, or with a sweep, runs each step
+// of the sweep in turn and records them together as its latest sweep
+func (m *DefaultTargetManager) runCycle(ctx context.Context, envName string, target config.Target, logger *slog.Logger, mt *managedTarget) {
+	if len(target.Sweep) == 0 {
+		m.runBenchmarkWithCallback(ctx, envName, target, logger, mt)
+		return
+	}
+
+	steps := target.SweepTargets()
+	sweep := make([]api.RunResponse, 0, len(steps))
+	for i, step := range steps {
+		stepLogger := logger.With(
+			"sweep_step", i+1,
+			"profile", step.GetProfile(m.cfg.Defaults),
+			"rate", step.GetRate(m.cfg.Defaults))
+		run := m.runBenchmarkWithCallback(ctx, envName, step, stepLogger, mt)
+		if run == nil || ctx.Err() != nil || m.isDraining() {
+			logger.Info("sweep interrupted", "completed_steps", len(sweep))
+			return
+		}
+		sweep = append(sweep, *run)
+	}
+
+	m.mu.Lock()
+	mt.lastSweep = sweep
+	m.mu.Unlock()
+}
+
+// runBenchmarkWithCallback runs a benchmark and updates the target's last
+// results. It returns the run as recorded in the registry, or nil if it
+// didn't start.
+func (m *DefaultTargetManager) runBenchmarkWithCallback(ctx context.Context, envName string, target config.Target, logger *slog.Logger, mt *managedTarget) *api.RunResponse {
 	if m.runner == nil {
 		logger.Error("runner not set, cannot run benchmark")
-		return
+		return nil
 	}
 
 	// Run the benchmark and get results
-	results := m.runner.runBenchmarkWithResults(ctx, envName, target, logger)
-
-	// Update last run time and results
+	runID := api.NewRunID()
 	m.mu.Lock()
-	if mt, exists := m.targets[name]; exists {
-		now := time.Now()
-		mt.lastRunAt = &now
-		mt.lastResults = results
+	started := m.beginRun(mt)
+	var run *api.RunResponse
+	if started {
+		// A generated ID can't clash with an existing run
+		run, _ = m.beginRunRecord(runID, target, "scheduled")
 	}
 	m.mu.Unlock()
+	if !started {
+		return nil
+	}
+	output, err := m.runner.runBenchmarkWithResults(ctx, envName, target, runID, logger.With("run_id", runID))
+
+	// Update last run time, results and error
+	m.mu.Lock()
+	m.recordRun(mt, output, err)
+	m.finishRunRecord(run, output, err)
+	m.notifyRun(mt, target, "scheduled", runID, output, err)
+	ranAt := *mt.lastRunAt
+	result := *run
+	m.mu.Unlock()
+
+	m.archiveRun(envName, target, runID, ranAt, output, err)
+	m.pushMetrics(envName, target)
+	return &result
+}
+
+// beginRun registers a run on the target, refusing once the target has been
+// removed so no run can write its metric series after they are deleted.
+// Must be called with m.mu held for writing.
+func (m *DefaultTargetManager) beginRun(mt *managedTarget) bool {
+	if mt.removed {
+		return false
+	}
+	mt.runsInFlight++
+	mt.runs.Add(1)
+	return true
+}
+
+// recordRun stores the outcome of a run on the target and ends the run
+// registered by beginRun. Must be called with m.mu held for writing.
+func (m *DefaultTargetManager) recordRun(mt *managedTarget, output *runOutput, err error) {
+	if mt.runsInFlight > 0 {
+		mt.runsInFlight--
+		mt.runs.Done()
+	}
+
+	now := time.Now()
+	// A run cancelled before it started (e.g. on stop) says nothing about
+	// the target, so it doesn't count as its latest failure
+	if err != nil && !errors.Is(err, context.Canceled) {
+		mt.lastError = errorReport(err)
+		mt.lastErrorAt = &now
+		mt.consecutiveFailures++
+		m.logFailure(mt, now, err)
+	} else if err == nil && output != nil {
+		mt.consecutiveFailures = 0
+	}
+	if !errors.Is(err, context.Canceled) {
+		labels := metrics.Labels(mt.environment, mt.target.Name, mt.target.Model)
+		metrics.ConsecutiveFailures.With(labels).Set(float64(mt.consecutiveFailures))
+	}
+	mt.lastRunAt = &now
+	mt.lastResults = nil
+	mt.lastRaw = nil
+	if output == nil {
+		return
+	}
+
+	mt.lastResults = output.results
+	if m.cfg.ArchiveRawOutput {
+		mt.lastRaw = output.raw
+	}
+	if output.results != nil {
+		// Failed runs (e.g. ones that made no requests) can still have
+		// results, but would skew the history's baselines and percentiles
+		if err == nil {
+			m.checkRegression(mt, now, output.results.OutputTokensPerSec)
+			mt.history = append(mt.history, historyEntry{at: now, runID: output.runID, results: output.results})
+			if len(mt.history) > maxHistoryEntries {
+				mt.history = mt.history[len(mt.history)-maxHistoryEntries:]
+			}
+		}
+		m.publishResults(mt.target.Name, output.results)
+	}
+}
+
+// notifyRun queues a webhook event for a completed run. Runs cancelled
+// before finishing aren't reported. Must be called with m.mu held.
+func (m *DefaultTargetManager) notifyRun(mt *managedTarget, target config.Target, trigger, runID string, output *runOutput, err error) {
+	if m.notifier == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	event := webhook.RunEvent{
+		Event:       webhook.EventRunCompleted,
+		Target:      target.Name,
+		Environment: mt.environment,
+		Model:       target.Model,
+		RunID:       runID,
+		Trigger:     trigger,
+		Success:     err == nil,
+		Timestamp:   time.Now(),
+	}
+	if mt.lastRunAt != nil {
+		event.Timestamp = *mt.lastRunAt
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if output != nil && output.results != nil {
+		event.TotalRequests = output.results.TotalRequests
+		event.SuccessfulRequests = output.results.SuccessfulRequests
+		event.FailedRequests = output.results.FailedRequests
+		event.RequestsPerSec = output.results.RequestsPerSec
+		event.OutputTokensPerSec = output.results.OutputTokensPerSec
+	}
+	m.notifier.Notify(event)
+}
+
+// pushMetrics pushes a target's metrics to the Pushgateway, if one is set,
+// after a run. As with archiving, push errors are only logged.
+func (m *DefaultTargetManager) pushMetrics(envName string, target config.Target) {
+	if m.pusher == nil {
+		return
+	}
+	if err := m.pusher.PushTarget(context.Background(), envName, target.Name); err != nil {
+		m.logger.Warn("failed to push metrics to the pushgateway", "target", target.Name, "error", err)
+	}
+}
+
+// archiveRun stores a completed run's results, with the raw guidellm output,
+// to each result sink. Runs without results aren't archived, and sink errors
+// are only logged so archiving can never fail a run.
+func (m *DefaultTargetManager) archiveRun(envName string, target config.Target, runID string, at time.Time, output *runOutput, err error) {
+	if len(m.sinks) == 0 || output == nil || output.results == nil {
+		return
+	}
+
+	rec := results.Record{
+		Timestamp:   at,
+		Environment: envName,
+		Target:      target.Name,
+		Model:       target.Model,
+		RunID:       runID,
+		Results:     output.results,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	for _, sink := range m.sinks {
+		if err := sink.Store(context.Background(), rec, output.raw); err != nil {
+			m.logger.Error("failed to archive results", "target", target.Name, "error", err)
+		}
+	}
+}
+
+// logFailure appends a failed run to the failure log. Must be called with
+// m.mu held for writing.
+func (m *DefaultTargetManager) logFailure(mt *managedTarget, at time.Time, err error) {
+	category := FailureNonzeroExit
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		category = runErr.Category
+	}
+
+	m.failures = append(m.failures, failureEvent{
+		at:          at,
+		target:      mt.target.Name,
+		environment: mt.environment,
+		category:    category,
+		message:     err.Error(),
+	})
+	if len(m.failures) > maxFailureEvents {
+		m.failures = m.failures[len(m.failures)-maxFailureEvents:]
+	}
+}
+
+// GetFailures breaks down the run failures logged within window by stage,
+// reason and target. Failures of targets since removed are still included.
+func (m *DefaultTargetManager) GetFailures(window time.Duration) (*api.FailuresResponse, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	resp := &api.FailuresResponse{
+		Window:   window.String(),
+		ByStage:  make(map[string]int),
+		ByReason: make(map[string]int),
+		Targets:  []api.TargetFailures{},
+	}
+	byTarget := make(map[string]*api.TargetFailures)
+	var order []string
+
+	since := time.Now().Add(-window)
+	for _, event := range m.failures {
+		if event.at.Before(since) {
+			continue
+		}
+		stage, reason := event.category.Stage(), string(event.category)
+		resp.Total++
+		resp.ByStage[stage]++
+		resp.ByReason[reason]++
+
+		key := event.environment + "/" + event.target
+		tf, ok := byTarget[key]
+		if !ok {
+			tf = &api.TargetFailures{
+				Name:        event.target,
+				Environment: event.environment,
+				ByStage:     make(map[string]int),
+				ByReason:    make(map[string]int),
+			}
+			byTarget[key] = tf
+			order = append(order, key)
+		}
+		tf.Total++
+		tf.ByStage[stage]++
+		tf.ByReason[reason]++
+		tf.LastFailureAt = event.at
+		tf.LastError = event.message
+	}
+
+	for _, key := range order {
+		resp.Targets = append(resp.Targets, *byTarget[key])
+	}
+	sort.SliceStable(resp.Targets, func(i, j int) bool {
+		return resp.Targets[i].Total > resp.Targets[j].Total
+	})
+	return resp, nil
 }
 
-// toTargetResponse converts a managedTarget to an API response
+// historyMergeMethod describes how GetHistoryPercentiles combines runs
+const historyMergeMethod = "pooled_samples"
+
+// GetHistoryPercentiles merges latency percentiles across the target's runs
+// that completed within window. Runs are merged by pooling the latency
+// samples each run recorded (the same samples fed to the Prometheus
+// histograms) and taking percentiles of the pool, rather than averaging each
+// run's percentiles, which would understate the tails. Every run contributes
+// the same number of samples per benchmark, so runs are weighted equally
+// regardless of how many requests they made.
+func (m *DefaultTargetManager) GetHistoryPercentiles(name string, window time.Duration) (*api.HistoryPercentilesResponse, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	m.mu.RLock()
+	mt, exists := m.targets[name]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, errTargetNotFound(name)
+	}
+	cutoff := time.Now().Add(-window)
+	var entries []historyEntry
+	for _, entry := range mt.history {
+		if !entry.at.Before(cutoff) {
+			entries = append(entries, entry)
+		}
+	}
+	m.mu.RUnlock()
+
+	resp := &api.HistoryPercentilesResponse{
+		Name:   name,
+		Window: window.String(),
+		Runs:   len(entries),
+		Method: historyMergeMethod,
+	}
+	if len(entries) == 0 {
+		return resp, nil
+	}
+
+	from, to := entries[0].at, entries[len(entries)-1].at
+	resp.From, resp.To = &from, &to
+
+	var ttft, itl, e2e []float64
+	for _, entry := range entries {
+		ttft = append(ttft, entry.results.TTFTValues...)
+		itl = append(itl, entry.results.ITLValues...)
+		e2e = append(e2e, entry.results.E2EValues...)
+	}
+	resp.TTFT = summarizePercentiles(ttft)
+	resp.ITL = summarizePercentiles(itl)
+	resp.E2E = summarizePercentiles(e2e)
+	return resp, nil
+}
+
+// summarizePercentiles computes percentile estimates over pooled samples
+// (nil if there are none). Sorts samples in place.
+func summarizePercentiles(samples []float64) *api.PercentileSummary {
+	if len(samples) == 0 {
+		return nil
+	}
+	sort.Float64s(samples)
+	return &api.PercentileSummary{
+		Samples: len(samples),
+		P50:     parser.Percentile(samples, 50),
+		P90:     parser.Percentile(samples, 90),
+		P95:     parser.Percentile(samples, 95),
+		P99:     parser.Percentile(samples, 99),
+	}
+}
+
+// publishResults sends fresh results to the target's subscribers. A slow
+// subscriber only ever sees the most recent results. Must be called with
+// m.mu held for writing.
+func (m *DefaultTargetManager) publishResults(name string, results *parser.ParsedResults) {
+	for ch := range m.subscribers[name] {
+		select {
+		case <-ch: // drop stale results the subscriber hasn't read yet
+		default:
+		}
+		ch <- results
+	}
+}
+
+// SubscribeResults registers for the target's results as each run completes
+func (m *DefaultTargetManager) SubscribeResults(name string) (<-chan *parser.ParsedResults, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.targets[name]; !exists {
+		return nil, nil, errTargetNotFound(name)
+	}
+
+	ch := make(chan *parser.ParsedResults, 1)
+	if m.subscribers[name] == nil {
+		m.subscribers[name] = make(map[chan *parser.ParsedResults]struct{})
+	}
+	m.subscribers[name][ch] = struct{}{}
+
+	unsubscribe := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// Already closed if the target was removed
+		if _, ok := m.subscribers[name][ch]; !ok {
+			return
+		}
+		delete(m.subscribers[name], ch)
+		if len(m.subscribers[name]) == 0 {
+			delete(m.subscribers, name)
+		}
+		close(ch)
+	}
+	return ch, unsubscribe, nil
+}
+
+// toTargetResponse converts a managedTarget to an API response. Profile,
+// rate and max_seconds reflect any active override.
 func (m *DefaultTargetManager) toTargetResponse(mt *managedTarget) api.TargetResponse {
+	now := time.Now()
+	target := mt.effectiveTarget(now)
+
 	return api.TargetResponse{
-		Name:        mt.target.Name,
-		Model:       mt.target.Model,
-		URL:         mt.target.URL,
-		Environment: mt.environment,
-		Status:      mt.status,
-		Profile:     mt.target.GetProfile(m.cfg.Defaults),
-		Rate:        mt.target.GetRate(m.cfg.Defaults),
-		MaxSeconds:  mt.target.GetMaxSeconds(m.cfg.Defaults),
-		RequestType: mt.target.GetRequestType(m.cfg.Defaults),
-		LastRunAt:   mt.lastRunAt,
-		LastResults: mt.lastResults,
+		Name:                target.Name,
+		Model:               target.Model,
+		URL:                 target.URL,
+		Environment:         mt.environment,
+		Status:              m.reportedStatus(mt),
+		Profile:             target.GetProfile(m.cfg.Defaults),
+		Rate:                target.GetRate(m.cfg.Defaults),
+		MaxSeconds:          target.GetMaxSeconds(m.cfg.Defaults),
+		RequestType:         target.GetRequestType(m.cfg.Defaults),
+		LastRunAt:           mt.lastRunAt,
+		LastResults:         mt.lastResults,
+		LastError:           mt.lastError,
+		LastErrorAt:         mt.lastErrorAt,
+		ConsecutiveFailures: mt.consecutiveFailures,
+		Override:            mt.activeOverride(now),
+		Tags:                target.Tags,
+		NextRunAt:           m.nextRunAt(mt),
+		Pause:               mt.activePause(now),
+		RemainingRuns:       mt.remainingRunsOrMax(),
+		AutoStart:           target.GetAutoStart(),
+	}
+}
+
+// remainingRunsOrMax returns the scheduled runs the target has left, or
+// its max_runs if it hasn't been started
+func (mt *managedTarget) remainingRunsOrMax() *int {
+	remaining := mt.remainingRuns
+	if remaining == nil {
+		remaining = mt.target.MaxRuns
+	}
+	if remaining == nil {
+		return nil
 	}
+	n := *remaining
+	return &n
+}
+
+// reportedStatus returns the status shown for a target: a running target
+// whose latest runs failed is reported as degraded, or as error once the
+// failure streak reaches the configured threshold
+func (m *DefaultTargetManager) reportedStatus(mt *managedTarget) api.TargetStatus {
+	if mt.status != api.TargetStatusRunning || mt.consecutiveFailures == 0 {
+		return mt.status
+	}
+	if mt.consecutiveFailures >= m.cfg.Defaults.GetErrorThreshold() {
+		return api.TargetStatusError
+	}
+	return api.TargetStatusDegraded
+}
+
+// SetOverride temporarily overrides a target's rate, max_seconds and/or
+// profile. Runs use the override until it expires, then revert.
+func (m *DefaultTargetManager) SetOverride(name string, req api.OverrideRequest) (*api.TargetResponse, error) {
+	if req.DurationSeconds <= 0 {
+		return nil, fmt.Errorf("duration_seconds must be positive")
+	}
+	if req.Rate == nil && req.MaxSeconds == nil && req.Profile == "" {
+		return nil, fmt.Errorf("override must set at least one of rate, max_seconds or profile")
+	}
+	if req.Rate != nil && *req.Rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+	if req.MaxSeconds != nil && *req.MaxSeconds <= 0 {
+		return nil, fmt.Errorf("max_seconds must be positive")
+	}
+	if req.Profile != "" {
+		if err := m.cfg.ValidateProfile(req.Profile); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+	profile := req.Profile
+	if profile == "" {
+		profile = mt.target.GetProfile(m.cfg.Defaults)
+	}
+	if err := config.ValidateRate(profile, req.Rate); err != nil {
+		return nil, err
+	}
+
+	mt.override = &api.TargetOverride{
+		Rate:       req.Rate,
+		MaxSeconds: req.MaxSeconds,
+		Profile:    req.Profile,
+		ExpiresAt:  time.Now().Add(time.Duration(req.DurationSeconds) * time.Second),
+	}
+
+	m.logger.Info("target override set",
+		"name", name,
+		"expires_at", mt.override.ExpiresAt)
+
+	resp := m.toTargetResponse(mt)
+	return &resp, nil
+}
+
+// ClearOverride removes a target's override before it expires
+func (m *DefaultTargetManager) ClearOverride(name string) (*api.TargetResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+
+	if mt.override != nil {
+		mt.override = nil
+		m.logger.Info("target override cleared", "name", name)
+	}
+
+	resp := m.toTargetResponse(mt)
+	return &resp, nil
+}
+
+// PauseTarget pauses a target's scheduled runs until ResumeTarget, leaving
+// other targets' schedules running. Manual runs of the target still run.
+func (m *DefaultTargetManager) PauseTarget(name string) (*api.TargetResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+
+	// A manual run's timed pause becomes an indefinite one
+	if mt.pauseReason != api.PauseReasonAPI || !m.schedulePaused(mt, time.Now()) {
+		now := time.Now()
+		mt.pausedAt, mt.pauseUntil, mt.pauseReason = &now, nil, api.PauseReasonAPI
+		m.logger.Info("target schedule paused", "name", name)
+	}
+
+	resp := m.toTargetResponse(mt)
+	return &resp, nil
+}
+
+// ResumeTarget resumes a target's scheduled runs, ending a pause by
+// PauseTarget or after a manual run. The global scheduler pause, if any,
+// still applies.
+func (m *DefaultTargetManager) ResumeTarget(name string) (*api.TargetResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, exists := m.targets[name]
+	if !exists {
+		return nil, errTargetNotFound(name)
+	}
+
+	if mt.pausedAt != nil {
+		mt.clearPause()
+		m.logger.Info("target schedule resumed", "name", name)
+	}
+
+	resp := m.toTargetResponse(mt)
+	return &resp, nil
+}
+
+// clearPause ends the target's own schedule pause
+func (mt *managedTarget) clearPause() {
+	mt.pausedAt, mt.pauseUntil, mt.pauseReason = nil, nil, ""
+}
+
+// activePause returns the target's own schedule pause, or nil if it has
+// none or it has expired. Must be called with m.mu held.
+func (mt *managedTarget) activePause(now time.Time) *api.TargetPause {
+	if mt.pausedAt == nil || (mt.pauseUntil != nil && !now.Before(*mt.pauseUntil)) {
+		return nil
+	}
+	return &api.TargetPause{Reason: mt.pauseReason, PausedAt: *mt.pausedAt, Until: mt.pauseUntil}
 }
 
 // PauseScheduler pauses all scheduled benchmark runs
@@ -589,12 +2060,6 @@ func (m *DefaultTargetManager) PauseScheduler() error {
 		return fmt.Errorf("scheduler is already paused")
 	}
 
-	// Cancel auto-resume timer if it exists
-	if m.autoResumeTimer != nil {
-		m.autoResumeTimer.Stop()
-		m.autoResumeTimer = nil
-	}
-
 	m.schedulerPaused = true
 	now := time.Now()
 	m.schedulerPausedAt = &now
@@ -615,12 +2080,6 @@ func (m *DefaultTargetManager) ResumeScheduler() error {
 		return fmt.Errorf("scheduler is not paused")
 	}
 
-	// Cancel auto-resume timer if it exists
-	if m.autoResumeTimer != nil {
-		m.autoResumeTimer.Stop()
-		m.autoResumeTimer = nil
-	}
-
 	m.schedulerPaused = false
 	m.schedulerPausedAt = nil
 
@@ -638,17 +2097,16 @@ func (m *DefaultTargetManager) GetSchedulerStatus() api.SchedulerStatusResponse
 
 	var nextScheduledRun *time.Time
 	if !m.schedulerPaused {
-		// Calculate next scheduled run based on interval
+		// The earliest of the running targets' next runs
 		for _, mt := range m.targets {
-			if mt.status == api.TargetStatusRunning && mt.lastRunAt != nil {
-				next := mt.lastRunAt.Add(m.cfg.GetInterval())
+			if next := m.nextRunAt(mt); next != nil {
 				if nextScheduledRun == nil || next.Before(*nextScheduledRun) {
-					nextScheduledRun = &next
+					nextScheduledRun = next
 				}
 			}
 		}
 
-		// If no last run, next run is now
+		// With no target scheduled, the next run is now
 		if nextScheduledRun == nil {
 			now := time.Now()
 			nextScheduledRun = &now
@@ -669,3 +2127,14 @@ func (m *DefaultTargetManager) getSchedulerState() api.SchedulerState {
 	}
 	return api.SchedulerStateRunning
 }
+
+// errAlreadyRunning is wrapped when starting a target that is already running
+var errAlreadyRunning = errors.New("already running")
+
+// errNotRunning is wrapped when stopping a target that isn't running
+var errNotRunning = errors.New("not running")
+
+// errTargetNotFound returns the error reported for an unknown target name
+func errTargetNotFound(name string) error {
+	return fmt.Errorf("target %q %w", name, api.ErrNotFound)
+}
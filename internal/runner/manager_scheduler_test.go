@@ -2,9 +2,12 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/yourorg/guidellm-runner/internal/api"
 	"github.com/yourorg/guidellm-runner/internal/config"
@@ -83,7 +86,7 @@ func TestSchedulerAutoResumeAfterManualRun(t *testing.T) {
 
 	// Add a test target
 	ctx := context.Background()
-	err := manager.AddTarget(ctx, api.AddTargetRequest{
+	_, err := manager.AddTarget(ctx, api.AddTargetRequest{
 		Name:  "test-target",
 		URL:   "http://localhost:8000",
 		Model: "test-model",
@@ -119,7 +122,7 @@ func TestSchedulerStateWithTargets(t *testing.T) {
 	ctx := context.Background()
 
 	// Add a target
-	err := manager.AddTarget(ctx, api.AddTargetRequest{
+	_, err := manager.AddTarget(ctx, api.AddTargetRequest{
 		Name:  "test-target",
 		URL:   "http://localhost:8000",
 		Model: "test-model",
@@ -160,3 +163,310 @@ func TestSchedulerStateWithTargets(t *testing.T) {
 		t.Error("expected NextScheduledRun to be set when running")
 	}
 }
+
+// TestTriggerRunPausesOnlyItsTarget verifies that a manual run pauses just
+// its own target's schedule, and only if the target runs on one
+func TestTriggerRunPausesOnlyItsTarget(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+
+	for _, name := range []string{"manual-only", "scheduled", "other"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	paused := func(name string) bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return manager.schedulePaused(manager.targets[name], time.Now())
+	}
+
+	// Trigger-only: nothing is scheduled, so nothing to pause
+	manager.TriggerRun(ctx, "manual-only", "run-1", nil)
+	if paused("manual-only") {
+		t.Error("expected a target without a schedule not to be paused")
+	}
+
+	for _, name := range []string{"scheduled", "other"} {
+		if err := manager.StartTarget(ctx, name); err != nil {
+			t.Fatalf("failed to start target: %v", err)
+		}
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	// A manual run of a scheduled target pauses its schedule alone
+	manager.TriggerRun(ctx, "scheduled", "run-2", nil)
+	if !paused("scheduled") {
+		t.Error("expected the triggered target's schedule to be paused")
+	}
+	if paused("other") {
+		t.Error("expected other targets to keep their schedules")
+	}
+	if status := manager.GetSchedulerStatus(); status.State != api.SchedulerStateRunning {
+		t.Errorf("expected the scheduler to keep running, got %s", status.State)
+	}
+	if target, _ := manager.GetTarget("scheduled"); target.NextRunAt != nil {
+		t.Errorf("expected no next run while paused, got %v", target.NextRunAt)
+	}
+
+	// The pause lifts by itself once it expires
+	manager.mu.Lock()
+	expired := time.Now().Add(-time.Second)
+	manager.targets["scheduled"].pauseUntil = &expired
+	manager.mu.Unlock()
+	if paused("scheduled") {
+		t.Error("expected the schedule to resume after the pause")
+	}
+}
+
+// TestTargetNextRunAt verifies that a running target reports its own next
+// run, and none while stopped or while the scheduler is paused
+func TestTargetNextRunAt(t *testing.T) {
+	manager := newTestManager(t)
+	runOnStart := false
+	manager.cfg.Defaults.RunOnStart = &runOnStart
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "countdown",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	if target, _ := manager.GetTarget("countdown"); target.NextRunAt != nil {
+		t.Errorf("expected no next run while stopped, got %v", target.NextRunAt)
+	}
+
+	before := time.Now()
+	if err := manager.StartTarget(ctx, "countdown"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+	var next *time.Time
+	deadline := time.Now().Add(5 * time.Second)
+	for next == nil && time.Now().Before(deadline) {
+		target, _ := manager.GetTarget("countdown")
+		next = target.NextRunAt
+		time.Sleep(time.Millisecond)
+	}
+	if next == nil || next.Before(before) || next.After(before.Add(manager.cfg.GetInterval())) {
+		t.Fatalf("expected the next run within an interval of starting, got %v", next)
+	}
+	if status := manager.GetSchedulerStatus(); status.NextScheduledRun == nil || !status.NextScheduledRun.Equal(*next) {
+		t.Errorf("expected the scheduler's next run to be the target's, got %v", status.NextScheduledRun)
+	}
+
+	if err := manager.PauseScheduler(); err != nil {
+		t.Fatalf("failed to pause scheduler: %v", err)
+	}
+	if target, _ := manager.GetTarget("countdown"); target.NextRunAt != nil {
+		t.Errorf("expected no next run while paused, got %v", target.NextRunAt)
+	}
+	manager.ResumeScheduler()
+
+	if err := manager.StopTarget("countdown"); err != nil {
+		t.Fatalf("failed to stop target: %v", err)
+	}
+	if target, _ := manager.GetTarget("countdown"); target.NextRunAt != nil {
+		t.Errorf("expected no next run after stopping, got %v", target.NextRunAt)
+	}
+}
+
+// TestPauseTarget verifies that pausing a target holds its scheduled runs
+// alone until it is resumed
+func TestPauseTarget(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	for _, name := range []string{"paused", "other"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	if _, err := manager.PauseTarget("missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown target, got %v", err)
+	}
+	target, err := manager.PauseTarget("paused")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Pause == nil || target.Pause.Reason != api.PauseReasonAPI || target.Pause.Until != nil {
+		t.Fatalf("expected an indefinite API pause, got %+v", target.Pause)
+	}
+
+	// The paused target skips its immediate first run; the other doesn't
+	for _, name := range []string{"paused", "other"} {
+		if err := manager.StartTarget(ctx, name); err != nil {
+			t.Fatalf("failed to start target: %v", err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if other, _ := manager.GetTarget("other"); other.LastRunAt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the unpaused target to run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if target, _ := manager.GetTarget("paused"); target.LastRunAt != nil || target.NextRunAt != nil {
+		t.Errorf("expected the paused target not to run or be scheduled, got %+v", target)
+	}
+
+	// A manual run doesn't turn the pause into a timed one
+	manager.TriggerRun(ctx, "paused", "run-1", nil)
+	if target, _ := manager.GetTarget("paused"); target.Pause == nil || target.Pause.Until != nil {
+		t.Errorf("expected the pause to outlast the manual run, got %+v", target.Pause)
+	}
+
+	target, err = manager.ResumeTarget("paused")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Pause != nil || target.NextRunAt == nil {
+		t.Errorf("expected the target to be scheduled again, got %+v", target)
+	}
+}
+
+// TestMaxRuns verifies that a target with max_runs stops itself after that
+// many scheduled runs, counting down its remaining runs, and that starting
+// it again resets the count
+func TestMaxRuns(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Defaults.Interval = 1
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	maxRuns := 2
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:    "one-shot",
+		URL:     "http://localhost:8000",
+		Model:   "test-model",
+		MaxRuns: &maxRuns,
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	if target, _ := manager.GetTarget("one-shot"); target.RemainingRuns == nil || *target.RemainingRuns != 2 {
+		t.Errorf("expected 2 remaining runs before starting, got %v", target.RemainingRuns)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := manager.StartTarget(ctx, "one-shot"); err != nil {
+			t.Fatalf("failed to start target: %v", err)
+		}
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			target, _ := manager.GetTarget("one-shot")
+			if target.Status == api.TargetStatusStopped {
+				if *target.RemainingRuns != 0 {
+					t.Errorf("expected no remaining runs once stopped, got %d", *target.RemainingRuns)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("target didn't stop after max_runs (remaining %v)", target.RemainingRuns)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if runs := manager.ListRuns("one-shot", ""); len(runs) != 2*attempt {
+			t.Errorf("expected %d runs after start %d, got %d", 2*attempt, attempt, len(runs))
+		}
+	}
+
+	zero := 0
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "never", URL: "http://localhost:8000", Model: "test-model", MaxRuns: &zero,
+	}); err == nil {
+		t.Error("expected max_runs of 0 to be rejected")
+	}
+}
+
+// TestRunOnce verifies that RunOnce runs each target exactly once, straight
+// away whatever the interval, and reports the targets whose run failed
+func TestRunOnce(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, `case "$*" in
+  *broken-model*) exit 1 ;;
+esac
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --output-dir) dir=$2 ;;
+  esac
+  shift
+done
+echo '{"benchmarks": [{"scheduler_state": {"created_requests": 1, "successful_requests": 1}}]}' > "$dir/benchmarks.json"`)
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	for _, name := range []string{"healthy", "broken"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:    name,
+			URL:     "http://localhost:8000",
+			Model:   name + "-model",
+			MaxRuns: intPtr(5),
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.RunOnce(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("RunOnce didn't return")
+	}
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 targets failed: broken") {
+		t.Errorf("expected the broken target reported, got %v", err)
+	}
+	for _, name := range []string{"healthy", "broken"} {
+		if runs := manager.ListRuns(name, ""); len(runs) != 1 {
+			t.Errorf("expected 1 run of %s, got %d", name, len(runs))
+		}
+		if target, _ := manager.GetTarget(name); target.Status != api.TargetStatusStopped {
+			t.Errorf("expected %s stopped after its run, got %s", name, target.Status)
+		}
+	}
+
+	if err := manager.RemoveTarget("broken"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	if err := manager.RunOnce(ctx); err != nil {
+		t.Errorf("expected the healthy target alone to succeed, got %v", err)
+	}
+}
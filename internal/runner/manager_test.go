@@ -0,0 +1,1926 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/discovery"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+)
+
+// newTestManager creates a manager with no runner attached, so started
+// targets loop without spawning guidellm
+func newTestManager(t *testing.T) *DefaultTargetManager {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1.0,
+			Interval:    300,
+			MaxSeconds:  60,
+			RequestType: "text_completions",
+		},
+	}
+	return NewTargetManager(cfg, logger)
+}
+
+// TestConcurrentRemoveAndStart interleaves RemoveTarget and StartTarget on the
+// same target. Run with -race to catch unsynchronized access.
+func TestConcurrentRemoveAndStart(t *testing.T) {
+	ctx := context.Background()
+
+	for i := 0; i < 50; i++ {
+		manager := newTestManager(t)
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  "test-target",
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		var startErr, removeErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			startErr = manager.StartTarget(ctx, "test-target")
+		}()
+		go func() {
+			defer wg.Done()
+			removeErr = manager.RemoveTarget("test-target")
+		}()
+		wg.Wait()
+
+		// The target existed before either call, so removal always succeeds
+		if removeErr != nil {
+			t.Fatalf("iteration %d: unexpected remove error: %v", i, removeErr)
+		}
+		// Start either won the race or reports the target as not found
+		if startErr != nil && !errors.Is(startErr, api.ErrNotFound) {
+			t.Fatalf("iteration %d: unexpected start error: %v", i, startErr)
+		}
+
+		if _, ok := manager.GetTarget("test-target"); ok {
+			t.Fatalf("iteration %d: target still present after remove", i)
+		}
+		if err := manager.StopTarget("test-target"); !errors.Is(err, api.ErrNotFound) {
+			t.Fatalf("iteration %d: expected not found from stop, got %v", i, err)
+		}
+
+		// Any loop started before the remove must have been cancelled by it
+		manager.Wait()
+	}
+}
+
+// TestRestartDoesNotMarkNewLoopStopped verifies that a loop exiting after a
+// stop doesn't clobber the status of a loop started right after it
+func TestRestartDoesNotMarkNewLoopStopped(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := manager.StartTarget(ctx, "test-target"); err != nil {
+			t.Fatalf("failed to start target: %v", err)
+		}
+		if err := manager.StopTarget("test-target"); err != nil {
+			t.Fatalf("failed to stop target: %v", err)
+		}
+	}
+	if err := manager.StartTarget(ctx, "test-target"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+
+	// Give the cancelled loops time to observe cancellation and exit
+	time.Sleep(50 * time.Millisecond)
+
+	target, ok := manager.GetTarget("test-target")
+	if !ok {
+		t.Fatal("expected target to exist")
+	}
+	if target.Status != api.TargetStatusRunning {
+		t.Errorf("expected running after restart, got %s", target.Status)
+	}
+
+	manager.StopAll()
+	manager.Wait()
+
+	target, _ = manager.GetTarget("test-target")
+	if target.Status != api.TargetStatusStopped {
+		t.Errorf("expected stopped after StopAll, got %s", target.Status)
+	}
+}
+
+// TestBackgroundDiscoveryOverlapsStartup verifies that configured targets are
+// started while discovery is still in flight, and that discovered targets are
+// started once they arrive without displacing configured ones
+func TestBackgroundDiscoveryOverlapsStartup(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(discovery.ModelsResponse{
+			Object: "list",
+			Data: []discovery.ModelInfo{
+				{ID: "configured", ModelType: "text"},
+				{ID: "org/discovered", ModelType: "text"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	manager.cfg.Environments = map[string]config.Environment{
+		"static": {Targets: []config.Target{
+			{Name: "configured", URL: "http://static.local/v1", Model: "configured"},
+		}},
+	}
+	manager.cfg.Discovery = config.DiscoveryConfig{
+		Enabled:    true,
+		Background: true,
+		Environments: map[string]config.DiscoveryEnvConfig{
+			"found": {Endpoint: server.URL + "/v1/models", BaseURL: server.URL + "/v1"},
+		},
+	}
+
+	ctx := context.Background()
+	if err := manager.LoadFromConfig(); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	manager.StartAllConfigured(ctx)
+	done := manager.StartDiscovery(ctx, true)
+
+	// Configured target is running while discovery is still blocked
+	target, ok := manager.GetTarget("configured")
+	if !ok || target.Status != api.TargetStatusRunning {
+		t.Fatalf("expected configured target running during discovery, got %+v", target)
+	}
+	if _, ok := manager.GetTarget("org-discovered"); ok {
+		t.Fatal("discovered target added before discovery finished")
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background discovery did not finish")
+	}
+
+	discovered, ok := manager.GetTarget("org-discovered")
+	if !ok {
+		t.Fatal("expected discovered target to be added")
+	}
+	if discovered.Status != api.TargetStatusRunning {
+		t.Errorf("expected discovered target running, got %s", discovered.Status)
+	}
+
+	// The configured target wins the name collision and isn't double-added
+	target, _ = manager.GetTarget("configured")
+	if target.Environment != "static" {
+		t.Errorf("expected configured target to keep environment static, got %s", target.Environment)
+	}
+	if got := len(listAll(manager)); got != 2 {
+		t.Errorf("expected 2 targets, got %d", got)
+	}
+
+	manager.StopAll()
+	manager.Wait()
+}
+
+// TestOverrideExpiresAndReverts verifies that an override applies to the
+// target's runs until it expires, after which its configured settings return
+func TestOverrideExpiresAndReverts(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	rate := 5.0
+	resp, err := manager.SetOverride("test-target", api.OverrideRequest{
+		Rate:            &rate,
+		Profile:         "poisson",
+		DurationSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("failed to set override: %v", err)
+	}
+	if resp.Override == nil || resp.Rate != 5.0 || resp.Profile != "poisson" {
+		t.Fatalf("expected override reflected in response, got %+v", resp)
+	}
+	if resp.MaxSeconds != 60 {
+		t.Errorf("expected max_seconds to keep its default, got %d", resp.MaxSeconds)
+	}
+
+	mt := manager.targets["test-target"]
+	logger := manager.logger
+	target := manager.scheduledTarget(mt, logger)
+	if target.GetRate(manager.cfg.Defaults) != 5.0 || target.GetProfile(manager.cfg.Defaults) != "poisson" {
+		t.Fatalf("expected scheduled run to use override, got rate %v profile %s",
+			target.GetRate(manager.cfg.Defaults), target.GetProfile(manager.cfg.Defaults))
+	}
+
+	// Expire the override
+	manager.mu.Lock()
+	mt.override.ExpiresAt = time.Now().Add(-time.Second)
+	manager.mu.Unlock()
+
+	got, _ := manager.GetTarget("test-target")
+	if got.Override != nil || got.Rate != 1.0 || got.Profile != "constant" {
+		t.Errorf("expected expired override to be hidden, got %+v", got)
+	}
+
+	target = manager.scheduledTarget(mt, logger)
+	if target.GetRate(manager.cfg.Defaults) != 1.0 || target.GetProfile(manager.cfg.Defaults) != "constant" {
+		t.Errorf("expected scheduled run to revert, got rate %v profile %s",
+			target.GetRate(manager.cfg.Defaults), target.GetProfile(manager.cfg.Defaults))
+	}
+	if mt.override != nil {
+		t.Error("expected expired override to be dropped")
+	}
+
+	if _, err := manager.SetOverride("test-target", api.OverrideRequest{Rate: &rate}); err == nil {
+		t.Error("expected error for missing duration")
+	}
+	if _, err := manager.SetOverride("missing", api.OverrideRequest{Rate: &rate, DurationSeconds: 1}); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := manager.SetOverride("test-target", api.OverrideRequest{Profile: "bursty", DurationSeconds: 60}); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestAddTargetRestrictEnvironments(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+	manager.cfg.Environments = map[string]config.Environment{"develop": {}}
+	manager.cfg.Discovery.Environments = map[string]config.DiscoveryEnvConfig{"staging": {}}
+
+	// Permissive by default: unknown environments are created implicitly
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "adhoc", URL: "http://localhost:8000", Model: "m", Environment: "adhoc-env",
+	}); err != nil {
+		t.Fatalf("expected unknown environment to be accepted by default: %v", err)
+	}
+
+	manager.cfg.API.RestrictEnvironments = true
+
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "rejected", URL: "http://localhost:8000", Model: "m", Environment: "production",
+	}); err == nil {
+		t.Error("expected unknown environment to be rejected")
+	}
+	if _, ok := manager.GetTarget("rejected"); ok {
+		t.Error("rejected target should not be registered")
+	}
+
+	for _, env := range []string{"develop", "staging"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name: "in-" + env, URL: "http://localhost:8000", Model: "m", Environment: env,
+		}); err != nil {
+			t.Errorf("expected configured environment %s to be accepted: %v", env, err)
+		}
+	}
+}
+
+func TestSubscribeResults(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	if _, _, err := manager.SubscribeResults("missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	results, unsubscribe, err := manager.SubscribeResults("test-target")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	// Only the latest results are kept for a subscriber that hasn't read yet
+	mt := manager.targets["test-target"]
+	manager.mu.Lock()
+	manager.recordRun(mt, &runOutput{results: &parser.ParsedResults{TotalRequests: 1}}, nil)
+	manager.recordRun(mt, nil, errors.New("failed"))
+	manager.recordRun(mt, &runOutput{results: &parser.ParsedResults{TotalRequests: 2}}, nil)
+	manager.mu.Unlock()
+
+	select {
+	case got := <-results:
+		if got.TotalRequests != 2 {
+			t.Errorf("expected latest results, got %d requests", got.TotalRequests)
+		}
+	default:
+		t.Fatal("expected results to be published")
+	}
+
+	unsubscribe()
+	if _, ok := <-results; ok {
+		t.Error("expected channel closed after unsubscribe")
+	}
+	unsubscribe() // idempotent
+
+	// Removing the target ends remaining streams
+	results, unsubscribe, _ = manager.SubscribeResults("test-target")
+	if err := manager.RemoveTarget("test-target"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	if _, ok := <-results; ok {
+		t.Error("expected channel closed after target removal")
+	}
+	unsubscribe()
+	if len(manager.subscribers) != 0 {
+		t.Errorf("expected no subscribers left, got %d", len(manager.subscribers))
+	}
+}
+
+// TestLatestResultsReportsInFlightRun verifies the results response flags a
+// run in progress and reports when the last run finished
+func TestLatestResultsReportsInFlightRun(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exec sleep 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	resp, err := manager.GetLatestResults("test-target")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.IsRunning || resp.LastRunAt != nil {
+		t.Fatalf("expected idle target with no runs, got %+v", resp)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		manager.TriggerRun(context.Background(), "test-target", "run-1", nil)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ = manager.GetLatestResults("test-target")
+		if resp.IsRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected is_running during the run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	<-done
+	resp, _ = manager.GetLatestResults("test-target")
+	if resp.IsRunning {
+		t.Error("expected is_running to clear after the run")
+	}
+	if resp.LastRunAt == nil {
+		t.Error("expected last_run_at after the run")
+	}
+
+	manager.ResumeScheduler()
+}
+
+func TestStartAllConfiguredRetriesFailedStart(t *testing.T) {
+	manager := newTestManager(t)
+	manager.startRetryAttempts = 3
+	manager.startRetryBackoff = time.Millisecond
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "flaky-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	var mu sync.Mutex
+	attempts := 0
+	manager.startFn = func(ctx context.Context, name string) error {
+		mu.Lock()
+		attempts++
+		n := attempts
+		mu.Unlock()
+		if n == 1 {
+			return errors.New("dependency unavailable")
+		}
+		return manager.StartTarget(ctx, name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager.StartAllConfigured(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if target, _ := manager.GetTarget("flaky-target"); target.Status == api.TargetStatusRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("target was not started on retry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	if attempts != 2 {
+		t.Errorf("expected start to succeed on the second attempt, got %d attempts", attempts)
+	}
+	mu.Unlock()
+
+	manager.StopAll()
+	manager.Wait()
+}
+
+// TestStartAllConfiguredStaggers verifies that a startup stagger spaces out
+// target starts in the background, and that cancelling stops the rest
+func TestStartAllConfiguredStaggers(t *testing.T) {
+	manager := newTestManager(t)
+	manager.startStagger = 50 * time.Millisecond
+	for _, name := range []string{"c", "a", "b"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	started := make(chan string, 3)
+	manager.startFn = func(ctx context.Context, name string) error {
+		started <- name
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	begin := time.Now()
+	manager.StartAllConfigured(ctx)
+	if name := <-started; name != "a" {
+		t.Errorf("expected targets to start in name order, got %s first", name)
+	}
+	if name := <-started; name != "b" || time.Since(begin) < manager.startStagger {
+		t.Errorf("expected b to start after the stagger, got %s after %v", name, time.Since(begin))
+	}
+
+	cancel()
+	manager.Wait()
+	select {
+	case name := <-started:
+		t.Errorf("expected no starts after cancellation, got %s", name)
+	default:
+	}
+}
+
+// TestStartAllConfiguredSkipsAutoStartOff verifies that targets with
+// auto_start off are left stopped, and report it
+func TestStartAllConfiguredSkipsAutoStartOff(t *testing.T) {
+	manager := newTestManager(t)
+	for _, name := range []string{"regular", "stress"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	autoStart := false
+	manager.targets["stress"].target.AutoStart = &autoStart
+
+	var started []string
+	manager.startFn = func(ctx context.Context, name string) error {
+		started = append(started, name)
+		return nil
+	}
+	manager.StartAllConfigured(context.Background())
+	if !slices.Equal(started, []string{"regular"}) {
+		t.Errorf("expected only the regular target started, got %v", started)
+	}
+
+	for name, want := range map[string]bool{"regular": true, "stress": false} {
+		if target, _ := manager.GetTarget(name); target.AutoStart != want {
+			t.Errorf("expected %s to report auto_start %v, got %v", name, want, target.AutoStart)
+		}
+	}
+}
+
+// TestAddTargetReportsAllInvalidFields verifies that AddTarget reports every
+// invalid field at once, and a single one with its usual message
+func TestAddTargetReportsAllInvalidFields(t *testing.T) {
+	manager := newTestManager(t)
+	_, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:          "broken",
+		URL:           "htp://localhost:8000/v1",
+		Rate:          floatPtr(-1),
+		MaxSeconds:    intPtr(0),
+		ProbeTimeout:  intPtr(0),
+		WarmupRuns:    intPtr(-1),
+		MaxRuns:       intPtr(0),
+		BackendKwargs: map[string]interface{}{"timeout": func() {}},
+	})
+	var verr *api.ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, api.ErrInvalid) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	fields := []string{"url", "model", "rate", "max_seconds", "probe_timeout", "warmup_runs", "max_runs", "backend_kwargs"}
+	for _, field := range fields {
+		if verr.Fields[field] == "" {
+			t.Errorf("expected %s reported, got %v", field, verr.Fields)
+		}
+	}
+	if len(verr.Fields) != len(fields) {
+		t.Errorf("expected only the %d invalid fields reported, got %v", len(fields), verr.Fields)
+	}
+
+	_, err = manager.AddTarget(context.Background(), api.AddTargetRequest{Name: "no-model", URL: "http://localhost:8000"})
+	if err == nil || err.Error() != "model is required" {
+		t.Errorf("expected just the missing model reported, got %v", err)
+	}
+}
+
+func TestAddTargetValidatesURL(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "typo", URL: "htp://localhost:8000/v1", Model: "m",
+	}); err == nil {
+		t.Error("expected non-http url to be rejected")
+	}
+
+	// With probing enabled the endpoint must answer
+	manager.cfg.API.ProbeTargets = true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("expected probe of /v1/models, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized) // still proves the server is there
+	}))
+	defer server.Close()
+
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "reachable", URL: server.URL + "/v1/chat/completions", Model: "m",
+	}); err != nil {
+		t.Errorf("expected reachable target to be accepted: %v", err)
+	}
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "unreachable", URL: unreachable.URL + "/v1", Model: "m",
+	}); err == nil {
+		t.Error("expected unreachable target to be rejected")
+	}
+	if _, ok := manager.GetTarget("unreachable"); ok {
+		t.Error("rejected target should not be registered")
+	}
+}
+
+func TestAddTargetValidatesProfile(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+
+	_, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "typo", URL: "http://localhost:8000", Model: "m", Profile: "constnat",
+	})
+	if err == nil || !strings.Contains(err.Error(), "poisson") {
+		t.Errorf("expected unknown profile to be rejected with the valid profiles, got %v", err)
+	}
+
+	// guidellm_profiles admits profiles the runner doesn't know of
+	manager.cfg.GuideLLMProfiles = append(slices.Clone(config.ValidProfiles), "bursty")
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "bursty", URL: "http://localhost:8000", Model: "m", Profile: "bursty",
+	}); err != nil {
+		t.Errorf("expected configured profile to be accepted: %v", err)
+	}
+
+	// Rate-less profiles take no rate
+	rate := 5.0
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name: "throughput", URL: "http://localhost:8000", Model: "m", Profile: "throughput", Rate: &rate,
+	}); err == nil {
+		t.Error("expected a rate for the throughput profile to be rejected")
+	}
+	if _, err := manager.SetOverride("bursty", api.OverrideRequest{Profile: "synchronous", Rate: &rate, DurationSeconds: 60}); err == nil {
+		t.Error("expected an override rate for the synchronous profile to be rejected")
+	}
+}
+
+// TestRemoveDuringRunLeavesNoSeries removes a target while its run is in
+// flight and checks the run's final metric writes don't resurrect its
+// series. Run with -race.
+func TestRemoveDuringRunLeavesNoSeries(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exec sleep 5")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:        "removed-mid-run",
+		URL:         "http://localhost:8000",
+		Model:       "test-model",
+		Environment: "series-test",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	if err := manager.StartTarget(ctx, "removed-mid-run"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ := manager.GetLatestResults("removed-mid-run")
+		if resp.IsRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := manager.RemoveTarget("removed-mid-run"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	manager.Wait()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "target" && label.GetValue() == "removed-mid-run" {
+					t.Errorf("leftover series %s for removed target", family.GetName())
+				}
+			}
+		}
+	}
+}
+
+// TestDrain verifies that a graceful shutdown lets runs in flight finish
+// within the grace period, then cancels the rest
+func TestDrain(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, `case "$*" in
+  *slow-model*) exec sleep 30 ;;
+  *) sleep 1; exit 1 ;;
+esac`)
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	for _, name := range []string{"quick", "slow"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: name + "-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+		if err := manager.StartTarget(ctx, name); err != nil {
+			t.Fatalf("failed to start target: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for manager.runsInFlight() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("runs never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	start := time.Now()
+	drained, killed := manager.Drain(ctx, 3*time.Second)
+	if drained != 1 || killed != 1 {
+		t.Errorf("expected 1 run drained and 1 killed, got %d and %d", drained, killed)
+	}
+	manager.Wait()
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("shutdown took %s, expected the slow run to be cut off after the grace period", elapsed)
+	}
+
+	quick, _ := manager.GetTarget("quick")
+	if quick.LastError == "" || quick.Status != api.TargetStatusStopped {
+		t.Errorf("expected the quick run recorded and its target stopped, got %+v", quick)
+	}
+	if runs := manager.ListRuns("quick", ""); len(runs) != 1 {
+		t.Errorf("expected the drained loop not to schedule another run, got %d runs", len(runs))
+	}
+}
+
+// TestStopAllCancelsManualRuns verifies that shutdown cancels manual runs in
+// flight, which callers may have detached from their own cancellation, and
+// waits for them
+func TestStopAllCancelsManualRuns(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exec sleep 30")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "manual",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := manager.TriggerRun(context.WithoutCancel(ctx), "manual", "run-1", nil)
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ := manager.GetLatestResults("manual")
+		if resp.IsRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	manager.StopAll()
+	manager.Wait()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the cancelled run to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("manual run not cancelled by StopAll")
+	}
+}
+
+// TestStartRun verifies that an async run is registered as pending and can
+// be polled until it finishes
+func TestStartRun(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "echo 'something broke' >&2; exit 3")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "async",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	if _, err := manager.StartRun(ctx, "missing", "", nil); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown target, got %v", err)
+	}
+	if _, err := manager.GetRun("missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown run, got %v", err)
+	}
+
+	run, err := manager.StartRun(ctx, "async", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.RunID == "" || run.Status != api.RunStatusPending {
+		t.Fatalf("expected a pending run with an ID, got %+v", run)
+	}
+	if _, err := manager.StartRun(ctx, "async", run.RunID, nil); err == nil {
+		t.Error("expected an error reusing a run ID")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := manager.GetRun(run.RunID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Status == api.RunStatusFailed {
+			if got.Error == "" || got.StartedAt == nil || got.FinishedAt == nil {
+				t.Errorf("expected error and timestamps on the failed run, got %+v", got)
+			}
+			break
+		}
+		if got.Status == api.RunStatusDone {
+			t.Fatalf("expected the run to fail, got %+v", got)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run never finished, last status %s", got.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestRunRegistry verifies that scheduled and manual runs are registered,
+// listed newest first and evicted past the retention count
+func TestRunRegistry(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "exit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	manager.runs = newRunRegistry(2)
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "registry",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	manager.TriggerRun(ctx, "registry", "run-1", nil)
+	if _, err := manager.TriggerRun(ctx, "registry", "run-1", nil); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("expected a conflict reusing a run ID, got %v", err)
+	}
+	mt := manager.targets["registry"]
+	manager.runBenchmarkWithCallback(ctx, mt.environment, mt.target, manager.logger, mt)
+	manager.TriggerRun(ctx, "registry", "run-3", nil)
+
+	runs := manager.ListRuns("", "")
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 retained runs, got %d", len(runs))
+	}
+	if runs[0].RunID != "run-3" || runs[0].Trigger != "manual" {
+		t.Errorf("expected the newest run to be manual run-3, got %s (%s)", runs[0].RunID, runs[0].Trigger)
+	}
+	if runs[1].Trigger != "scheduled" || runs[1].RunID == "" {
+		t.Errorf("expected a scheduled run with a generated ID, got %+v", runs[1])
+	}
+	for _, run := range runs {
+		if run.Status != api.RunStatusFailed || run.Error == "" || run.FinishedAt == nil {
+			t.Errorf("expected run %s to have failed, got %+v", run.RunID, run)
+		}
+	}
+	if _, err := manager.GetRun("run-1"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected the oldest run to be evicted, got %v", err)
+	}
+	if got := manager.ListRuns("other", ""); len(got) != 0 {
+		t.Errorf("expected no runs for another target, got %d", len(got))
+	}
+}
+
+// TestTriggerRunOverrides verifies that config overrides apply to a single
+// manual run without changing the stored target
+func TestTriggerRunOverrides(t *testing.T) {
+	manager := newTestManager(t)
+	argsFile := filepath.Join(t.TempDir(), "args")
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, `echo "$@" > `+argsFile+"\nexit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	rate := 2.0
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "stress",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+		Rate:  &rate,
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	overrideRate := 50.0
+	maxSeconds := 5
+	overrides := &api.RunOverrides{
+		Rate:       &overrideRate,
+		MaxSeconds: &maxSeconds,
+		Profile:    "constant",
+		DataSpec:   "prompt_tokens=64,output_tokens=32",
+	}
+	manager.TriggerRun(ctx, "stress", "run-1", overrides)
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("guidellm not run: %v", err)
+	}
+	for _, want := range []string{"--rate 50 ", "--max-seconds 5 ", "--profile constant ", "--data prompt_tokens=64,output_tokens=32 "} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("expected %q in guidellm args %q", want, args)
+		}
+	}
+	if got := *manager.targets["stress"].target.Rate; got != rate {
+		t.Errorf("expected the stored rate to stay %g, got %g", rate, got)
+	}
+	if run, _ := manager.GetRun("run-1"); run == nil || run.Overrides == nil || run.Overrides.Profile != "constant" {
+		t.Errorf("expected the run to record its overrides, got %+v", run)
+	}
+
+	zero := 0.0
+	for _, invalid := range []*api.RunOverrides{{Rate: &zero}, {Profile: "bursty"}} {
+		if _, err := manager.TriggerRun(ctx, "stress", "", invalid); !errors.Is(err, api.ErrInvalid) {
+			t.Errorf("expected invalid overrides %+v to be rejected, got %v", invalid, err)
+		}
+	}
+
+	// A rate alone is checked against the target's own profile
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:    "sync",
+		URL:     "http://localhost:8000",
+		Model:   "test-model",
+		Profile: "synchronous",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	rateOnly := &api.RunOverrides{Rate: &overrideRate}
+	if _, err := manager.TriggerRun(ctx, "sync", "", rateOnly); !errors.Is(err, api.ErrInvalid) {
+		t.Errorf("expected a rate for a synchronous target to be rejected, got %v", err)
+	}
+	if _, err := manager.StartRun(ctx, "sync", "", rateOnly); !errors.Is(err, api.ErrInvalid) {
+		t.Errorf("expected a rate for a synchronous target to be rejected, got %v", err)
+	}
+}
+
+// TestSweep verifies that a target's sweep runs each rate in turn and
+// records one set of results per rate
+func TestSweep(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, `while [ $# -gt 0 ]; do
+  case "$1" in
+    --rate) rate=$2 ;;
+    --output-dir) dir=$2 ;;
+  esac
+  shift
+done
+echo '{"benchmarks": [{"scheduler_state": {"created_requests": '$rate', "successful_requests": '$rate'}}]}' > "$dir/benchmarks.json"`)
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "sweep",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	rates := []float64{1, 5, 10}
+	mt := manager.targets["sweep"]
+	mt.target.SweepLabels = true
+	for _, rate := range rates {
+		mt.target.Sweep = append(mt.target.Sweep, config.SweepStep{Rate: &rate})
+	}
+	manager.runCycle(ctx, mt.environment, mt.target, manager.logger, mt)
+
+	resp, err := manager.GetLatestResults("sweep")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Sweep) != len(rates) {
+		t.Fatalf("expected %d sweep runs, got %d", len(rates), len(resp.Sweep))
+	}
+	for i, run := range resp.Sweep {
+		if run.Rate != rates[i] {
+			t.Errorf("expected step %d at rate %g, got %g", i+1, rates[i], run.Rate)
+		}
+		if run.Results == nil || run.Results.TotalRequests != int(rates[i]) {
+			t.Errorf("expected step %d to have its own results, got %+v", i+1, run.Results)
+		}
+	}
+	if runs := manager.ListRuns("sweep", ""); len(runs) != len(rates) {
+		t.Errorf("expected %d registered runs, got %d", len(rates), len(runs))
+	}
+
+	// With sweep_labels each rate has its own series
+	labels := metrics.Labels(mt.environment, "sweep", "test-model")
+	for _, run := range resp.Sweep {
+		runLabels := metrics.RunLabels(labels, fmt.Sprintf("%g", run.Rate), run.Profile)
+		if got := testutil.ToFloat64(metrics.RequestsTotal.With(runLabels)); got != run.Rate {
+			t.Errorf("expected %g requests at rate %g, got %g", run.Rate, run.Rate, got)
+		}
+	}
+}
+
+func TestAddTargetProbeTimeout(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.API.ProbeTargets = true
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	timeout := 1
+	start := time.Now()
+	_, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:         "slow",
+		URL:          slow.URL + "/v1",
+		Model:        "m",
+		ProbeTimeout: &timeout,
+	})
+	if err == nil {
+		t.Fatal("expected probe of slow endpoint to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected probe to give up after ~1s, took %s", elapsed)
+	}
+}
+
+func TestAddTargetProbesCustomHealthPath(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.API.ProbeTargets = true
+
+	var probed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probed = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:       "custom-health",
+		URL:        server.URL + "/v1/chat/completions",
+		Model:      "m",
+		HealthPath: "/healthz",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	if probed != "/healthz" {
+		t.Errorf("expected probe of /healthz, got %q", probed)
+	}
+
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:       "url-health",
+		URL:        server.URL + "/v1",
+		Model:      "m",
+		HealthPath: server.URL + "/healthz",
+	}); err == nil {
+		t.Error("expected a full URL health path to be rejected")
+	}
+}
+
+func TestLoadFromConfigRejectsDuplicateNames(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Environments = map[string]config.Environment{
+		"develop": {Targets: []config.Target{
+			{Name: "gpt-4", URL: "http://dev.local/v1", Model: "gpt-4"},
+			{Name: "llama", URL: "http://dev.local/v1", Model: "llama"},
+		}},
+		"staging": {Targets: []config.Target{
+			{Name: "gpt-4", URL: "http://staging.local/v1", Model: "gpt-4"},
+		}},
+	}
+
+	err := manager.LoadFromConfig()
+	if err == nil {
+		t.Fatal("expected duplicate target names to be rejected")
+	}
+	if !strings.Contains(err.Error(), `"gpt-4" (environments develop, staging)`) {
+		t.Errorf("expected error to name the duplicate and its environments, got: %v", err)
+	}
+	if got := len(listAll(manager)); got != 0 {
+		t.Errorf("expected nothing loaded on error, got %d targets", got)
+	}
+}
+
+func TestGetHistoryPercentiles(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	// Three runs in the window with E2E samples 1..4, 5..8, 9..12, and one
+	// older run that must be excluded
+	now := time.Now()
+	mt := manager.targets["test-target"]
+	mt.history = []historyEntry{
+		{at: now.Add(-3 * time.Hour), results: &parser.ParsedResults{E2EValues: []float64{100, 100, 100, 100}}},
+		{at: now.Add(-40 * time.Minute), results: &parser.ParsedResults{E2EValues: []float64{1, 2, 3, 4}}},
+		{at: now.Add(-20 * time.Minute), results: &parser.ParsedResults{E2EValues: []float64{5, 6, 7, 8}}},
+		{at: now.Add(-time.Minute), results: &parser.ParsedResults{E2EValues: []float64{9, 10, 11, 12}, TTFTValues: []float64{0.5}}},
+	}
+
+	resp, err := manager.GetHistoryPercentiles("test-target", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Runs != 3 {
+		t.Fatalf("expected 3 runs in window, got %d", resp.Runs)
+	}
+	if resp.E2E == nil || resp.E2E.Samples != 12 {
+		t.Fatalf("expected 12 pooled E2E samples, got %+v", resp.E2E)
+	}
+	// Pooled samples 1..12: p50 interpolates between 6 and 7
+	if resp.E2E.P50 != 6.5 {
+		t.Errorf("expected pooled p50 6.5, got %v", resp.E2E.P50)
+	}
+	if resp.E2E.P99 < 11.8 || resp.E2E.P99 > 12 {
+		t.Errorf("expected pooled p99 near 12, got %v", resp.E2E.P99)
+	}
+	if resp.TTFT == nil || resp.TTFT.P50 != 0.5 {
+		t.Errorf("expected TTFT from the one run that had it, got %+v", resp.TTFT)
+	}
+	if resp.ITL != nil {
+		t.Errorf("expected no ITL summary without samples, got %+v", resp.ITL)
+	}
+
+	// Stored history isn't reordered by the merge
+	if mt.history[1].results.E2EValues[0] != 1 {
+		t.Error("history samples were modified")
+	}
+
+	empty, err := manager.GetHistoryPercentiles("test-target", time.Second)
+	if err != nil || empty.Runs != 0 || empty.E2E != nil {
+		t.Errorf("expected empty summary for a window with no runs, got %+v, %v", empty, err)
+	}
+	if _, err := manager.GetHistoryPercentiles("missing", time.Hour); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+// TestHistoryExcludesFailedRuns verifies that a failed run with results,
+// such as one that made no requests, isn't kept in the history
+func TestHistoryExcludesFailedRuns(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "test-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	manager.mu.Lock()
+	mt := manager.targets["test-target"]
+	manager.recordRun(mt, &runOutput{runID: "run-ok", results: &parser.ParsedResults{OutputTokensPerSec: 100}}, nil)
+	manager.recordRun(mt, &runOutput{runID: "run-empty", results: &parser.ParsedResults{}},
+		&RunError{Category: FailureZeroRequests, Err: errors.New("zero requests")})
+	history := mt.history
+	lastResults := mt.lastResults
+	manager.mu.Unlock()
+
+	if len(history) != 1 || history[0].runID != "run-ok" {
+		t.Errorf("expected only the successful run in history, got %+v", history)
+	}
+	if lastResults == nil || lastResults.OutputTokensPerSec != 0 {
+		t.Errorf("expected the failed run's results to still be the latest, got %+v", lastResults)
+	}
+}
+
+// TestTargetExposesLastError verifies that a failed run's error is surfaced
+// on the target and kept after a later cancelled run
+func TestTargetExposesLastError(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "echo 'openai.AuthenticationError: Incorrect API key provided' >&2; exit 1")
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	ctx := context.Background()
+	if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+		Name:  "bad-key",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	if target, _ := manager.GetTarget("bad-key"); target.LastError != "" || target.LastErrorAt != nil {
+		t.Fatalf("expected no error before any run, got %+v", target)
+	}
+
+	if _, err := manager.TriggerRun(ctx, "bad-key", "run-1", nil); err == nil {
+		t.Fatal("expected failed run")
+	}
+	target, _ := manager.GetTarget("bad-key")
+	if !strings.Contains(target.LastError, "unauthorized") || !strings.Contains(target.LastError, "Incorrect API key") {
+		t.Errorf("expected categorized last error with guidellm's message, got %q", target.LastError)
+	}
+	if target.LastErrorAt == nil {
+		t.Error("expected last error time to be set")
+	}
+
+	// A run cancelled before starting doesn't replace the last failure
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	manager.TriggerRun(cancelled, "bad-key", "run-2", nil)
+	if after, _ := manager.GetTarget("bad-key"); after.LastError != target.LastError {
+		t.Errorf("expected last error to survive a cancelled run, got %q", after.LastError)
+	}
+}
+
+// TestFailureStreakDrivesStatus verifies that a running target is reported
+// as degraded, then error, as failures accumulate, and recovers on success
+func TestFailureStreakDrivesStatus(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Defaults.ErrorThreshold = 2
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "flaky",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	mt := manager.targets["flaky"]
+	failed := &RunError{Category: FailureNonzeroExit, Err: errors.New("exit status 1")}
+
+	// Failures on a stopped target count but don't change its status
+	manager.mu.Lock()
+	manager.recordRun(mt, nil, failed)
+	mt.status = api.TargetStatusRunning
+	manager.mu.Unlock()
+
+	steps := []struct {
+		output   *runOutput
+		err      error
+		status   api.TargetStatus
+		failures int
+	}{
+		{nil, failed, api.TargetStatusError, 2},
+		{&runOutput{results: &parser.ParsedResults{TotalRequests: 10, SuccessfulRequests: 10}}, nil, api.TargetStatusRunning, 0},
+		{&runOutput{results: &parser.ParsedResults{}}, &RunError{Category: FailureZeroRequests, Err: errors.New("zero requests")}, api.TargetStatusDegraded, 1},
+		{nil, context.Canceled, api.TargetStatusDegraded, 1},
+	}
+	for i, step := range steps {
+		manager.mu.Lock()
+		manager.recordRun(mt, step.output, step.err)
+		manager.mu.Unlock()
+
+		target, _ := manager.GetTarget("flaky")
+		if target.Status != step.status || target.ConsecutiveFailures != step.failures {
+			t.Errorf("step %d: expected %s with %d failures, got %s with %d", i, step.status, step.failures, target.Status, target.ConsecutiveFailures)
+		}
+		gauge := metrics.ConsecutiveFailures.With(metrics.Labels("dynamic", "flaky", "test-model"))
+		if got := testutil.ToFloat64(gauge); got != float64(step.failures) {
+			t.Errorf("step %d: expected consecutive failures gauge %d, got %v", i, step.failures, got)
+		}
+	}
+}
+
+// TestGetFailuresAggregates verifies that failures are broken down by stage,
+// reason and target, counting only those within the window
+func TestGetFailuresAggregates(t *testing.T) {
+	manager := newTestManager(t)
+	for _, name := range []string{"auth-broken", "empty", "healthy"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	fail := func(category FailureCategory) error {
+		return &RunError{Category: category, Err: errors.New("failed")}
+	}
+	manager.mu.Lock()
+	manager.recordRun(manager.targets["auth-broken"], nil, fail(FailureUnauthorized))
+	manager.recordRun(manager.targets["auth-broken"], nil, fail(FailureUnauthorized))
+	manager.recordRun(manager.targets["auth-broken"], nil, fail(FailureParse))
+	manager.recordRun(manager.targets["empty"], &runOutput{results: &parser.ParsedResults{}}, fail(FailureZeroRequests))
+	manager.recordRun(manager.targets["healthy"], &runOutput{results: &parser.ParsedResults{TotalRequests: 1, SuccessfulRequests: 1}}, nil)
+	manager.recordRun(manager.targets["healthy"], nil, context.Canceled)
+	// An old failure outside the window
+	manager.failures[0].at = time.Now().Add(-2 * time.Hour)
+	manager.mu.Unlock()
+
+	if _, err := manager.GetFailures(0); err == nil {
+		t.Error("expected error for non-positive window")
+	}
+
+	resp, err := manager.GetFailures(time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get failures: %v", err)
+	}
+	if resp.Total != 3 {
+		t.Errorf("expected 3 failures in window, got %d", resp.Total)
+	}
+	wantStages := map[string]int{StageExec: 1, StageParse: 1, StageEmpty: 1}
+	for stage, want := range wantStages {
+		if resp.ByStage[stage] != want {
+			t.Errorf("expected %d %s failures, got %d", want, stage, resp.ByStage[stage])
+		}
+	}
+	if resp.ByReason[string(FailureUnauthorized)] != 1 {
+		t.Errorf("expected 1 unauthorized failure in window, got %d", resp.ByReason[string(FailureUnauthorized)])
+	}
+
+	if len(resp.Targets) != 2 {
+		t.Fatalf("expected failures for 2 targets, got %+v", resp.Targets)
+	}
+	first := resp.Targets[0]
+	if first.Name != "auth-broken" || first.Total != 2 || first.ByStage[StageParse] != 1 {
+		t.Errorf("expected auth-broken first with 2 failures, got %+v", first)
+	}
+	if resp.Targets[1].Name != "empty" || resp.Targets[1].ByReason[string(FailureZeroRequests)] != 1 {
+		t.Errorf("expected empty target's zero-request failure, got %+v", resp.Targets[1])
+	}
+
+	// A wider window includes the old failure
+	resp, _ = manager.GetFailures(3 * time.Hour)
+	if resp.Total != 4 {
+		t.Errorf("expected 4 failures in a 3h window, got %d", resp.Total)
+	}
+}
+
+// TestBulkAction verifies that bulk start/stop reports each target's outcome
+// and that one failure doesn't abort the batch
+func TestBulkAction(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	for _, name := range []string{"b", "a", "c"} {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	if _, err := manager.BulkAction(ctx, "restart", nil); err == nil {
+		t.Error("expected error for unknown action")
+	}
+
+	if err := manager.StartTarget(ctx, "b"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+	resp, err := manager.BulkAction(ctx, api.BulkActionStart, []string{"a", "missing", "b"})
+	if err != nil {
+		t.Fatalf("bulk start failed: %v", err)
+	}
+	if resp.Succeeded != 2 || resp.Failed != 1 || len(resp.Results) != 3 {
+		t.Fatalf("expected 2 succeeded and 1 failed, got %+v", resp)
+	}
+	if r := resp.Results[1]; r.Name != "missing" || !strings.Contains(r.Error, "not found") {
+		t.Errorf("expected not found error for missing target, got %+v", r)
+	}
+	if r := resp.Results[2]; r.Status != api.TargetStatusRunning || r.Message != "already running" {
+		t.Errorf("expected already-running target to succeed, got %+v", r)
+	}
+	if target, _ := manager.GetTarget("c"); target.Status != api.TargetStatusStopped {
+		t.Errorf("expected unlisted target to stay stopped, got %s", target.Status)
+	}
+
+	// With no names every target is acted on, in name order
+	resp, err = manager.BulkAction(ctx, api.BulkActionStop, nil)
+	if err != nil {
+		t.Fatalf("bulk stop failed: %v", err)
+	}
+	if resp.Succeeded != 3 || resp.Failed != 0 {
+		t.Errorf("expected all 3 to stop, got %+v", resp)
+	}
+	var names []string
+	for _, r := range resp.Results {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("expected targets in name order, got %v", names)
+	}
+	for _, target := range listAll(manager) {
+		if target.Status != api.TargetStatusStopped {
+			t.Errorf("expected %s stopped, got %s", target.Name, target.Status)
+		}
+	}
+}
+
+// TestGroupAction verifies that group actions and summaries resolve the
+// group's members when used, following targets as they are added
+func TestGroupAction(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Groups = map[string]config.Group{
+		"llama": {Targets: []string{"llama-8b"}, Tags: map[string]string{"family": "llama"}},
+	}
+	ctx := context.Background()
+	add := func(name string, tags map[string]string) {
+		if _, err := manager.AddTarget(ctx, api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+			Tags:  tags,
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	add("llama-8b", nil)
+	add("mistral", map[string]string{"family": "mistral"})
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	if _, err := manager.GroupAction(ctx, "missing", api.BulkActionStart); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown group, got %v", err)
+	}
+
+	// Targets added after the group was configured join it
+	add("llama-70b", map[string]string{"family": "llama"})
+	resp, err := manager.GroupAction(ctx, "llama", api.BulkActionStart)
+	if err != nil {
+		t.Fatalf("group start failed: %v", err)
+	}
+	if resp.Succeeded != 2 || resp.Results[0].Name != "llama-70b" || resp.Results[1].Name != "llama-8b" {
+		t.Fatalf("expected both llama targets to start, got %+v", resp)
+	}
+	if target, _ := manager.GetTarget("mistral"); target.Status != api.TargetStatusStopped {
+		t.Errorf("expected a target outside the group to stay stopped, got %s", target.Status)
+	}
+
+	mt := manager.targets["llama-8b"]
+	manager.mu.Lock()
+	manager.recordRun(mt, &runOutput{results: &parser.ParsedResults{TotalRequests: 1, SuccessfulRequests: 1, OutputTokensPerSec: 40}}, nil)
+	manager.mu.Unlock()
+	summary, err := manager.GetGroupSummary("llama")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(summary.Targets) != 2 || summary.OutputTokensPerSec != 40 || summary.ReportingTargets != 1 {
+		t.Errorf("expected 2 targets with one reporting 40 tok/s, got %+v", summary)
+	}
+
+	// A group whose targets are all gone acts on nothing, not everything
+	if err := manager.RemoveTarget("llama-70b"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	if err := manager.RemoveTarget("llama-8b"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	resp, err = manager.GroupAction(ctx, "llama", api.BulkActionStart)
+	if err != nil || len(resp.Results) != 0 {
+		t.Errorf("expected an empty group to act on no targets, got %+v (err %v)", resp, err)
+	}
+	if target, _ := manager.GetTarget("mistral"); target.Status != api.TargetStatusStopped {
+		t.Errorf("expected mistral to stay stopped, got %s", target.Status)
+	}
+}
+
+// listAll returns every target, unfiltered
+func listAll(manager *DefaultTargetManager) []api.TargetResponse {
+	targets, _ := manager.ListTargets(api.TargetFilter{})
+	return targets
+}
+
+func TestListTargetsFilterAndPage(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	for _, req := range []api.AddTargetRequest{
+		{Name: "llama-prod", Environment: "prod", Tags: map[string]string{"team": "search", "gpu": "a100"}},
+		{Name: "mistral-prod", Environment: "prod", Tags: map[string]string{"team": "chat"}},
+		{Name: "llama-dev", Environment: "dev", Tags: map[string]string{"team": "search"}},
+		{Name: "qwen-prod", Environment: "prod"},
+	} {
+		req.URL, req.Model = "http://localhost:8000", "m"
+		if _, err := manager.AddTarget(ctx, req); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+	if err := manager.StartTarget(ctx, "mistral-prod"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	names := func(targets []api.TargetResponse) string {
+		var out []string
+		for _, target := range targets {
+			out = append(out, target.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name     string
+		filter   api.TargetFilter
+		expected string
+		total    int
+	}{
+		{"all sorted by name", api.TargetFilter{}, "llama-dev,llama-prod,mistral-prod,qwen-prod", 4},
+		{"environment", api.TargetFilter{Environment: "prod"}, "llama-prod,mistral-prod,qwen-prod", 3},
+		{"status", api.TargetFilter{Status: api.TargetStatusRunning}, "mistral-prod", 1},
+		{"name substring", api.TargetFilter{Query: "LLAMA"}, "llama-dev,llama-prod", 2},
+		{"tag", api.TargetFilter{Tags: map[string]string{"team": "search"}}, "llama-dev,llama-prod", 2},
+		{"tags", api.TargetFilter{Tags: map[string]string{"team": "search", "gpu": ""}}, "llama-prod", 1},
+		{"page", api.TargetFilter{Environment: "prod", Limit: 2, Offset: 1}, "mistral-prod,qwen-prod", 3},
+		{"offset past end", api.TargetFilter{Offset: 10}, "", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, total := manager.ListTargets(tt.filter)
+			if got := names(targets); got != tt.expected || total != tt.total {
+				t.Errorf("expected %q (total %d), got %q (total %d)", tt.expected, tt.total, got, total)
+			}
+		})
+	}
+}
+
+// TestGetSummary verifies that the summary condenses every target's latest
+// results, sorted by name
+func TestGetSummary(t *testing.T) {
+	manager := newTestManager(t)
+	for _, name := range []string{"zeta", "alpha"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	e2e := []float64{0.5, 0.1, 0.3, 0.2, 0.4}
+	manager.mu.Lock()
+	manager.recordRun(manager.targets["zeta"], &runOutput{results: &parser.ParsedResults{
+		TotalRequests:      4,
+		SuccessfulRequests: 3,
+		FailedRequests:     1,
+		OutputTokensPerSec: 120,
+		E2EValues:          e2e,
+	}}, nil)
+	manager.mu.Unlock()
+
+	summary := manager.GetSummary()
+	if len(summary.Targets) != 2 || summary.Targets[0].Name != "alpha" || summary.Targets[1].Name != "zeta" {
+		t.Fatalf("expected alpha and zeta, got %+v", summary.Targets)
+	}
+
+	alpha := summary.Targets[0]
+	if alpha.LastRunAt != nil || alpha.SuccessRate != nil || alpha.E2E != nil {
+		t.Errorf("expected no results for alpha, got %+v", alpha)
+	}
+
+	zeta := summary.Targets[1]
+	if zeta.LastRunAt == nil || zeta.OutputTokensPerSec != 120 || zeta.SuccessRate == nil || *zeta.SuccessRate != 0.75 {
+		t.Errorf("unexpected summary for zeta: %+v", zeta)
+	}
+	if zeta.E2E == nil || zeta.E2E.P50 != 0.3 || zeta.TTFT != nil {
+		t.Errorf("unexpected latencies for zeta: e2e=%+v ttft=%+v", zeta.E2E, zeta.TTFT)
+	}
+	if e2e[0] != 0.5 {
+		t.Error("summary sorted the target's results in place")
+	}
+}
+
+// TestGetMetricsSnapshot verifies that the snapshot reports each target's
+// gauges from its latest run with results, kept through later failures
+func TestGetMetricsSnapshot(t *testing.T) {
+	manager := newTestManager(t)
+	for _, name := range []string{"zeta", "alpha"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	manager.mu.Lock()
+	manager.recordRun(manager.targets["zeta"], &runOutput{results: &parser.ParsedResults{
+		TotalRequests:      4,
+		SuccessfulRequests: 3,
+		OutputTokensPerSec: 120,
+		RequestsPerSec:     2,
+	}}, nil)
+	manager.recordRun(manager.targets["zeta"], nil, errors.New("exit status 1"))
+	manager.mu.Unlock()
+
+	snapshot := manager.GetMetricsSnapshot()
+	if len(snapshot.Targets) != 2 || snapshot.Targets[0].Name != "alpha" || snapshot.Targets[1].Name != "zeta" {
+		t.Fatalf("expected alpha and zeta, got %+v", snapshot.Targets)
+	}
+	alpha := snapshot.Targets[0]
+	if alpha.Up || alpha.OutputTokensPerSec != nil || alpha.LastBenchmarkTimestamp != nil {
+		t.Errorf("expected no gauges for alpha, got %+v", alpha)
+	}
+	zeta := snapshot.Targets[1]
+	if zeta.OutputTokensPerSec == nil || *zeta.OutputTokensPerSec != 120 || *zeta.RequestsPerSec != 2 ||
+		*zeta.RequestSuccessRate != 0.75 || zeta.LastBenchmarkTimestamp == nil {
+		t.Errorf("expected zeta's gauges kept through the failed run, got %+v", zeta)
+	}
+	if zeta.ConsecutiveFailures != 1 {
+		t.Errorf("expected 1 consecutive failure, got %d", zeta.ConsecutiveFailures)
+	}
+	if snapshot.FleetOutputTokensPerSec != 0 {
+		t.Errorf("expected no fresh fleet throughput after the failed run, got %v", snapshot.FleetOutputTokensPerSec)
+	}
+}
+
+// TestFleetThroughput verifies that the fleet throughput sums the latest
+// output tokens per second of targets that ran within the freshness window
+func TestFleetThroughput(t *testing.T) {
+	manager := newTestManager(t)
+	manager.cfg.Metrics.FleetFreshness = 600
+	for _, name := range []string{"fresh-a", "fresh-b", "stale"} {
+		if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+			Name:  name,
+			URL:   "http://localhost:8000",
+			Model: "test-model",
+		}); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	manager.mu.Lock()
+	manager.recordRun(manager.targets["stale"], &runOutput{results: &parser.ParsedResults{OutputTokensPerSec: 1000}}, nil)
+	staleAt := time.Now().Add(-time.Hour)
+	manager.targets["stale"].lastRunAt = &staleAt
+	manager.recordRun(manager.targets["fresh-a"], &runOutput{results: &parser.ParsedResults{OutputTokensPerSec: 100}}, nil)
+	manager.recordRun(manager.targets["fresh-b"], &runOutput{results: &parser.ParsedResults{OutputTokensPerSec: 50}}, nil)
+	manager.mu.Unlock()
+
+	summary := manager.GetSummary()
+	if summary.FleetOutputTokensPerSec != 150 || summary.FleetTargets != 2 {
+		t.Errorf("expected 150 tokens/s from 2 targets, got %g from %d", summary.FleetOutputTokensPerSec, summary.FleetTargets)
+	}
+	if got := testutil.ToFloat64(metrics.FleetOutputTokensPerSecond); got != 150 {
+		t.Errorf("expected fleet gauge 150, got %g", got)
+	}
+
+	if err := manager.RemoveTarget("fresh-b"); err != nil {
+		t.Fatalf("failed to remove target: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.FleetOutputTokensPerSecond); got != 100 {
+		t.Errorf("expected fleet gauge 100 after removal, got %g", got)
+	}
+
+	// A stopped target drops out once its last run goes stale, without
+	// another run completing
+	manager.mu.Lock()
+	manager.targets["fresh-a"].status = api.TargetStatusRunning
+	manager.mu.Unlock()
+	if err := manager.StopTarget("fresh-a"); err != nil {
+		t.Fatalf("failed to stop target: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.FleetOutputTokensPerSecond); got != 100 {
+		t.Errorf("expected fleet gauge 100 while the stopped target's run is fresh, got %g", got)
+	}
+	manager.mu.Lock()
+	manager.targets["fresh-a"].lastRunAt = &staleAt
+	manager.mu.Unlock()
+	if got := testutil.ToFloat64(metrics.FleetOutputTokensPerSecond); got != 0 {
+		t.Errorf("expected fleet gauge 0 once the stopped target's run is stale, got %g", got)
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "compare",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	mt := manager.targets["compare"]
+	manager.mu.Lock()
+	manager.recordRun(mt, &runOutput{runID: "last-week", results: &parser.ParsedResults{
+		OutputTokensPerSec: 100,
+		RequestsPerSec:     10,
+		E2EValues:          []float64{1, 1, 1, 1},
+	}}, nil)
+	manager.recordRun(mt, &runOutput{runID: "today", results: &parser.ParsedResults{
+		OutputTokensPerSec: 95,
+		RequestsPerSec:     10,
+		E2EValues:          []float64{1.5, 1.5, 1.5, 1.5},
+	}}, nil)
+	manager.mu.Unlock()
+
+	resp, err := manager.CompareRuns("compare", api.CompareRequest{Baseline: "last-week"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Current.RunID != "today" {
+		t.Errorf("expected the latest run to be compared by default, got %q", resp.Current.RunID)
+	}
+	byMetric := make(map[string]api.MetricComparison)
+	for _, m := range resp.Metrics {
+		byMetric[m.Metric] = m
+	}
+	if tps := byMetric["output_tokens_per_second"]; tps.DeltaPercent == nil || *tps.DeltaPercent != -5 || !tps.Pass {
+		t.Errorf("expected a passing 5%% throughput drop, got %+v", tps)
+	}
+	if e2e := byMetric["e2e_latency_seconds_p95"]; e2e.DeltaPercent == nil || *e2e.DeltaPercent != 50 || e2e.Pass {
+		t.Errorf("expected a failing 50%% e2e p95 increase, got %+v", e2e)
+	}
+	if _, ok := byMetric["ttft_seconds_p50"]; ok {
+		t.Error("expected TTFT to be skipped when neither run measured it")
+	}
+	if resp.Pass {
+		t.Error("expected the comparison to fail on latency")
+	}
+
+	loose := 60.0
+	resp, err = manager.CompareRuns("compare", api.CompareRequest{Baseline: "last-week", Current: "today", MaxLatencyIncrease: &loose})
+	if err != nil || !resp.Pass {
+		t.Errorf("expected the comparison to pass with a 60%% latency threshold, got %+v (%v)", resp, err)
+	}
+
+	if _, err := manager.CompareRuns("compare", api.CompareRequest{Baseline: "missing"}); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown run, got %v", err)
+	}
+}
+
+// TestBaseline verifies pinning, reading and clearing a target's baseline,
+// and that summaries report the latest results relative to it
+func TestBaseline(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "baseline",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	if _, err := manager.SetBaseline("baseline", ""); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("expected a conflict without results, got %v", err)
+	}
+	if _, err := manager.GetBaseline("baseline"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found before a baseline is set, got %v", err)
+	}
+
+	mt := manager.targets["baseline"]
+	record := func(runID string, tps float64, e2e float64) {
+		manager.mu.Lock()
+		manager.recordRun(mt, &runOutput{runID: runID, results: &parser.ParsedResults{
+			TotalRequests:      1,
+			SuccessfulRequests: 1,
+			OutputTokensPerSec: tps,
+			E2EValues:          []float64{e2e, e2e},
+		}}, nil)
+		manager.mu.Unlock()
+	}
+	record("first", 100, 1)
+	record("second", 200, 2)
+
+	if _, err := manager.SetBaseline("baseline", "missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown run, got %v", err)
+	}
+	baseline, err := manager.SetBaseline("baseline", "first")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if baseline.RunID != "first" || baseline.Results.OutputTokensPerSec != 100 {
+		t.Errorf("expected run first to be pinned, got %+v", baseline)
+	}
+	if got, err := manager.GetBaseline("baseline"); err != nil || got.RunID != "first" {
+		t.Errorf("expected baseline first, got %+v (err %v)", got, err)
+	}
+
+	summary := manager.GetSummary().Targets[0].VsBaseline
+	if summary == nil || summary.RunID != "first" {
+		t.Fatalf("expected deltas against run first, got %+v", summary)
+	}
+	if summary.OutputTokensPerSec == nil || *summary.OutputTokensPerSec != 100 {
+		t.Errorf("expected throughput +100%%, got %v", summary.OutputTokensPerSec)
+	}
+	if summary.E2EP95 == nil || *summary.E2EP95 != 100 {
+		t.Errorf("expected e2e p95 +100%%, got %v", summary.E2EP95)
+	}
+	if summary.RequestsPerSec != nil || summary.TTFTP95 != nil {
+		t.Errorf("expected no deltas for metrics the baseline lacks, got %+v", summary)
+	}
+
+	// Without a run ID the latest run is pinned
+	if baseline, err := manager.SetBaseline("baseline", ""); err != nil || baseline.RunID != "second" {
+		t.Errorf("expected the latest run to be pinned, got %+v (err %v)", baseline, err)
+	}
+
+	if err := manager.ClearBaseline("baseline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manager.GetSummary().Targets[0].VsBaseline != nil {
+		t.Error("expected no deltas after the baseline is cleared")
+	}
+	if err := manager.ClearBaseline("baseline"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found clearing an unset baseline, got %v", err)
+	}
+	if _, err := manager.GetBaseline("missing"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for an unknown target, got %v", err)
+	}
+}
+
+// TestBaselineSkipsRunsWithoutThroughput verifies that a run without output
+// throughput, which regression alerts couldn't compare against, isn't pinned
+func TestBaselineSkipsRunsWithoutThroughput(t *testing.T) {
+	manager := newTestManager(t)
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "baseline",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+
+	mt := manager.targets["baseline"]
+	record := func(runID string, tps float64, err error) {
+		manager.mu.Lock()
+		manager.recordRun(mt, &runOutput{runID: runID, results: &parser.ParsedResults{OutputTokensPerSec: tps}}, err)
+		manager.mu.Unlock()
+	}
+
+	record("idle", 0, nil)
+	if _, err := manager.SetBaseline("baseline", ""); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("expected a conflict without a run with throughput, got %v", err)
+	}
+
+	record("good", 100, nil)
+	record("empty", 0, &RunError{Category: FailureZeroRequests, Err: errors.New("zero requests")})
+	record("idle-again", 0, nil)
+	baseline, err := manager.SetBaseline("baseline", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if baseline.RunID != "good" {
+		t.Errorf("expected the latest run with throughput to be pinned, got %q", baseline.RunID)
+	}
+	if _, err := manager.SetBaseline("baseline", "idle-again"); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("expected a conflict pinning a run without throughput, got %v", err)
+	}
+}
+
+// TestWarmupRunsAreDiscarded verifies that a starting target makes its
+// warmup runs before the first recorded run, without counting them
+func TestWarmupRunsAreDiscarded(t *testing.T) {
+	invocations := filepath.Join(t.TempDir(), "invocations")
+	manager := newTestManager(t)
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, "echo run >> "+invocations+"\nexit 1")
+	manager.cfg.Defaults.WarmupRuns = 2
+	manager.SetRunner(New(manager.cfg, manager.logger))
+
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "warmup-target",
+		URL:   "http://localhost:8000",
+		Model: "test-model",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	if err := manager.StartTarget(context.Background(), "warmup-target"); err != nil {
+		t.Fatalf("failed to start target: %v", err)
+	}
+	defer func() {
+		manager.StopAll()
+		manager.Wait()
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		target, _ := manager.GetTarget("warmup-target")
+		if target.LastRunAt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first recorded run never completed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	data, err := os.ReadFile(invocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "run"); got != 3 {
+		t.Errorf("expected 2 warmups and 1 recorded run, got %d invocations", got)
+	}
+	runs := metrics.BenchmarkRunsTotal.With(metrics.Labels("dynamic", "warmup-target", "test-model"))
+	if got := testutil.ToFloat64(runs); got != 1 {
+		t.Errorf("expected only the recorded run to be counted, got %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RunOnce runs every registered target once and waits for them to finish,
+// for use as a CI gate or cron job rather than a daemon. Each target is
+// started for a single scheduled run (a full sweep, for targets with a
+// sweep) made as soon as it starts; max_runs is ignored, and targets with
+// auto_start off are left out. It returns an error naming the targets whose
+// run failed or that never ran, e.g. because they couldn't be started.
+func (m *DefaultTargetManager) RunOnce(ctx context.Context) error {
+	m.mu.Lock()
+	m.once = true
+	m.mu.Unlock()
+	names, _ := m.autoStartTargets()
+	if len(names) == 0 {
+		return errors.New("no targets to run")
+	}
+
+	m.StartAllConfigured(ctx)
+	m.Wait()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var failed []string
+	for _, name := range names {
+		mt, exists := m.targets[name]
+		if !exists || mt.lastErrorAt != nil || mt.lastResults == nil {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d targets failed: %s", len(failed), len(names), strings.Join(failed, ", "))
+	}
+	m.logger.Info("all targets ran successfully", "targets", len(names))
+	return nil
+}
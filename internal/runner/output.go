@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// rawLogName is the file in a run's output directory guidellm's full
+// output is streamed to when raw output is kept
+const rawLogName = "guidellm.log"
+
+// maxTailLines bounds the lines of each output stream kept on a RunError
+const maxTailLines = 10
+
+// tailBuffer is an io.Writer that keeps only the last max bytes written, so
+// a chatty run can't exhaust memory. Failures are reported at the end of
+// the output, so the tail is what's kept.
+type tailBuffer struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > b.max {
+		b.truncated = true
+		p = p[len(p)-b.max:]
+	}
+	if over := len(b.buf) + len(p) - b.max; over > 0 {
+		b.truncated = true
+		b.buf = b.buf[:copy(b.buf, b.buf[over:])]
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+// String returns the kept output. If earlier output was dropped, the
+// partial first line is dropped with it.
+func (b *tailBuffer) String() string {
+	s := string(b.buf)
+	if b.truncated {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+	return s
+}
+
+// tailLines returns the last n non-empty lines of output, each truncated to
+// maxDetailLength
+func tailLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	tail := make([]string, 0, n)
+	for i := len(lines) - 1; i >= 0 && len(tail) < n; i-- {
+		line := strings.TrimRight(lines[i], " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) > maxDetailLength {
+			line = line[:maxDetailLength] + "..."
+		}
+		tail = append(tail, line)
+	}
+	for i, j := 0, len(tail)-1; i < j; i, j = i+1, j-1 {
+		tail[i], tail[j] = tail[j], tail[i]
+	}
+	return strings.Join(tail, "\n")
+}
+
+// maxLogLine bounds the partial line a lineWriter holds back; longer lines
+// are passed on in pieces
+const maxLogLine = 64 << 10
+
+// lineWriter passes what is written on to a writer shared with other
+// streams a whole line at a time, holding back a partial line until it is
+// completed, so the streams interleave by line rather than mid-line
+type lineWriter struct {
+	w   io.Writer
+	mu  *sync.Mutex // shared by the writers of w
+	buf []byte
+}
+
+func newLineWriter(w io.Writer, mu *sync.Mutex) *lineWriter {
+	return &lineWriter{w: w, mu: mu}
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	end := bytes.LastIndexByte(l.buf, '\n') + 1
+	if end == 0 && len(l.buf) < maxLogLine {
+		return len(p), nil
+	}
+	if end == 0 {
+		end = len(l.buf)
+	}
+	l.mu.Lock()
+	_, err := l.w.Write(l.buf[:end])
+	l.mu.Unlock()
+	l.buf = l.buf[:copy(l.buf, l.buf[end:])]
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush passes on a final partial line
+func (l *lineWriter) Flush() error {
+	if len(l.buf) == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.w.Write(l.buf)
+	l.buf = nil
+	return err
+}
+
+// copyRedacted copies the file at src to dst a line at a time, masking
+// secrets, so large output is never held in memory whole
+func copyRedacted(dst, src string, secrets ...string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	reader := bufio.NewReader(in)
+	writer := bufio.NewWriter(out)
+	for {
+		line, err := reader.ReadString('\n')
+		if _, werr := writer.WriteString(redactString(line, secrets...)); werr != nil {
+			return werr
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return out.Close()
+}
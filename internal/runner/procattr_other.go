@@ -0,0 +1,9 @@
+//go:build !unix
+
+package runner
+
+import "os/exec"
+
+// setProcessGroup is a no-op on platforms without process groups; only the
+// guidellm process itself is killed on cancellation
+func setProcessGroup(cmd *exec.Cmd) {}
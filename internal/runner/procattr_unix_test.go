@@ -0,0 +1,80 @@
+//go:build unix
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+// TestRunBenchmarkTimeoutKillsProcessGroup verifies that a timed out run
+// signals guidellm's whole process group, not just guidellm itself
+func TestRunBenchmarkTimeoutKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	// Stand-in for guidellm forking a worker and waiting on it
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, "sleep 30 &\necho $! > "+pidFile+"\nwait"),
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			RunTimeout:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "forking-target", URL: "http://test.local/v1", Model: "test-model"}
+
+	start := time.Now()
+	runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("run was not cut off by the timeout, took %s", elapsed)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("fake guidellm did not record its child: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("invalid child pid %q: %v", data, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("child process %d survived the timeout kill", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// processAlive reports whether pid is running. Zombies count as dead: an
+// orphan is reparented and may not be reaped promptly (e.g. in containers).
+func processAlive(pid int) bool {
+	if err := syscall.Kill(pid, 0); err != nil {
+		return false
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	// The state follows the parenthesised command name
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces secrets in anything we log
+const redactedValue = "REDACTED"
+
+// bearerTokenPattern matches bearer tokens such as the Authorization header
+// injected into --request-formatter-kwargs
+var bearerTokenPattern = regexp.MustCompile(`(?i)(bearer\s+)[^\s"'\\,}]+`)
+
+// redactString masks bearer tokens, the OPENAI_API_KEY value and any extra
+// secrets in s so it can be logged safely
+func redactString(s string, secrets ...string) string {
+	s = bearerTokenPattern.ReplaceAllString(s, "${1}"+redactedValue)
+
+	for _, secret := range append(secrets, os.Getenv("OPENAI_API_KEY")) {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+	return s
+}
+
+// redactArgs returns a copy of a command's args with secrets masked
+func redactArgs(args []string, secrets ...string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = redactString(arg, secrets...)
+	}
+	return redacted
+}
@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+func TestRedactString(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "env-secret-67890")
+
+	tests := []struct {
+		name     string
+		input    string
+		secrets  []string
+		expected string
+	}{
+		{
+			name:     "bearer token in formatter kwargs",
+			input:    `{"stream": false, "extras": {"headers": {"Authorization": "Bearer sk-abc123"}}}`,
+			expected: `{"stream": false, "extras": {"headers": {"Authorization": "Bearer REDACTED"}}}`,
+		},
+		{
+			name:     "env key value",
+			input:    "using key env-secret-67890 for auth",
+			expected: "using key REDACTED for auth",
+		},
+		{
+			name:     "explicit secret",
+			input:    "api_key=target-secret",
+			secrets:  []string{"target-secret"},
+			expected: "api_key=REDACTED",
+		},
+		{
+			name:     "nothing to redact",
+			input:    "--rate 10",
+			expected: "--rate 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactString(tt.input, tt.secrets...); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestRunBenchmarkNeverLogsSecrets runs a benchmark with debug logging and
+// asserts neither the target key nor the env key appear in the log output
+func TestRunBenchmarkNeverLogsSecrets(t *testing.T) {
+	const targetKey = "target-secret-12345"
+	const envKey = "env-secret-67890"
+	t.Setenv("OPENAI_API_KEY", envKey)
+
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	runner := New(cfg, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, key := range []string{targetKey, ""} {
+		target := config.Target{
+			Name:   "secret-target",
+			URL:    "http://127.0.0.1:1/v1",
+			Model:  "test-model",
+			APIKey: key,
+		}
+		runner.runBenchmarkWithResults(ctx, "test", target, "", logger)
+		runner.DryRun("test", target, logger)
+	}
+
+	out := buf.String()
+	if out == "" {
+		t.Fatal("expected log output")
+	}
+	for _, secret := range []string{targetKey, envKey} {
+		if strings.Contains(out, secret) {
+			t.Errorf("secret %q leaked into logs: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, redactedValue) {
+		t.Errorf("expected redacted marker in logs: %s", out)
+	}
+}
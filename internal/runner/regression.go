@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/webhook"
+)
+
+// throughputBaseline returns the mean output tokens per second of the last
+// runs entries of history, or false if history holds fewer runs with
+// throughput than that
+func throughputBaseline(history []historyEntry, runs int) (float64, bool) {
+	var sum float64
+	n := 0
+	for i := len(history) - 1; i >= 0 && n < runs; i-- {
+		if tps := history[i].results.OutputTokensPerSec; tps > 0 {
+			sum += tps
+			n++
+		}
+	}
+	if n < runs {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// checkRegression compares a successful run's output throughput against the
+// target's pinned baseline or, without one, the mean of its previous runs,
+// alerting via the webhook when it has dropped more than the threshold.
+// Must be called with m.mu held, before the run is added to the history.
+func (m *DefaultTargetManager) checkRegression(mt *managedTarget, at time.Time, current float64) {
+	threshold := mt.target.GetRegressionThreshold(m.cfg.Defaults)
+	if threshold <= 0 || current <= 0 {
+		return
+	}
+
+	var baseline float64
+	var runs int
+	var baselineRunID string
+	if pinned := mt.baseline; pinned != nil && pinned.Results.OutputTokensPerSec > 0 {
+		baseline, baselineRunID = pinned.Results.OutputTokensPerSec, pinned.RunID
+	} else {
+		runs = min(mt.target.GetRegressionWindow(m.cfg.Defaults), maxHistoryEntries)
+		var ok bool
+		if baseline, ok = throughputBaseline(mt.history, runs); !ok {
+			return
+		}
+	}
+
+	change := (current - baseline) / baseline * 100
+	if -change <= threshold {
+		return
+	}
+
+	m.logger.Warn("output throughput regression",
+		"environment", mt.environment,
+		"target", mt.target.Name,
+		"model", mt.target.Model,
+		"baseline", baseline,
+		"current", current,
+		"change_percent", change,
+		"baseline_run_id", baselineRunID,
+	)
+	if m.notifier == nil {
+		return
+	}
+	baselineDesc := fmt.Sprintf("%d-run baseline", runs)
+	if baselineRunID != "" {
+		baselineDesc = fmt.Sprintf("pinned baseline (run %s)", baselineRunID)
+	}
+	m.notifier.Notify(webhook.RegressionEvent{
+		Event: webhook.EventThroughputRegression,
+		Text: fmt.Sprintf("%s (%s): output throughput %.1f tok/s is %.1f%% below its %s of %.1f tok/s",
+			mt.target.Name, mt.target.Model, current, -change, baselineDesc, baseline),
+		Target:        mt.target.Name,
+		Environment:   mt.environment,
+		Model:         mt.target.Model,
+		Timestamp:     at,
+		Baseline:      baseline,
+		Current:       current,
+		ChangePercent: change,
+		Threshold:     threshold,
+		BaselineRuns:  runs,
+		BaselineRunID: baselineRunID,
+	})
+}
@@ -0,0 +1,155 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/parser"
+	"github.com/yourorg/guidellm-runner/internal/webhook"
+)
+
+func TestThroughputBaseline(t *testing.T) {
+	entry := func(tps float64) historyEntry {
+		return historyEntry{results: &parser.ParsedResults{OutputTokensPerSec: tps}}
+	}
+	history := []historyEntry{entry(1000), entry(100), entry(0), entry(200)}
+
+	if baseline, ok := throughputBaseline(history, 2); !ok || baseline != 150 {
+		t.Errorf("expected baseline 150, got %g (ok=%v)", baseline, ok)
+	}
+	// Runs without throughput don't count towards the window
+	if baseline, ok := throughputBaseline(history, 3); !ok || baseline != 1300.0/3 {
+		t.Errorf("expected baseline %g, got %g (ok=%v)", 1300.0/3, baseline, ok)
+	}
+	if _, ok := throughputBaseline(history, 4); ok {
+		t.Error("expected no baseline with too few runs")
+	}
+}
+
+// TestRegressionAlert verifies that a drop in throughput beyond the target's
+// threshold posts a regression event to the webhook
+func TestRegressionAlert(t *testing.T) {
+	events := make(chan webhook.RegressionEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.RegressionEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		if event.Event == webhook.EventThroughputRegression {
+			events <- event
+		}
+	}))
+	defer srv.Close()
+
+	notifier := webhook.New(config.WebhookConfig{URL: srv.URL, Timeout: 5, Workers: 1, QueueSize: 10},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer notifier.Close(context.Background())
+
+	manager := newTestManager(t)
+	manager.SetNotifier(notifier)
+	manager.cfg.Defaults.RegressionThreshold = 20
+	manager.cfg.Defaults.RegressionWindow = 5
+	window := 2
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "llama",
+		URL:   "http://localhost:8000",
+		Model: "llama-3",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	mt := manager.targets["llama"]
+	mt.target.RegressionWindow = &window
+
+	record := func(tps float64) {
+		manager.mu.Lock()
+		manager.recordRun(mt, &runOutput{results: &parser.ParsedResults{TotalRequests: 1, SuccessfulRequests: 1, OutputTokensPerSec: tps}}, nil)
+		manager.mu.Unlock()
+	}
+
+	// A 10% drop from the 2-run baseline of 100 is within the threshold
+	record(100)
+	record(100)
+	record(90)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected regression alert %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// 50 is a 47% drop from the baseline of 95
+	record(50)
+	select {
+	case event := <-events:
+		if event.Target != "llama" || event.Baseline != 95 || event.Current != 50 || event.BaselineRuns != 2 {
+			t.Errorf("unexpected event %+v", event)
+		}
+		if event.ChangePercent > -47 || event.ChangePercent < -48 {
+			t.Errorf("expected change of about -47%%, got %g", event.ChangePercent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a regression alert")
+	}
+}
+
+// TestPinnedBaselineRegression verifies that a pinned baseline is preferred
+// over the rolling window when checking for regressions
+func TestPinnedBaselineRegression(t *testing.T) {
+	events := make(chan webhook.RegressionEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.RegressionEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		if event.Event == webhook.EventThroughputRegression {
+			events <- event
+		}
+	}))
+	defer srv.Close()
+
+	notifier := webhook.New(config.WebhookConfig{URL: srv.URL, Timeout: 5, Workers: 1, QueueSize: 10},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer notifier.Close(context.Background())
+
+	manager := newTestManager(t)
+	manager.SetNotifier(notifier)
+	manager.cfg.Defaults.RegressionThreshold = 20
+	manager.cfg.Defaults.RegressionWindow = 5
+	if _, err := manager.AddTarget(context.Background(), api.AddTargetRequest{
+		Name:  "llama",
+		URL:   "http://localhost:8000",
+		Model: "llama-3",
+	}); err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	mt := manager.targets["llama"]
+
+	record := func(runID string, tps float64) {
+		manager.mu.Lock()
+		manager.recordRun(mt, &runOutput{runID: runID, results: &parser.ParsedResults{TotalRequests: 1, SuccessfulRequests: 1, OutputTokensPerSec: tps}}, nil)
+		manager.mu.Unlock()
+	}
+
+	// One run is too few for the 5-run window, but the pinned baseline
+	// applies straight away
+	record("golden", 100)
+	if _, err := manager.SetBaseline("llama", "golden"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record("slow", 70)
+	select {
+	case event := <-events:
+		if event.Baseline != 100 || event.Current != 70 || event.BaselineRunID != "golden" || event.BaselineRuns != 0 {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a regression alert")
+	}
+}
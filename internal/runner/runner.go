@@ -2,15 +2,21 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/discovery"
 	"github.com/yourorg/guidellm-runner/internal/metrics"
 	"github.com/yourorg/guidellm-runner/internal/parser"
 )
@@ -19,15 +25,71 @@ import (
 type Runner struct {
 	cfg    *config.Config
 	logger *slog.Logger
+	binary string
 	wg     sync.WaitGroup
+
+	slots    chan struct{} // run slots, nil when concurrency is unlimited
+	inFlight atomic.Int64  // guidellm subprocesses currently running
+
+	// version is the guidellm version found by DetectVersion
+	versionMu sync.RWMutex
+	version   string
 }
 
 // New creates a new Runner
 func New(cfg *config.Config, logger *slog.Logger) *Runner {
-	return &Runner{
+	binary := cfg.GuideLLMBinary
+	if binary == "" {
+		binary = "guidellm"
+	}
+
+	r := &Runner{
 		cfg:    cfg,
 		logger: logger,
+		binary: binary,
+	}
+	if cfg.Defaults.MaxConcurrentRuns > 0 {
+		r.slots = make(chan struct{}, cfg.Defaults.MaxConcurrentRuns)
+	}
+	return r
+}
+
+// InFlight returns the number of guidellm runs currently holding a slot
+func (r *Runner) InFlight() int {
+	return int(r.inFlight.Load())
+}
+
+// acquire waits for a run slot, returning false if ctx is cancelled first
+func (r *Runner) acquire(ctx context.Context) bool {
+	if r.slots != nil {
+		select {
+		case r.slots <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	r.inFlight.Add(1)
+	return true
+}
+
+// release returns a run slot taken by acquire
+func (r *Runner) release() {
+	r.inFlight.Add(-1)
+	if r.slots != nil {
+		<-r.slots
+	}
+}
+
+// ResolveBinary looks up the guidellm executable (a name on PATH or a path)
+// and returns its absolute path, failing if it is missing or not
+// executable. The path is absolute so it still resolves when guidellm runs
+// in subprocess.work_dir.
+func ResolveBinary(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("guidellm binary %q not found or not executable: %w", name, err)
 	}
+	return filepath.Abs(path)
 }
 
 // Start begins running benchmarks for all environments and targets
@@ -70,83 +132,89 @@ func (r *Runner) runTargetLoop(ctx context.Context, envName string, target confi
 		"profile", target.GetProfile(r.cfg.Defaults),
 		"rate", target.GetRate(r.cfg.Defaults))
 
-	ticker := time.NewTicker(r.cfg.GetInterval())
-	defer ticker.Stop()
-
-	// Run immediately, then on interval
-	r.runBenchmark(ctx, envName, target, logger)
+	schedule := newRunSchedule(r.cfg.Defaults, r.cfg.GetInterval(), time.Now())
+	timer := time.NewTimer(time.Until(schedule.next))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("stopping benchmark loop")
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			r.runBenchmark(ctx, envName, target, logger)
+			schedule.advance(time.Now())
+			timer.Reset(time.Until(schedule.next))
 		}
 	}
 }
 
 // runBenchmark executes a single GuideLLM benchmark run (backwards compatible)
 func (r *Runner) runBenchmark(ctx context.Context, envName string, target config.Target, logger *slog.Logger) {
-	r.runBenchmarkWithResults(ctx, envName, target, logger)
+	r.runBenchmarkWithResults(ctx, envName, target, "", logger)
 }
 
-// runBenchmarkWithResults executes a single GuideLLM benchmark run and returns results
-func (r *Runner) runBenchmarkWithResults(ctx context.Context, envName string, target config.Target, logger *slog.Logger) *parser.ParsedResults {
-	labels := metrics.Labels(envName, target.Name, target.Model)
-	metrics.BenchmarkRunsTotal.With(labels).Inc()
-
-	// Create temp directory for output
-	tmpDir, err := os.MkdirTemp("", "guidellm-*")
-	if err != nil {
-		logger.Error("failed to create temp directory", "error", err)
-		metrics.BenchmarkRunsFailed.With(labels).Inc()
-		return nil
-	}
-	defer os.RemoveAll(tmpDir)
+// subprocessWaitDelay bounds how long a killed guidellm run may take to be
+// reaped and release its output pipes
+const subprocessWaitDelay = 5 * time.Second
 
-	outputFile := filepath.Join(tmpDir, "benchmarks.json")
+// runOutput is what a single GuideLLM benchmark run produced
+type runOutput struct {
+	results *parser.ParsedResults
+	raw     []byte // raw guidellm JSON output, as written to benchmarks.json
+	runID   string
+}
 
-	// Get API key - prefer target config, fall back to environment
-	apiKey := target.APIKey
-	if apiKey == "" {
-		apiKey = os.Getenv("OPENAI_API_KEY")
+// runBenchmarkWithResults executes a single GuideLLM benchmark run and returns
+// its parsed results along with the raw guidellm output. runID identifies
+// the run in the run registry and its exemplars. A failed run returns
+// a *RunError; runs that completed but made no successful requests return
+// their results as well as the error. A run cancelled while waiting for a
+// slot returns the context's error.
+func (r *Runner) runBenchmarkWithResults(ctx context.Context, envName string, target config.Target, runID string, logger *slog.Logger) (*runOutput, error) {
+	// Queue for a slot when max_concurrent_runs is reached, so runs are
+	// delayed rather than dropped
+	if !r.acquire(ctx) {
+		logger.Info("benchmark run cancelled while waiting for a run slot")
+		return nil, ctx.Err()
 	}
+	defer r.release()
 
-	// Build GuideLLM command with API key injected into headers
-	// Note: guidellm does NOT read OPENAI_API_KEY from environment, so we
-	// must inject it via --request-formatter-kwargs
-	args := r.buildArgs(target, tmpDir, apiKey)
-	logger.Debug("running guidellm", "args", args)
-
-	cmd := exec.CommandContext(ctx, "guidellm", args...)
+	labels := metrics.Labels(envName, target.Name, target.Model)
+	metrics.BenchmarkRunsTotal.With(labels).Inc()
 
-	// Capture output for debugging
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Error("guidellm failed",
-			"error", err,
-			"output", string(output))
-		metrics.BenchmarkRunsFailed.With(labels).Inc()
-		return nil
+	// fail counts a failed run under its reason
+	fail := func(runErr *RunError) *RunError {
+		metrics.BenchmarkRunsFailed.With(metrics.FailureLabels(labels, string(runErr.Category))).Inc()
+		return runErr
 	}
 
-	logger.Debug("guidellm completed", "output_length", len(output))
+	raw, execErr := r.runGuidellm(ctx, target, logger)
+	if execErr != nil {
+		if execErr.Category == FailureSpawn {
+			metrics.SpawnErrors.With(labels).Inc()
+		}
+		return nil, fail(execErr)
+	}
 
-	// Parse results
-	results, err := parser.ParseFile(outputFile)
+	// Parse results, keeping the raw bytes for guidellm-native exports
+	results, err := parser.Parse(raw)
 	if err != nil {
 		logger.Error("failed to parse results", "error", err)
-		metrics.BenchmarkRunsFailed.With(labels).Inc()
-		return nil
+		return nil, fail(&RunError{Category: FailureParse, Err: err})
 	}
 
 	// Update Prometheus metrics
-	r.updateMetrics(labels, results)
+	r.updateMetrics(r.runLabels(labels, target), results, r.exemplar(runID), logger)
 	metrics.LastBenchmarkTimestamp.With(labels).SetToCurrentTime()
+	if r.cfg.Prometheus.DataKindLabel {
+		dataKind := config.DataKind(target.GetDataSpec(r.cfg.Defaults))
+		metrics.TargetDataKind.With(metrics.DataKindLabels(labels, dataKind)).Set(1)
+	}
+	metrics.SetTargetTags(labels, target.Tags)
 
 	// Log at appropriate level based on results
+	var runErr error
 	if results.TotalRequests == 0 {
 		// Zero requests indicates a silent failure - likely validation or connection issue
 		logger.Error("benchmark completed with ZERO requests - possible validation failure",
@@ -156,7 +224,16 @@ func (r *Runner) runBenchmarkWithResults(ctx context.Context, envName string, ta
 			"url", target.URL,
 			"model", target.Model,
 			"hint", "Check if the target URL is reachable and authentication is configured correctly")
-		metrics.BenchmarkRunsFailed.With(labels).Inc()
+		runErr = fail(&RunError{Category: FailureZeroRequests, Err: errors.New("benchmark completed with zero requests")})
+		metrics.ZeroRequestRuns.With(labels).Inc()
+		if r.cfg.Defaults.CheckModelOnZeroRequests {
+			apiKey, _ := resolveAPIKey(target)
+			r.checkModelPresence(ctx, labels, target, apiKey, logger)
+		}
+		if r.cfg.Defaults.DiagnoseFailures {
+			apiKey, _ := resolveAPIKey(target)
+			r.diagnoseTarget(ctx, target, apiKey, logger)
+		}
 	} else if results.FailedRequests > 0 && results.SuccessfulRequests == 0 {
 		// All requests failed
 		logger.Error("benchmark completed with all requests failed",
@@ -164,7 +241,12 @@ func (r *Runner) runBenchmarkWithResults(ctx context.Context, envName string, ta
 			"successful", results.SuccessfulRequests,
 			"failed", results.FailedRequests,
 			"tokens_per_sec", results.OutputTokensPerSec)
+		runErr = fail(&RunError{Category: FailureAllFailed, Err: fmt.Errorf("all %d requests failed", results.FailedRequests)})
 	} else {
+		if r.cfg.Defaults.CheckModelOnZeroRequests {
+			// Requests went through, so the endpoint evidently serves the model
+			metrics.TargetModelPresent.With(labels).Set(1)
+		}
 		logger.Info("benchmark completed",
 			"requests", results.TotalRequests,
 			"successful", results.SuccessfulRequests,
@@ -172,43 +254,337 @@ func (r *Runner) runBenchmarkWithResults(ctx context.Context, envName string, ta
 			"tokens_per_sec", results.OutputTokensPerSec)
 	}
 
-	return results
+	return &runOutput{results: results, raw: raw, runID: runID}, runErr
+}
+
+// runWarmup executes a benchmark run whose results are discarded, to warm a
+// cold server up before its runs are recorded. It waits for a run slot like
+// any other run but records no metrics.
+func (r *Runner) runWarmup(ctx context.Context, target config.Target, logger *slog.Logger) error {
+	if !r.acquire(ctx) {
+		return ctx.Err()
+	}
+	defer r.release()
+
+	if _, err := r.runGuidellm(ctx, target, logger); err != nil {
+		return err
+	}
+	return nil
+}
+
+// runGuidellm runs guidellm once for target in a temp directory and returns
+// the raw JSON it wrote. It records no metrics, leaving that to the caller.
+func (r *Runner) runGuidellm(ctx context.Context, target config.Target, logger *slog.Logger) ([]byte, *RunError) {
+	// Create temp directory for output
+	tmpDir, err := os.MkdirTemp("", "guidellm-*")
+	if err != nil {
+		logger.Error("failed to create temp directory", "error", err)
+		return nil, &RunError{Category: FailureSpawn, Err: err}
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Get API key - prefer target config, fall back to environment
+	apiKey, _ := resolveAPIKey(target)
+
+	if r.cfg.KeepRawOutput {
+		// Deferred after RemoveAll so it runs first, keeping the output of
+		// failed runs too
+		defer r.keepRawOutput(tmpDir, target, apiKey, logger)
+	}
+
+	// Build GuideLLM command with API key injected into headers
+	// Note: guidellm does NOT read OPENAI_API_KEY from environment, so we
+	// must inject it via --request-formatter-kwargs
+	args := r.buildArgs(target, tmpDir, apiKey)
+	logger.Debug("running guidellm", "args", redactArgs(args, apiKey))
+
+	// Bound the subprocess independently of max_seconds so a hung guidellm
+	// can't block the target loop forever
+	timeout := target.GetRunTimeout(r.cfg.Defaults)
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := r.command(runCtx, args...)
+	setProcessGroup(cmd)
+	// Stop waiting on output pipes shortly after a kill, in case something
+	// outside the process group still holds them open
+	cmd.WaitDelay = subprocessWaitDelay
+
+	// Capture the ends of stdout (progress) and stderr (errors) separately
+	tailBytes := r.cfg.Subprocess.GetOutputTailBytes()
+	stdout, stderr := newTailBuffer(tailBytes), newTailBuffer(tailBytes)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if r.cfg.KeepRawOutput {
+		// Stream the full output next to the results, for keepRawOutput.
+		// Closed before the deferred keepRawOutput runs.
+		logFile, err := os.Create(filepath.Join(tmpDir, rawLogName))
+		if err != nil {
+			logger.Warn("failed to create guidellm output log", "error", err)
+		} else {
+			defer logFile.Close()
+			// The streams are written to the log a whole line at a time so
+			// they interleave by line, as the log is redacted line by line
+			var logMu sync.Mutex
+			stdoutLog, stderrLog := newLineWriter(logFile, &logMu), newLineWriter(logFile, &logMu)
+			defer stdoutLog.Flush()
+			defer stderrLog.Flush()
+			cmd.Stdout = io.MultiWriter(stdout, stdoutLog)
+			cmd.Stderr = io.MultiWriter(stderr, stderrLog)
+		}
+	}
+	if err := cmd.Run(); err != nil {
+		stdoutText := redactString(stdout.String(), apiKey)
+		stderrText := redactString(stderr.String(), apiKey)
+		detail := lastLine(stderrText)
+		if detail == "" {
+			detail = lastLine(stdoutText)
+		}
+		runErr := &RunError{
+			Category: classifyFailure(stderrText + "\n" + stdoutText),
+			Err:      err,
+			Detail:   detail,
+			Stderr:   tailLines(stderrText, maxTailLines),
+			Stdout:   tailLines(stdoutText, maxTailLines),
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			runErr.Category = FailureSpawn
+		}
+		if runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			runErr.Category = FailureTimeout
+			logger.Error("guidellm run timed out",
+				"timeout", timeout.String(),
+				"reason", runErr.Category,
+				"stderr", stderrText,
+				"stdout", runErr.Stdout)
+		} else {
+			logger.Error("guidellm failed",
+				"error", err,
+				"reason", runErr.Category,
+				"stderr", stderrText,
+				"stdout", runErr.Stdout)
+		}
+		return nil, runErr
+	}
+
+	logger.Debug("guidellm completed", "stdout_length", len(stdout.buf), "stderr_length", len(stderr.buf))
+
+	outputFile, err := findOutputFile(tmpDir)
+	if err != nil {
+		logger.Error("failed to find results", "error", err)
+		return nil, &RunError{Category: FailureParse, Err: err}
+	}
+	logger.Debug("found guidellm output", "file", filepath.Base(outputFile))
+
+	raw, err := os.ReadFile(outputFile)
+	if err != nil {
+		logger.Error("failed to read results", "error", err)
+		return nil, &RunError{Category: FailureParse, Err: err}
+	}
+	return raw, nil
+}
+
+// command returns a guidellm command run in the configured working
+// directory, with the configured environment
+func (r *Runner) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Dir = r.cfg.Subprocess.WorkDir
+	cmd.Env = r.cfg.Subprocess.Environ(os.Environ())
+	return cmd
+}
+
+// findOutputFile returns the newest JSON file guidellm wrote to dir. Its
+// name varies across guidellm versions (benchmarks.json, or including a run
+// id), so any JSON file is accepted.
+func findOutputFile(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+
+	var newest string
+	var newestMod time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestMod) {
+			newest, newestMod = path, info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no JSON output found in %s", dir)
+	}
+	return newest, nil
+}
+
+// keepRawOutput copies a run's raw guidellm output from its output
+// directory into the raw output directory as <target>-<timestamp>.json,
+// and its console output, if streamed to rawLogName, as
+// <target>-<timestamp>.log. Runs where guidellm wrote nothing are skipped,
+// and copy failures only logged.
+func (r *Runner) keepRawOutput(outputDir string, target config.Target, apiKey string, logger *slog.Logger) {
+	// Discovered target names may contain slashes from model IDs
+	name := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(target.Name)
+	base := filepath.Join(r.cfg.RawOutputDir, fmt.Sprintf("%s-%s", name, time.Now().UTC().Format("20060102T150405Z")))
+	if err := os.MkdirAll(r.cfg.RawOutputDir, 0o755); err != nil {
+		logger.Warn("failed to create raw output directory", "error", err)
+		return
+	}
+
+	// guidellm's console output, redacted as it may echo the API key
+	if _, err := os.Stat(filepath.Join(outputDir, rawLogName)); err == nil {
+		if err := copyRedacted(base+".log", filepath.Join(outputDir, rawLogName), apiKey); err != nil {
+			logger.Warn("failed to keep guidellm output log", "error", err)
+		}
+	}
+
+	outputFile, err := findOutputFile(outputDir)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		logger.Warn("failed to read raw guidellm output", "error", err)
+		return
+	}
+	path := base + ".json"
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		logger.Warn("failed to keep raw guidellm output", "error", err)
+		return
+	}
+	logger.Info("kept raw guidellm output", "path", path)
+}
+
+// checkModelPresence probes the target's /v1/models endpoint to tell a model
+// the endpoint doesn't serve apart from other zero-request causes, and
+// records the outcome in the model-present gauge. Returns false only when the
+// endpoint answered and the model is missing.
+func (r *Runner) checkModelPresence(ctx context.Context, labels map[string]string, target config.Target, apiKey string, logger *slog.Logger) bool {
+	endpoint, err := discovery.ModelsEndpoint(target.URL)
+	if err != nil {
+		logger.Warn("cannot derive models endpoint for model check", "error", err)
+		return true
+	}
+
+	client := discovery.NewClientWithTimeout(logger, target.GetProbeTimeout(r.cfg.Defaults))
+	present, err := client.HasModel(ctx, endpoint, apiKey, target.Model)
+	if err != nil {
+		logger.Warn("model check failed", "endpoint", endpoint, "error", err)
+		return true
+	}
+
+	if !present {
+		logger.Error("model not found on endpoint",
+			"endpoint", endpoint,
+			"model", target.Model,
+			"hint", "The target's model is not listed by the endpoint's /v1/models; check the model name")
+		metrics.TargetModelPresent.With(labels).Set(0)
+		return false
+	}
+
+	metrics.TargetModelPresent.With(labels).Set(1)
+	return true
+}
+
+// DryRun logs the fully-assembled guidellm command for a target, with the
+// API key redacted, without spawning anything
+func (r *Runner) DryRun(envName string, target config.Target, logger *slog.Logger) {
+	_, keySource := resolveAPIKey(target)
+	logger.Info("dry run: guidellm command",
+		"environment", envName,
+		"target", target.Name,
+		"model", target.Model,
+		"api_key_source", keySource,
+		"argv", r.redactedCommand(target))
+}
+
+// redactedCommand returns the guidellm command line a run of target
+// executes, with the API key redacted and a placeholder output directory
+func (r *Runner) redactedCommand(target config.Target) []string {
+	apiKey, _ := resolveAPIKey(target)
+	args := r.buildArgs(target, "<output-dir>", apiKey)
+	return redactArgs(append([]string{r.binary}, args...), apiKey)
+}
+
+// resolveAPIKey returns the API key for a target and where it came from:
+// the target config, the OPENAI_API_KEY environment variable, or none
+func resolveAPIKey(target config.Target) (key string, source string) {
+	if target.APIKey != "" {
+		return target.APIKey, "target"
+	}
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		return key, "OPENAI_API_KEY"
+	}
+	return "", "none"
 }
 
 // buildArgs constructs the GuideLLM CLI arguments
 func (r *Runner) buildArgs(target config.Target, outputDir string, apiKey string) []string {
+	// Targets are validated to have encodable kwargs, so this only falls
+	// back for targets that skipped validation
+	backendKwargs, err := target.GetBackendKwargs(r.cfg.Defaults)
+	if err != nil {
+		r.logger.Warn("ignoring backend_kwargs", "target", target.Name, "error", err)
+		backendKwargs, _ = (&config.Target{}).GetBackendKwargs(config.Defaults{})
+	}
+
 	args := []string{
 		"benchmark",
 		"--target", target.URL,
 		"--model", target.Model,
 		"--profile", target.GetProfile(r.cfg.Defaults),
-		"--rate", fmt.Sprintf("%g", target.GetRate(r.cfg.Defaults)),
+	}
+	// Rate-less profiles take no --rate; the rate otherwise always resolves,
+	// falling back to the positive default
+	if config.ProfileUsesRate(target.GetProfile(r.cfg.Defaults)) {
+		args = append(args, "--rate", fmt.Sprintf("%g", target.GetRate(r.cfg.Defaults)))
+	}
+	args = append(args,
 		"--max-seconds", fmt.Sprintf("%d", target.GetMaxSeconds(r.cfg.Defaults)),
-		"--data", r.cfg.Defaults.DataSpec,
+		"--data", target.GetDataSpec(r.cfg.Defaults),
 		"--output-dir", outputDir,
 		"--outputs", "json",
-		"--backend-kwargs", `{"validate_backend": false}`,
+		"--backend-kwargs", backendKwargs,
 		"--request-type", target.GetRequestType(r.cfg.Defaults),
-		// Use gpt2 processor to avoid needing model-specific tokenizers
-		// (many models like mistral need sentencepiece which isn't installed)
-		"--processor", "gpt2",
-	}
+		"--processor", target.GetProcessor(r.cfg.Defaults),
+	)
 
 	// Build request-formatter-kwargs with:
-	// - stream: false (streaming causes 502 errors with vLLM)
+	// - stream: false unless enabled per target (streaming causes 502 errors
+	//   with some vLLM deployments)
+	// - content_format: string for chat targets using the text formatter, so
+	//   messages carry plain string content instead of multimodal arrays
 	// - Authorization header (guidellm doesn't read OPENAI_API_KEY env var)
+	stream := target.GetStream()
+	var contentFormat string
+	if target.GetRequestType(r.cfg.Defaults) == "chat_completions" && target.GetChatFormatter(r.cfg.Defaults) == config.ChatFormatterText {
+		contentFormat = `, "content_format": "string"`
+	}
 	if apiKey != "" {
-		formatterKwargs := fmt.Sprintf(`{"stream": false, "extras": {"headers": {"Authorization": "Bearer %s"}}}`, apiKey)
+		formatterKwargs := fmt.Sprintf(`{"stream": %t%s, "extras": {"headers": {"Authorization": "Bearer %s"}}}`, stream, contentFormat, apiKey)
 		args = append(args, "--request-formatter-kwargs", formatterKwargs)
 	} else {
-		args = append(args, "--request-formatter-kwargs", `{"stream": false}`)
+		args = append(args, "--request-formatter-kwargs", fmt.Sprintf(`{"stream": %t%s}`, stream, contentFormat))
 	}
 
+	// Flags the runner doesn't model, validated not to repeat the above
+	args = append(args, target.ExtraArgs...)
+
 	return args
 }
 
+// maxObservationsPerRun returns the histogram observation warning threshold
+func (r *Runner) maxObservationsPerRun() int {
+	if r.cfg.Defaults.MaxObservationsPerRun > 0 {
+		return r.cfg.Defaults.MaxObservationsPerRun
+	}
+	return config.DefaultMaxObservationsPerRun
+}
+
 // updateMetrics updates Prometheus metrics from parsed results
-func (r *Runner) updateMetrics(labels map[string]string, results *parser.ParsedResults) {
+func (r *Runner) updateMetrics(labels map[string]string, results *parser.ParsedResults, exemplar prometheus.Labels, logger *slog.Logger) {
 	// Request counters
 	metrics.RequestsTotal.With(labels).Add(float64(results.TotalRequests))
 	metrics.RequestsSuccessful.With(labels).Add(float64(results.SuccessfulRequests))
@@ -222,14 +598,60 @@ func (r *Runner) updateMetrics(labels map[string]string, results *parser.ParsedR
 	metrics.OutputTokensPerSecond.With(labels).Set(results.OutputTokensPerSec)
 	metrics.RequestsPerSecond.With(labels).Set(results.RequestsPerSec)
 
+	successRate := 0.0
+	if results.TotalRequests > 0 {
+		successRate = float64(results.SuccessfulRequests) / float64(results.TotalRequests)
+	}
+	metrics.RequestSuccessRate.With(labels).Set(successRate)
+
 	// Latency histograms
+	observations := len(results.TTFTValues) + len(results.ITLValues) + len(results.E2EValues)
+	metrics.HistogramObservations.With(labels).Set(float64(observations))
+	if limit := r.maxObservationsPerRun(); observations > limit {
+		logger.Warn("benchmark run recorded an unusually large number of histogram observations",
+			"observations", observations,
+			"threshold", limit)
+	}
+
 	for _, v := range results.TTFTValues {
-		metrics.TimeToFirstToken.With(labels).Observe(v)
+		observe(metrics.TimeToFirstToken.With(labels), v, exemplar)
 	}
 	for _, v := range results.ITLValues {
-		metrics.InterTokenLatency.With(labels).Observe(v)
+		observe(metrics.InterTokenLatency.With(labels), v, exemplar)
 	}
 	for _, v := range results.E2EValues {
-		metrics.EndToEndLatency.With(labels).Observe(v)
+		observe(metrics.EndToEndLatency.With(labels), v, exemplar)
+	}
+}
+
+// runLabels returns the labels of a run's request, token, throughput and
+// latency metrics, with its rate and profile only if the target opted in
+func (r *Runner) runLabels(labels prometheus.Labels, target config.Target) prometheus.Labels {
+	if !target.SweepLabels {
+		return metrics.RunLabels(labels, "", "")
+	}
+	rate := fmt.Sprintf("%g", target.GetRate(r.cfg.Defaults))
+	return metrics.RunLabels(labels, rate, target.GetProfile(r.cfg.Defaults))
+}
+
+// exemplar returns the exemplar labels attached to a run's histogram
+// observations, or nil if exemplars are disabled. Runs without a run ID
+// are identified by their start time.
+func (r *Runner) exemplar(runID string) prometheus.Labels {
+	if !r.cfg.Prometheus.Exemplars {
+		return nil
+	}
+	if runID == "" {
+		runID = "scheduled-" + time.Now().UTC().Format("20060102T150405Z")
+	}
+	return prometheus.Labels{"run_id": runID}
+}
+
+// observe records v on a histogram, with the exemplar if there is one
+func observe(o prometheus.Observer, v float64, exemplar prometheus.Labels) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		eo.ObserveWithExemplar(v, exemplar)
+		return
 	}
+	o.Observe(v)
 }
@@ -1,13 +1,30 @@
 package runner
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/discovery"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+	"github.com/yourorg/guidellm-runner/internal/parser"
 )
 
 // TestAPIKeyHandling verifies that API keys are correctly passed to the guidellm subprocess
@@ -213,6 +230,7 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 		name     string
 		target   config.Target
 		expected map[string]string // flag -> expected value
+		absent   []string          // flags that must not be passed
 	}{
 		{
 			name: "uses defaults",
@@ -228,6 +246,7 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 				"--rate":        "10",
 				"--max-seconds": "30",
 				"--data":        "prompt_tokens=256,output_tokens=128",
+				"--processor":   "gpt2",
 			},
 		},
 		{
@@ -239,6 +258,7 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 				Profile:    "poisson",
 				Rate:       floatPtr(5.0),
 				MaxSeconds: intPtr(60),
+				Processor:  "mistralai/Mistral-7B-v0.1",
 			},
 			expected: map[string]string{
 				"--target":      "http://override:8000/v1",
@@ -246,8 +266,37 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 				"--profile":     "poisson",
 				"--rate":        "5",
 				"--max-seconds": "60",
+				"--processor":   "mistralai/Mistral-7B-v0.1",
 			},
 		},
+		{
+			name: "fractional rate",
+			target: config.Target{
+				Name:    "poisson-target",
+				URL:     "http://localhost:8000/v1",
+				Model:   "test-model",
+				Profile: "poisson",
+				Rate:    floatPtr(2.5),
+			},
+			expected: map[string]string{
+				"--profile": "poisson",
+				"--rate":    "2.5",
+			},
+		},
+		{
+			name: "rate-less profile omits rate",
+			target: config.Target{
+				Name:    "throughput-target",
+				URL:     "http://localhost:8000/v1",
+				Model:   "test-model",
+				Profile: "throughput",
+			},
+			expected: map[string]string{
+				"--profile":     "throughput",
+				"--max-seconds": "30",
+			},
+			absent: []string{"--rate"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -274,6 +323,11 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 					t.Errorf("Flag %s: expected %s, got %s", flag, expectedValue, actualValue)
 				}
 			}
+			for _, flag := range tt.absent {
+				if slices.Contains(args, flag) {
+					t.Errorf("Expected no %s flag in args: %v", flag, args)
+				}
+			}
 
 			// Verify output-dir is set
 			if argsMap["--output-dir"] != tmpDir {
@@ -286,7 +340,7 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 			}
 
 			// Verify backend-kwargs
-			expectedKwargs := `{"validate_backend": false}`
+			expectedKwargs := `{"validate_backend":false}`
 			if argsMap["--backend-kwargs"] != expectedKwargs {
 				t.Errorf("Expected --backend-kwargs=%s, got %s", expectedKwargs, argsMap["--backend-kwargs"])
 			}
@@ -294,6 +348,39 @@ func TestBuildArgsWithDefaults(t *testing.T) {
 	}
 }
 
+// TestBackendKwargs verifies that backend kwargs from the defaults and the
+// target are merged over the default
+func TestBackendKwargs(t *testing.T) {
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:       "constant",
+			Rate:          1,
+			MaxSeconds:    1,
+			BackendKwargs: map[string]interface{}{"timeout": 30, "validate_backend": true},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	runner := New(cfg, logger)
+
+	target := config.Target{
+		Name:          "test-target",
+		URL:           "http://test.local/v1",
+		Model:         "test-model",
+		BackendKwargs: map[string]interface{}{"timeout": 120, "http2": true},
+	}
+	args := runner.buildArgs(target, t.TempDir(), "")
+
+	var kwargs string
+	for i, arg := range args {
+		if arg == "--backend-kwargs" && i+1 < len(args) {
+			kwargs = args[i+1]
+		}
+	}
+	if want := `{"http2":true,"timeout":120,"validate_backend":true}`; kwargs != want {
+		t.Errorf("expected backend kwargs %s, got %s", want, kwargs)
+	}
+}
+
 // TestRequestTypeConfiguration verifies that request type is correctly configured
 func TestRequestTypeConfiguration(t *testing.T) {
 	tests := []struct {
@@ -371,6 +458,752 @@ func TestRequestTypeConfiguration(t *testing.T) {
 	}
 }
 
+// TestStreamFormatterKwargs verifies that the per-target stream option is
+// passed through to the request formatter
+func TestStreamFormatterKwargs(t *testing.T) {
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "chat_completions",
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	runner := New(cfg, logger)
+
+	enabled := true
+	tests := []struct {
+		name     string
+		stream   *bool
+		apiKey   string
+		expected string
+	}{
+		{"disabled by default", nil, "", `"stream": false`},
+		{"enabled", &enabled, "", `"stream": true`},
+		{"enabled with api key", &enabled, "sk-test", `"stream": true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := config.Target{
+				Name:   "test-target",
+				URL:    "http://test.local/v1",
+				Model:  "test-model",
+				Stream: tt.stream,
+			}
+			args := runner.buildArgs(target, t.TempDir(), tt.apiKey)
+
+			var kwargs string
+			for i, arg := range args {
+				if arg == "--request-formatter-kwargs" && i+1 < len(args) {
+					kwargs = args[i+1]
+				}
+			}
+			if !strings.Contains(kwargs, tt.expected) {
+				t.Errorf("expected formatter kwargs to contain %s, got %s", tt.expected, kwargs)
+			}
+			if !json.Valid([]byte(kwargs)) {
+				t.Errorf("formatter kwargs are not valid JSON: %s", kwargs)
+			}
+		})
+	}
+}
+
+// TestChatFormatterKwargs verifies that the text chat formatter asks for
+// plain string content, and only for chat_completions targets
+func TestChatFormatterKwargs(t *testing.T) {
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:       "constant",
+			Rate:          1,
+			MaxSeconds:    1,
+			DataSpec:      "prompt_tokens=10,output_tokens=10",
+			RequestType:   "chat_completions",
+			ChatFormatter: config.ChatFormatterText,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	runner := New(cfg, logger)
+
+	tests := []struct {
+		name   string
+		target config.Target
+		apiKey string
+		want   bool
+	}{
+		{"text formatter", config.Target{}, "", true},
+		{"text formatter with api key", config.Target{}, "sk-test", true},
+		{"multimodal override", config.Target{ChatFormatter: config.ChatFormatterMultimodal}, "", false},
+		{"text completions", config.Target{RequestType: "text_completions"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.target.Name = "test-target"
+			tt.target.URL = "http://test.local/v1"
+			tt.target.Model = "test-model"
+			args := runner.buildArgs(tt.target, t.TempDir(), tt.apiKey)
+
+			var kwargs string
+			for i, arg := range args {
+				if arg == "--request-formatter-kwargs" && i+1 < len(args) {
+					kwargs = args[i+1]
+				}
+			}
+			if got := strings.Contains(kwargs, `"content_format": "string"`); got != tt.want {
+				t.Errorf("expected content_format=%v, got kwargs %s", tt.want, kwargs)
+			}
+			if !json.Valid([]byte(kwargs)) {
+				t.Errorf("formatter kwargs are not valid JSON: %s", kwargs)
+			}
+		})
+	}
+}
+
+// TestExtraArgs verifies that extra args are appended after the structured
+// flags, and that every flag the runner sets is reserved from extra_args
+func TestExtraArgs(t *testing.T) {
+	cfg := &config.Config{Defaults: config.Defaults{Profile: "constant", Rate: 1, MaxSeconds: 1}}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	runner := New(cfg, logger)
+
+	target := config.Target{
+		Name:      "test-target",
+		URL:       "http://test.local/v1",
+		Model:     "test-model",
+		ExtraArgs: []string{"--warmup", "0.1"},
+	}
+	args := runner.buildArgs(target, t.TempDir(), "sk-test")
+
+	if got := args[len(args)-2:]; got[0] != "--warmup" || got[1] != "0.1" {
+		t.Errorf("expected extra args last, got %v", args)
+	}
+	for _, arg := range args[:len(args)-2] {
+		if strings.HasPrefix(arg, "--") && !slices.Contains(config.ReservedFlags, arg) {
+			t.Errorf("flag %s set by the runner is missing from config.ReservedFlags", arg)
+		}
+	}
+}
+
+// TestUnstreamedRunKeepsTokenLatencies verifies that TTFT and ITL reported
+// by guidellm are kept for targets that don't stream, as they were before
+// streaming could be enabled
+func TestUnstreamedRunKeepsTokenLatencies(t *testing.T) {
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `while [ $# -gt 0 ]; do
+  if [ "$1" = "--output-dir" ]; then cat > "$2/benchmarks.json" <<'JSON'
+{"benchmarks": [{
+  "scheduler_state": {"created_requests": 1, "successful_requests": 1},
+  "metrics": {
+    "time_to_first_token_ms": {"successful": {"mean": 50, "count": 1, "percentiles": {"p50": 50, "p95": 50, "p99": 50}}},
+    "inter_token_latency_ms": {"successful": {"mean": 10, "count": 1, "percentiles": {"p50": 10, "p95": 10, "p99": 10}}}
+  }
+}]}
+JSON
+  fi
+  shift
+done`),
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "unstreamed", URL: "http://test.local/v1", Model: "test-model"}
+
+	output, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.results.TTFTValues) == 0 || len(output.results.ITLValues) == 0 {
+		t.Errorf("expected TTFT and ITL kept without streaming, got %d and %d values",
+			len(output.results.TTFTValues), len(output.results.ITLValues))
+	}
+}
+
+// TestDryRunLogsRedactedCommand verifies that a dry run logs the assembled
+// guidellm argv without leaking the API key
+func TestDryRunLogsRedactedCommand(t *testing.T) {
+	cfg := &config.Config{
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	runner := New(cfg, logger)
+
+	target := config.Target{
+		Name:   "test-target",
+		URL:    "http://test.local/v1",
+		Model:  "test-model",
+		APIKey: "secret-key-12345",
+	}
+	runner.DryRun("test", target, logger)
+
+	out := buf.String()
+	if strings.Contains(out, target.APIKey) {
+		t.Errorf("dry run output leaked the API key: %s", out)
+	}
+	for _, want := range []string{`"argv":["guidellm","benchmark"`, "Bearer " + redactedValue, `"api_key_source":"target"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in dry run output: %s", want, out)
+		}
+	}
+}
+
+// TestCheckModelPresence verifies that a model missing from the target's
+// /v1/models listing is detected and reported via the model-present gauge
+func TestCheckModelPresence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected probe path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(discovery.ModelsResponse{
+			Object: "list",
+			Data:   []discovery.ModelInfo{{ID: "served-model", ModelType: "text"}},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Defaults: config.Defaults{CheckModelOnZeroRequests: true}}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	runner := New(cfg, logger)
+
+	tests := []struct {
+		model   string
+		present bool
+	}{
+		{model: "missing-model", present: false},
+		{model: "served-model", present: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			target := config.Target{
+				Name:  "check-" + tt.model,
+				URL:   server.URL + "/v1/chat/completions",
+				Model: tt.model,
+			}
+			labels := metrics.Labels("test", target.Name, target.Model)
+
+			got := runner.checkModelPresence(context.Background(), labels, target, "", logger)
+			if got != tt.present {
+				t.Errorf("expected present=%v, got %v", tt.present, got)
+			}
+
+			want := 0.0
+			if tt.present {
+				want = 1
+			}
+			if v := testutil.ToFloat64(metrics.TargetModelPresent.With(labels)); v != want {
+				t.Errorf("expected gauge %v, got %v", want, v)
+			}
+		})
+	}
+}
+
+// TestDiagnoseTarget verifies that a diagnosis logs the status and body of
+// the target's answers to the models listing and a test completion, with
+// the API key redacted
+func TestDiagnoseTarget(t *testing.T) {
+	var completion map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"object": "list", "data": []}`))
+		case "/v1/chat/completions":
+			json.NewDecoder(r.Body).Decode(&completion)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model not found (key sk-diagnose)"}`))
+		default:
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	runner := New(&config.Config{Defaults: config.Defaults{DiagnoseFailures: true}}, logger)
+	target := config.Target{Name: "diagnosed", URL: server.URL + "/v1", Model: "missing-model"}
+	runner.diagnoseTarget(context.Background(), target, "sk-diagnose", logger)
+
+	if completion["model"] != "missing-model" || completion["messages"] == nil {
+		t.Errorf("expected a chat completion test request for the target's model, got %v", completion)
+	}
+	out := logs.String()
+	for _, want := range []string{"check=models", "status=200", "check=completion", "status=404", "model not found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in diagnosis logs: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "sk-diagnose") {
+		t.Errorf("expected the API key redacted from the diagnosis logs: %s", out)
+	}
+}
+
+// TestResolveBinary verifies guidellm binary validation at startup
+func TestResolveBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	executable := filepath.Join(dir, "guidellm")
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "guidellm-noexec")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := ResolveBinary(executable)
+	if err != nil {
+		t.Fatalf("expected executable to resolve, got %v", err)
+	}
+	if path != executable {
+		t.Errorf("expected %s, got %s", executable, path)
+	}
+
+	// Names without a slash are looked up on PATH
+	t.Setenv("PATH", dir)
+	if path, err := ResolveBinary("guidellm"); err != nil || path != executable {
+		t.Errorf("expected PATH lookup to find %s, got %s (%v)", executable, path, err)
+	}
+
+	// Relative paths are made absolute, so they still resolve from work_dir
+	t.Chdir(dir)
+	if path, err := ResolveBinary("./guidellm"); err != nil || path != executable {
+		t.Errorf("expected ./guidellm to resolve to %s, got %s (%v)", executable, path, err)
+	}
+
+	for _, bad := range []string{notExecutable, filepath.Join(dir, "missing"), "no-such-guidellm"} {
+		if _, err := ResolveBinary(bad); err == nil {
+			t.Errorf("expected error resolving %s", bad)
+		}
+	}
+}
+
+// TestSubprocessEnvironment verifies that guidellm runs in the configured
+// working directory and only sees the allowed environment variables
+func TestSubprocessEnvironment(t *testing.T) {
+	t.Setenv("GUIDELLM_TEST_ALLOWED", "yes")
+	t.Setenv("GUIDELLM_TEST_SECRET", "leaked")
+	t.Setenv("UNRELATED_TOKEN", "leaked")
+
+	workDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "env")
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `{ pwd; env; } > `+out+`; echo "guidellm version 0.5.0"`),
+		Subprocess: config.SubprocessConfig{
+			WorkDir:  workDir,
+			EnvAllow: []string{"PATH", "GUIDELLM_TEST_*"},
+			EnvDeny:  []string{"GUIDELLM_TEST_SECRET"},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	if _, err := New(cfg, logger).DetectVersion(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	if lines[0] != workDir {
+		t.Errorf("expected guidellm to run in %s, got %s", workDir, lines[0])
+	}
+	if !slices.Contains(lines, "GUIDELLM_TEST_ALLOWED=yes") {
+		t.Errorf("expected allowed variable to pass through, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "leaked") {
+		t.Errorf("expected denied and unlisted variables to be withheld, got:\n%s", data)
+	}
+}
+
+// TestRunBenchmarkTimeout verifies that a hung guidellm is killed once the
+// run timeout fires and the run is reported as timed out
+func TestRunBenchmarkTimeout(t *testing.T) {
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, "exec sleep 30"),
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			RunTimeout:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	runner := New(cfg, logger)
+
+	target := config.Target{Name: "hung-target", URL: "http://test.local/v1", Model: "test-model"}
+
+	start := time.Now()
+	output, _ := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	elapsed := time.Since(start)
+
+	if output != nil {
+		t.Errorf("expected no results from a timed out run, got %+v", output)
+	}
+	if elapsed > 10*time.Second {
+		t.Errorf("run was not cut off by the timeout, took %s", elapsed)
+	}
+	if !strings.Contains(buf.String(), "guidellm run timed out") {
+		t.Errorf("expected timeout-specific log, got: %s", buf.String())
+	}
+	failed := metrics.BenchmarkRunsFailed.With(metrics.FailureLabels(metrics.Labels("test", target.Name, target.Model), string(FailureTimeout)))
+	if got := testutil.ToFloat64(failed); got != 1 {
+		t.Errorf("expected 1 timeout failure, got %v", got)
+	}
+}
+
+// TestMaxConcurrentRunsQueues verifies that runs beyond max_concurrent_runs
+// wait for a slot, and give up if their context is cancelled while waiting
+func TestMaxConcurrentRunsQueues(t *testing.T) {
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, "exec sleep 1"),
+		Defaults: config.Defaults{
+			Profile:           "constant",
+			Rate:              1,
+			MaxSeconds:        1,
+			DataSpec:          "prompt_tokens=10,output_tokens=10",
+			RequestType:       "text_completions",
+			MaxConcurrentRuns: 1,
+		},
+	}
+
+	var buf syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "busy-target", URL: "http://test.local/v1", Model: "test-model"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runner.InFlight() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("first run never took a slot")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The second run queues behind the first and is cancelled before a slot frees
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if output, err := runner.runBenchmarkWithResults(ctx, "test", target, "", logger); output != nil || err != ctx.Err() {
+		t.Errorf("expected cancellation from a cancelled queued run, got %+v, %v", output, err)
+	}
+	if got := runner.InFlight(); got != 1 {
+		t.Errorf("expected 1 run in flight while queued run waits, got %d", got)
+	}
+	if !strings.Contains(buf.String(), "waiting for a run slot") {
+		t.Errorf("expected queued cancellation log, got: %s", buf.String())
+	}
+
+	<-done
+	if got := runner.InFlight(); got != 0 {
+		t.Errorf("expected no runs in flight after completion, got %d", got)
+	}
+}
+
+// TestKeepRawOutput verifies that with keep_raw_output set, guidellm's
+// output survives the temp dir, even when it fails to parse
+func TestKeepRawOutput(t *testing.T) {
+	rawDir := filepath.Join(t.TempDir(), "raw")
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `while [ $# -gt 0 ]; do
+  if [ "$1" = "--output-dir" ]; then echo '{"unexpected": "schema"' > "$2/benchmarks-run42.json"; fi
+  shift
+done`),
+		KeepRawOutput: true,
+		RawOutputDir:  rawDir,
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "org/model", URL: "http://test.local/v1", Model: "test-model"}
+
+	if _, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger); err == nil {
+		t.Fatal("expected a parse failure")
+	}
+
+	kept, err := filepath.Glob(filepath.Join(rawDir, "org_model-*.json"))
+	if err != nil || len(kept) != 1 {
+		t.Fatalf("expected one kept output file, got %v (%v)", kept, err)
+	}
+	data, err := os.ReadFile(kept[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "unexpected") {
+		t.Errorf("unexpected kept output %q", data)
+	}
+	if !strings.Contains(buf.String(), "kept raw guidellm output") {
+		t.Errorf("expected retained path to be logged, got: %s", buf.String())
+	}
+}
+
+// TestOutputBeyondTailStreamed verifies that output beyond the tail bound
+// isn't held in memory, while the kept log still gets all of it, redacted
+func TestOutputBeyondTailStreamed(t *testing.T) {
+	rawDir := filepath.Join(t.TempDir(), "raw")
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `yes "progress: Authorization: Bearer sk-streamed" | head -n 20000
+echo 'RuntimeError: benchmark failed' >&2
+exit 1`),
+		KeepRawOutput: true,
+		RawOutputDir:  rawDir,
+		Subprocess:    config.SubprocessConfig{OutputTailBytes: 4096},
+		Defaults: config.Defaults{
+			Profile:     "constant",
+			Rate:        1,
+			MaxSeconds:  1,
+			DataSpec:    "prompt_tokens=10,output_tokens=10",
+			RequestType: "text_completions",
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runner := New(cfg, logger)
+	target := config.Target{Name: "chatty", URL: "http://test.local/v1", Model: "test-model"}
+
+	_, err := runner.runBenchmarkWithResults(context.Background(), "test", target, "", logger)
+	var runErr *RunError
+	if !errors.As(err, &runErr) {
+		t.Fatalf("expected a RunError, got %v", err)
+	}
+	if runErr.Detail != "RuntimeError: benchmark failed" {
+		t.Errorf("expected the stderr error as detail, got %q", runErr.Detail)
+	}
+	if strings.Count(runErr.Stdout, "\n") != maxTailLines-1 || strings.Contains(runErr.Stdout, "sk-streamed") {
+		t.Errorf("expected %d redacted stdout lines, got %q", maxTailLines, runErr.Stdout)
+	}
+
+	kept, err := filepath.Glob(filepath.Join(rawDir, "chatty-*.log"))
+	if err != nil || len(kept) != 1 {
+		t.Fatalf("expected one kept log, got %v (%v)", kept, err)
+	}
+	data, err := os.ReadFile(kept[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "Bearer REDACTED"); got != 20000 {
+		t.Errorf("expected all 20000 progress lines kept redacted, got %d", got)
+	}
+	if strings.Contains(string(data), "sk-streamed") || !strings.Contains(string(data), "RuntimeError") {
+		t.Errorf("expected the kept log redacted and to include stderr")
+	}
+
+	// However much is written, the buffer stays within its bound
+	buf := newTailBuffer(4096)
+	line := []byte(strings.Repeat("x", 99) + "\n")
+	for i := 0; i < 10000; i++ {
+		buf.Write(line)
+	}
+	if len(buf.buf) != 4096 || cap(buf.buf) > 2*4096 {
+		t.Errorf("expected the buffer bounded to 4096 bytes, got len %d cap %d", len(buf.buf), cap(buf.buf))
+	}
+}
+
+// TestFindOutputFile verifies that guidellm's output is found whatever it
+// is named, preferring the newest JSON file
+func TestFindOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := findOutputFile(dir); err == nil {
+		t.Error("expected an error for an empty output dir")
+	}
+
+	write := func(name string, mod time.Time) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	now := time.Now()
+	named := write("benchmarks_run-1a2b3c.json", now.Add(-time.Minute))
+	write("guidellm.log", now)
+	if got, err := findOutputFile(dir); err != nil || got != named {
+		t.Errorf("expected %s, got %s (%v)", named, got, err)
+	}
+
+	newest := write("report.json", now)
+	if got, err := findOutputFile(dir); err != nil || got != newest {
+		t.Errorf("expected newest file %s, got %s (%v)", newest, got, err)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes from loggers
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestUpdateMetricsSuccessRate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runner := New(&config.Config{}, logger)
+	labels := metrics.RunLabels(metrics.Labels("test", "success-rate-target", "test-model"), "", "")
+
+	tests := []struct {
+		name       string
+		total      int
+		successful int
+		want       float64
+	}{
+		{"partial success", 8, 6, 0.75},
+		{"no requests", 0, 0, 0},
+		{"all succeeded", 5, 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := &parser.ParsedResults{TotalRequests: tt.total, SuccessfulRequests: tt.successful}
+			runner.updateMetrics(labels, results, nil, logger)
+			if got := testutil.ToFloat64(metrics.RequestSuccessRate.With(labels)); got != tt.want {
+				t.Errorf("expected success rate %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunLabels(t *testing.T) {
+	runner := New(&config.Config{Defaults: config.Defaults{Profile: "constant", Rate: 2}}, slog.Default())
+	labels := metrics.Labels("test", "labels-target", "test-model")
+	rate := 2.5
+
+	target := config.Target{Rate: &rate}
+	if got := runner.runLabels(labels, target); got["rate"] != "" || got["profile"] != "" {
+		t.Errorf("expected empty rate and profile labels without sweep_labels, got %v", got)
+	}
+	target.SweepLabels = true
+	if got := runner.runLabels(labels, target); got["rate"] != "2.5" || got["profile"] != "constant" {
+		t.Errorf("expected rate 2.5 and profile constant labels, got %v", got)
+	}
+}
+
+func TestUpdateMetricsWarnsOnExcessObservations(t *testing.T) {
+	cfg := &config.Config{Defaults: config.Defaults{MaxObservationsPerRun: 150}}
+	labels := metrics.RunLabels(metrics.Labels("test", "observations-target", "test-model"), "", "")
+
+	tests := []struct {
+		name     string
+		values   int
+		wantWarn bool
+	}{
+		{"within threshold", 100, false},
+		{"oversized count", 1000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			runner := New(cfg, logger)
+
+			results := &parser.ParsedResults{E2EValues: make([]float64, tt.values)}
+			runner.updateMetrics(labels, results, nil, logger)
+
+			warned := strings.Contains(buf.String(), "unusually large number of histogram observations")
+			if warned != tt.wantWarn {
+				t.Errorf("expected warning=%v, got log: %s", tt.wantWarn, buf.String())
+			}
+			if got := testutil.ToFloat64(metrics.HistogramObservations.With(labels)); got != float64(tt.values) {
+				t.Errorf("expected observations gauge %d, got %v", tt.values, got)
+			}
+		})
+	}
+}
+
+// TestExemplars verifies that latency observations carry the run ID as an
+// exemplar when exemplars are enabled
+func TestExemplars(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if got := New(&config.Config{}, logger).exemplar("run-1"); got != nil {
+		t.Errorf("expected no exemplar when disabled, got %v", got)
+	}
+
+	runner := New(&config.Config{Prometheus: config.PrometheusConfig{Exemplars: true}}, logger)
+	if got := runner.exemplar(""); !strings.HasPrefix(got["run_id"], "scheduled-") {
+		t.Errorf("expected a scheduled run ID, got %v", got)
+	}
+
+	labels := metrics.RunLabels(metrics.Labels("test", "exemplar-target", "test-model"), "", "")
+	results := &parser.ParsedResults{E2EValues: []float64{0.3}}
+	runner.updateMetrics(labels, results, runner.exemplar("run-42"), logger)
+
+	// Exemplars are only exposed in the OpenMetrics format
+	handler := promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if strings.HasPrefix(line, "guidellm_e2e_latency_seconds_bucket{") &&
+			strings.Contains(line, `target="exemplar-target"`) &&
+			strings.Contains(line, `# {run_id="run-42"} 0.3`) {
+			return
+		}
+	}
+	t.Errorf("no run_id exemplar on guidellm_e2e_latency_seconds buckets:\n%s", rec.Body.String())
+}
+
+// writeFakeGuidellm writes an executable shell script standing in for
+// guidellm and returns its path
+func writeFakeGuidellm(t *testing.T, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "guidellm")
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
 // Helper function to create int pointer
 func intPtr(i int) *int {
 	return &i
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+// runRegistry tracks recent scheduled and manual runs by ID, oldest first,
+// keeping at most limit. Guarded by the manager's mu.
+type runRegistry struct {
+	limit int
+	runs  map[string]*api.RunResponse
+	order []string
+}
+
+// newRunRegistry creates a registry keeping the latest limit runs, or
+// config.DefaultRunRetention if limit isn't positive
+func newRunRegistry(limit int) runRegistry {
+	if limit <= 0 {
+		limit = config.DefaultRunRetention
+	}
+	return runRegistry{limit: limit, runs: make(map[string]*api.RunResponse)}
+}
+
+// add registers a run, evicting the oldest runs beyond the limit
+func (r *runRegistry) add(run *api.RunResponse) {
+	r.runs[run.RunID] = run
+	r.order = append(r.order, run.RunID)
+	for len(r.order) > r.limit {
+		delete(r.runs, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// get returns a copy of the run with the given ID
+func (r *runRegistry) get(id string) (*api.RunResponse, bool) {
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, false
+	}
+	runCopy := *run
+	return &runCopy, true
+}
+
+// list returns copies of the runs matching target and status, newest
+// first. Empty filters match any run.
+func (r *runRegistry) list(target string, status api.RunStatus) []api.RunResponse {
+	runs := []api.RunResponse{}
+	for i := len(r.order) - 1; i >= 0; i-- {
+		run := r.runs[r.order[i]]
+		if (target == "" || run.Target == target) && (status == "" || run.Status == status) {
+			runs = append(runs, *run)
+		}
+	}
+	return runs
+}
+
+// beginRunRecord marks a run of target as running in the registry: a
+// pending run registered by StartRun, or otherwise a new one. Must be called
+// with m.mu held for writing.
+func (m *DefaultTargetManager) beginRunRecord(runID string, target config.Target, trigger string) (*api.RunResponse, error) {
+	now := time.Now()
+	run, exists := m.runs.runs[runID]
+	if exists {
+		if run.Status != api.RunStatusPending || run.Target != target.Name {
+			return nil, fmt.Errorf("run %q already exists: %w", runID, api.ErrConflict)
+		}
+	} else {
+		run = &api.RunResponse{
+			RunID:     runID,
+			Target:    target.Name,
+			Trigger:   trigger,
+			CreatedAt: now,
+		}
+		m.runs.add(run)
+	}
+
+	run.Status = api.RunStatusRunning
+	run.StartedAt = &now
+	run.Profile = target.GetProfile(m.cfg.Defaults)
+	run.Rate = target.GetRate(m.cfg.Defaults)
+	return run, nil
+}
+
+// finishRunRecord records the outcome of a run in the registry. Must be
+// called with m.mu held for writing.
+func (m *DefaultTargetManager) finishRunRecord(run *api.RunResponse, output *runOutput, err error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	if output != nil {
+		run.Results = output.results
+	}
+	switch {
+	case err != nil:
+		run.Status = api.RunStatusFailed
+		run.Error = err.Error()
+	case output == nil:
+		run.Status = api.RunStatusFailed
+		run.Error = "benchmark produced no results"
+	default:
+		run.Status = api.RunStatusDone
+	}
+}
+
+// validateRunOverrides checks a manual run's overrides of target, which may
+// be nil. A rate is checked against the profile the run will use, the
+// target's own unless overridden too.
+func validateRunOverrides(cfg *config.Config, target config.Target, o *api.RunOverrides) error {
+	if o == nil {
+		return nil
+	}
+	if o.Rate != nil && *o.Rate <= 0 {
+		return fmt.Errorf("%w config_overrides: rate must be positive", api.ErrInvalid)
+	}
+	if o.MaxSeconds != nil && *o.MaxSeconds <= 0 {
+		return fmt.Errorf("%w config_overrides: max_seconds must be positive", api.ErrInvalid)
+	}
+	profile := target.GetProfile(cfg.Defaults)
+	if o.Profile != "" {
+		if err := cfg.ValidateProfile(o.Profile); err != nil {
+			return fmt.Errorf("%w config_overrides: %w", api.ErrInvalid, err)
+		}
+		profile = o.Profile
+	}
+	if err := config.ValidateRate(profile, o.Rate); err != nil {
+		return fmt.Errorf("%w config_overrides: %w", api.ErrInvalid, err)
+	}
+	return nil
+}
+
+// applyRunOverrides returns a copy of target with a manual run's overrides
+// applied, which may be nil
+func applyRunOverrides(target config.Target, o *api.RunOverrides) config.Target {
+	if o == nil {
+		return target
+	}
+	if o.Rate != nil {
+		target.Rate = o.Rate
+	}
+	if o.MaxSeconds != nil {
+		target.MaxSeconds = o.MaxSeconds
+	}
+	if o.Profile != "" {
+		target.Profile = o.Profile
+	}
+	if o.DataSpec != "" {
+		target.DataSpec = o.DataSpec
+	}
+	return target
+}
+
+// StartRun triggers a benchmark run of a target in the background and
+// returns it as registered, pending. Its progress and results can be polled
+// with GetRun. An empty runID is generated.
+func (m *DefaultTargetManager) StartRun(ctx context.Context, name string, runID string, overrides *api.RunOverrides) (*api.RunResponse, error) {
+	if runID == "" {
+		runID = api.NewRunID()
+	}
+
+	m.mu.Lock()
+	mt, exists := m.targets[name]
+	if !exists {
+		m.mu.Unlock()
+		return nil, errTargetNotFound(name)
+	}
+	if err := validateRunOverrides(m.cfg, mt.effectiveTarget(time.Now()), overrides); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if _, exists := m.runs.get(runID); exists {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("run %q already exists: %w", runID, api.ErrConflict)
+	}
+	run := &api.RunResponse{
+		RunID:     runID,
+		Target:    name,
+		Trigger:   "manual",
+		Overrides: overrides,
+		Status:    api.RunStatusPending,
+		CreatedAt: time.Now(),
+	}
+	m.runs.add(run)
+	resp := *run
+	m.mu.Unlock()
+
+	// The run outlives the request that started it; StopAll still cancels it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		_, err := m.TriggerRun(ctx, name, runID, overrides)
+
+		// TriggerRun records the outcome of runs it starts; a run that
+		// failed before starting is still pending
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if err != nil && run.Status == api.RunStatusPending {
+			m.finishRunRecord(run, nil, err)
+		}
+	}()
+
+	return &resp, nil
+}
+
+// GetRun returns a recent run, while it is retained in the registry
+func (m *DefaultTargetManager) GetRun(runID string) (*api.RunResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	run, ok := m.runs.get(runID)
+	if !ok {
+		return nil, fmt.Errorf("run %q %w", runID, api.ErrNotFound)
+	}
+	return run, nil
+}
+
+// ListRuns returns the retained runs, newest first, optionally filtered by
+// target and status
+func (m *DefaultTargetManager) ListRuns(target string, status api.RunStatus) []api.RunResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.runs.list(target, status)
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+// runSchedule times a target's scheduled runs: one per interval, each
+// shifted by up to the configured jitter either way
+type runSchedule struct {
+	interval time.Duration
+	jitter   float64
+	next     time.Time
+}
+
+// newRunSchedule returns the schedule of a target starting at now. Its
+// first run is immediate (give or take jitter) or, without run_on_start,
+// after a random delay of up to one interval.
+func newRunSchedule(defaults config.Defaults, interval time.Duration, now time.Time) *runSchedule {
+	s := &runSchedule{interval: interval, jitter: defaults.Jitter}
+	if defaults.GetRunOnStart() {
+		s.next = now.Add(randomDuration(time.Duration(s.jitter * float64(interval))))
+	} else {
+		s.next = now.Add(randomDuration(interval))
+	}
+	return s
+}
+
+// advance moves the schedule on to the run after next, skipping any that
+// were missed by now
+func (s *runSchedule) advance(now time.Time) {
+	s.next = s.next.Add(s.jitteredInterval())
+	s.skipMissed(now)
+}
+
+// skipMissed moves the schedule past runs that were due by now, e.g. during
+// a slow run, as a ticker drops ticks for a slow receiver. It reports
+// whether any were skipped.
+func (s *runSchedule) skipMissed(now time.Time) bool {
+	skipped := false
+	for !s.next.After(now) {
+		s.next = s.next.Add(s.jitteredInterval())
+		skipped = true
+	}
+	return skipped
+}
+
+// jitteredInterval returns the interval shifted by a random amount of up to
+// the jitter fraction of it, either way. An out-of-range jitter that could
+// make it non-positive is ignored.
+func (s *runSchedule) jitteredInterval() time.Duration {
+	spread := time.Duration(s.jitter * float64(s.interval))
+	if d := s.interval - spread + randomDuration(2*spread); d > 0 {
+		return d
+	}
+	return s.interval
+}
+
+// randomDuration returns a random duration in [0, d), or 0 if d isn't
+// positive
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+func TestRunSchedule(t *testing.T) {
+	now := time.Now()
+	interval := time.Minute
+
+	if s := newRunSchedule(config.Defaults{}, interval, now); !s.next.Equal(now) {
+		t.Errorf("expected an immediate first run without jitter, got %v", s.next.Sub(now))
+	}
+
+	off := false
+	for i := 0; i < 100; i++ {
+		s := newRunSchedule(config.Defaults{Jitter: 0.1}, interval, now)
+		if d := s.next.Sub(now); d < 0 || d >= 6*time.Second {
+			t.Fatalf("expected a first run within the jitter, got %v", d)
+		}
+
+		s = newRunSchedule(config.Defaults{RunOnStart: &off}, interval, now)
+		if d := s.next.Sub(now); d < 0 || d >= interval {
+			t.Fatalf("expected a first run within one interval, got %v", d)
+		}
+	}
+
+	s := newRunSchedule(config.Defaults{Jitter: 0.1}, interval, now)
+	for i := 0; i < 100; i++ {
+		prev := s.next
+		s.advance(prev)
+		if d := s.next.Sub(prev); d < 54*time.Second || d > 66*time.Second {
+			t.Fatalf("expected runs 60s ±10%% apart, got %v", d)
+		}
+	}
+
+	// Runs missed by a slow cycle are skipped rather than made back to back
+	s = newRunSchedule(config.Defaults{}, interval, now)
+	s.advance(now.Add(150 * time.Second))
+	if d := s.next.Sub(now); d != 3*time.Minute {
+		t.Errorf("expected the next run at 3m, got %v", d)
+	}
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"time"
+)
+
+// Drain begins a graceful shutdown: target loops stop scheduling runs, and
+// runs in flight get up to grace, or until ctx is done, to finish before
+// StopAll cancels the rest. It returns how many runs finished and how many
+// were cancelled. Wait should still be called afterwards.
+func (m *DefaultTargetManager) Drain(ctx context.Context, grace time.Duration) (drained, killed int) {
+	m.drainOnce.Do(func() { close(m.draining) })
+
+	inFlight := m.runsInFlight()
+	if inFlight > 0 {
+		m.logger.Info("draining benchmark runs", "runs", inFlight, "grace", grace)
+
+		done := make(chan struct{})
+		go func() {
+			m.wg.Wait()
+			close(done)
+		}()
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+
+	killed = m.runsInFlight()
+	drained = max(inFlight-killed, 0)
+	m.StopAll()
+	if inFlight > 0 {
+		m.logger.Info("drained benchmark runs", "drained", drained, "killed", killed)
+	}
+	return drained, killed
+}
+
+// isDraining reports whether Drain has been called
+func (m *DefaultTargetManager) isDraining() bool {
+	select {
+	case <-m.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// runsInFlight counts the runs in flight across all targets
+func (m *DefaultTargetManager) runsInFlight() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n := 0
+	for _, mt := range m.targets {
+		n += mt.runsInFlight
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+)
+
+// GetMetricsSnapshot returns the values of the main per-target gauges,
+// sorted by target name. Prometheus vecs can't readily be read back, so
+// the values are derived from each target's latest run with results, which
+// set the gauges, and consecutive failure count.
+func (m *DefaultTargetManager) GetMetricsSnapshot() api.MetricsSnapshotResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	targets := make([]api.TargetMetricsSnapshot, 0, len(m.targets))
+	for _, mt := range m.targets {
+		snapshot := api.TargetMetricsSnapshot{
+			Name:                mt.target.Name,
+			Environment:         mt.environment,
+			Model:               mt.target.Model,
+			Up:                  mt.status == api.TargetStatusRunning,
+			ConsecutiveFailures: mt.consecutiveFailures,
+		}
+		if len(mt.history) > 0 {
+			latest := mt.history[len(mt.history)-1]
+			results := latest.results
+			successRate := 0.0
+			if results.TotalRequests > 0 {
+				successRate = float64(results.SuccessfulRequests) / float64(results.TotalRequests)
+			}
+			at, tokens, requests := latest.at, results.OutputTokensPerSec, results.RequestsPerSec
+			snapshot.OutputTokensPerSec = &tokens
+			snapshot.RequestsPerSec = &requests
+			snapshot.RequestSuccessRate = &successRate
+			snapshot.LastBenchmarkTimestamp = &at
+		}
+		targets = append(targets, snapshot)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	fleet, _ := m.fleetThroughput(now)
+	return api.MetricsSnapshotResponse{
+		At:                      now,
+		FleetOutputTokensPerSec: fleet,
+		Targets:                 targets,
+	}
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// The range of guidellm versions the parser is tested against: at least
+// MinTestedGuideLLMVersion and below MaxTestedGuideLLMVersion
+const (
+	MinTestedGuideLLMVersion = "0.5.0"
+	MaxTestedGuideLLMVersion = "0.6.0"
+)
+
+// versionTimeout bounds how long `guidellm --version` may take
+const versionTimeout = 30 * time.Second
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+// DetectVersion runs `guidellm --version` and records the version it
+// reports, which is returned by GuideLLMVersion
+func (r *Runner) DetectVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	out, err := r.command(ctx, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s --version failed: %w", r.binary, err)
+	}
+	version, err := ParseGuideLLMVersion(string(out))
+	if err != nil {
+		return "", err
+	}
+
+	r.versionMu.Lock()
+	r.version = version
+	r.versionMu.Unlock()
+	return version, nil
+}
+
+// GuideLLMVersion returns the version found by DetectVersion, or "" if it
+// hasn't been detected
+func (r *Runner) GuideLLMVersion() string {
+	r.versionMu.RLock()
+	defer r.versionMu.RUnlock()
+	return r.version
+}
+
+// ParseGuideLLMVersion extracts the version from `guidellm --version`
+// output, e.g. "guidellm version 0.5.0" or "0.5.0"
+func ParseGuideLLMVersion(output string) (string, error) {
+	version := versionPattern.FindString(output)
+	if version == "" {
+		return "", fmt.Errorf("no version found in guidellm --version output %q", output)
+	}
+	return version, nil
+}
+
+// CheckGuideLLMVersion returns an error if version is outside the tested
+// range, in which case guidellm's output may not parse
+func CheckGuideLLMVersion(version string) error {
+	v, err := versionKey(version)
+	if err != nil {
+		return err
+	}
+	lo, _ := versionKey(MinTestedGuideLLMVersion)
+	hi, _ := versionKey(MaxTestedGuideLLMVersion)
+	if compareVersions(v, lo) < 0 || compareVersions(v, hi) >= 0 {
+		return fmt.Errorf("guidellm %s is outside the tested range >= %s, < %s",
+			version, MinTestedGuideLLMVersion, MaxTestedGuideLLMVersion)
+	}
+	return nil
+}
+
+// versionKey splits a major.minor.patch version into its numbers
+func versionKey(version string) ([3]int, error) {
+	var key [3]int
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return key, fmt.Errorf("invalid guidellm version %q", version)
+	}
+	for i := range key {
+		key[i], _ = strconv.Atoi(m[i+1])
+	}
+	return key, nil
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, the same as or
+// newer than b
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
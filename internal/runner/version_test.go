@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/yourorg/guidellm-runner/internal/api"
+	"github.com/yourorg/guidellm-runner/internal/config"
+)
+
+func TestCheckGuideLLMVersion(t *testing.T) {
+	tests := []struct {
+		output  string
+		version string
+		tested  bool
+	}{
+		{output: "guidellm version 0.5.0\n", version: "0.5.0", tested: true},
+		{output: "0.5.3", version: "0.5.3", tested: true},
+		{output: "guidellm, version 0.4.9", version: "0.4.9", tested: false},
+		{output: "guidellm 0.6.0rc1", version: "0.6.0", tested: false},
+		{output: "guidellm 1.0.0", version: "1.0.0", tested: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			version, err := ParseGuideLLMVersion(tt.output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tt.version {
+				t.Errorf("expected version %s, got %s", tt.version, version)
+			}
+			if err := CheckGuideLLMVersion(version); (err == nil) != tt.tested {
+				t.Errorf("expected tested=%v, got error %v", tt.tested, err)
+			}
+		})
+	}
+
+	if _, err := ParseGuideLLMVersion("command not found"); err == nil {
+		t.Error("expected an error for output without a version")
+	}
+}
+
+func TestDetectVersion(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	cfg := &config.Config{
+		GuideLLMBinary: writeFakeGuidellm(t, `[ "$1" = "--version" ] && echo "guidellm version 0.5.1"`),
+	}
+	runner := New(cfg, logger)
+
+	if got := runner.GuideLLMVersion(); got != "" {
+		t.Errorf("expected no version before detection, got %q", got)
+	}
+	version, err := runner.DetectVersion(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "0.5.1" || runner.GuideLLMVersion() != "0.5.1" {
+		t.Errorf("expected version 0.5.1, got %q (recorded %q)", version, runner.GuideLLMVersion())
+	}
+}
+
+// TestGetVersion verifies that the build information and detected guidellm
+// version are reported by GetVersion and GetStatus
+func TestGetVersion(t *testing.T) {
+	manager := newTestManager(t)
+	manager.SetBuildInfo("v1.2.0", "abc1234", "2025-01-02T03:04:05Z")
+	manager.cfg.GuideLLMBinary = writeFakeGuidellm(t, `echo "guidellm version 0.5.0"`)
+	manager.SetRunner(New(manager.cfg, manager.logger))
+	if _, err := manager.runner.DetectVersion(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := manager.GetVersion()
+	want := api.VersionResponse{
+		Version:         "v1.2.0",
+		GitCommit:       "abc1234",
+		BuildDate:       "2025-01-02T03:04:05Z",
+		GuideLLMVersion: "0.5.0",
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	status := manager.GetStatus()
+	if status.Version != "v1.2.0" || status.GuideLLMVersion != "0.5.0" {
+		t.Errorf("expected status versions v1.2.0 and 0.5.0, got %q and %q", status.Version, status.GuideLLMVersion)
+	}
+}
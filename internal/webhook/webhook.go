@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the payload, keyed with the
+// configured secret and prefixed "sha256="
+const SignatureHeader = "X-Guidellm-Signature"
+
+// Events sent to the webhook, in each payload's event field
+const (
+	EventRunCompleted         = "run.completed"
+	EventThroughputRegression = "throughput.regression"
+)
+
+// RunEvent is the JSON payload sent when a benchmark run completes
+type RunEvent struct {
+	Event              string    `json:"event"`
+	Target             string    `json:"target"`
+	Environment        string    `json:"environment"`
+	Model              string    `json:"model"`
+	RunID              string    `json:"run_id,omitempty"`
+	Trigger            string    `json:"trigger"` // scheduled or manual
+	Success            bool      `json:"success"`
+	Error              string    `json:"error,omitempty"`
+	Timestamp          time.Time `json:"timestamp"`
+	TotalRequests      int       `json:"total_requests"`
+	SuccessfulRequests int       `json:"successful_requests"`
+	FailedRequests     int       `json:"failed_requests"`
+	RequestsPerSec     float64   `json:"requests_per_second"`
+	OutputTokensPerSec float64   `json:"output_tokens_per_second"`
+}
+
+// RegressionEvent is the JSON payload sent when a run's output throughput
+// drops more than the target's threshold below its baseline. Text is a
+// one-line summary, so the payload can be posted straight to a Slack
+// incoming webhook.
+type RegressionEvent struct {
+	Event         string    `json:"event"`
+	Text          string    `json:"text"`
+	Target        string    `json:"target"`
+	Environment   string    `json:"environment"`
+	Model         string    `json:"model"`
+	Timestamp     time.Time `json:"timestamp"`
+	Baseline      float64   `json:"baseline_output_tokens_per_second"`
+	Current       float64   `json:"output_tokens_per_second"`
+	ChangePercent float64   `json:"change_percent"` // negative for a drop
+	Threshold     float64   `json:"threshold_percent"`
+	BaselineRuns  int       `json:"baseline_runs"`
+
+	// BaselineRunID is set when the target's pinned baseline run was
+	// compared against, rather than a rolling window of BaselineRuns runs
+	BaselineRunID string `json:"baseline_run_id,omitempty"`
+}
+
+// retryBackoff is the delay before the first retry, doubling on each
+// further attempt
+const retryBackoff = time.Second
+
+// Notifier delivers run events to a webhook URL in the background. Delivery
+// is best-effort: events are queued for a fixed pool of workers, and dropped
+// rather than blocking the caller when the queue is full.
+type Notifier struct {
+	url        string
+	secret     []byte
+	retries    int
+	backoff    time.Duration
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	wg     sync.WaitGroup
+}
+
+// New creates a notifier for cfg and starts its delivery workers
+func New(cfg config.WebhookConfig, logger *slog.Logger) *Notifier {
+	n := &Notifier{
+		url:        cfg.URL,
+		secret:     []byte(cfg.Secret),
+		retries:    max(cfg.Retries, 0),
+		backoff:    retryBackoff,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+		logger:     logger.With("component", "webhook"),
+		queue:      make(chan []byte, max(cfg.QueueSize, 0)),
+	}
+
+	for range max(cfg.Workers, 1) {
+		n.wg.Add(1)
+		go n.worker()
+	}
+	return n
+}
+
+// Notify queues event (a RunEvent or RegressionEvent) for delivery without
+// blocking. The event is dropped if the queue is full or the notifier has
+// been closed.
+func (n *Notifier) Notify(event any) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.logger.Error("failed to encode webhook event", "error", err)
+		return
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return
+	}
+
+	select {
+	case n.queue <- body:
+	default:
+		metrics.WebhookDeliveries.WithLabelValues("dropped").Inc()
+		n.logger.Warn("webhook queue full, dropping event")
+	}
+}
+
+// Close stops accepting events and waits for queued ones to be delivered,
+// giving up when ctx is done
+func (n *Notifier) Close(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker delivers queued events until the queue is closed
+func (n *Notifier) worker() {
+	defer n.wg.Done()
+
+	for body := range n.queue {
+		n.deliver(body)
+	}
+}
+
+// deliver posts body, retrying with backoff on failure
+func (n *Notifier) deliver(body []byte) {
+	var err error
+	backoff := n.backoff
+	for attempt := 0; attempt <= n.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = n.post(body); err == nil {
+			metrics.WebhookDeliveries.WithLabelValues("delivered").Inc()
+			return
+		}
+		n.logger.Debug("webhook delivery attempt failed", "attempt", attempt+1, "error", err)
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues("failed").Inc()
+	n.logger.Warn("webhook delivery failed", "attempts", n.retries+1, "error", err)
+}
+
+// post sends one delivery attempt; any non-2xx response is a failure
+func (n *Notifier) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yourorg/guidellm-runner/internal/config"
+	"github.com/yourorg/guidellm-runner/internal/metrics"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestNotifierSignsAndRetries(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan RunEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so the delivery is retried
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), Sign([]byte("s3cret"), body); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+		var event RunEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received <- event
+	}))
+	defer srv.Close()
+
+	n := New(config.WebhookConfig{URL: srv.URL, Secret: "s3cret", Timeout: 5, Retries: 1, Workers: 1, QueueSize: 1}, testLogger())
+	n.backoff = time.Millisecond
+	n.Notify(RunEvent{Event: EventRunCompleted, Target: "llama", Success: true, TotalRequests: 10})
+
+	select {
+	case event := <-received:
+		if event.Target != "llama" || event.TotalRequests != 10 || !event.Success {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestNotifierDropsWhenQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	dropped := metrics.WebhookDeliveries.WithLabelValues("dropped")
+	before := testutil.ToFloat64(dropped)
+
+	n := New(config.WebhookConfig{URL: srv.URL, Timeout: 5, Workers: 1, QueueSize: 1}, testLogger())
+	// The first event occupies the worker, the second fills the queue
+	n.Notify(RunEvent{Target: "a"})
+	deadline := time.Now().Add(5 * time.Second)
+	for len(n.queue) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	n.Notify(RunEvent{Target: "b"})
+	n.Notify(RunEvent{Target: "c"})
+
+	if got := testutil.ToFloat64(dropped) - before; got != 1 {
+		t.Errorf("expected 1 dropped event, got %g", got)
+	}
+
+	close(release)
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Events after close are ignored
+	n.Notify(RunEvent{Target: "d"})
+}